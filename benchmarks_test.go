@@ -121,6 +121,29 @@ func Benchmark404Many(B *testing.B) {
 	runRequest(B, router, "GET", "/viewfake")
 }
 
+func newStaticRoutesRouter() *Engine {
+	router := New()
+	for _, path := range []string{
+		"/health", "/metrics", "/v1/ping", "/v1/users", "/v1/users/:id", "/v1/users/:id/posts",
+		"/v1/posts", "/v1/posts/:id", "/v1/posts/:id/comments", "/v1/pings", "/v1/static/*filepath",
+	} {
+		router.GET(path, func(c *Context) {})
+	}
+	return router
+}
+
+func BenchmarkStaticRoute(B *testing.B) {
+	runRequest(B, newStaticRoutesRouter(), "GET", "/v1/ping")
+}
+
+// BenchmarkStaticRouteTree serves the same request as BenchmarkStaticRoute
+// without the static routes fast path, walking the tree instead.
+func BenchmarkStaticRouteTree(B *testing.B) {
+	router := newStaticRoutesRouter()
+	router.staticRoutes = nil
+	runRequest(B, router, "GET", "/v1/ping")
+}
+
 type mockWriter struct {
 	headers http.Header
 }
@@ -20,6 +20,7 @@ const (
 	MIMEPROTOBUF          = "application/x-protobuf"
 	MIMEYAML              = "application/x-yaml"
 	MIMETOML              = "application/toml"
+	MIMENDJSON            = "application/x-ndjson"
 )
 
 // Binding describes the interface which needs to be implemented for binding the
@@ -75,27 +76,42 @@ var (
 	Query         = queryBinding{}
 	FormPost      = formPostBinding{}
 	FormMultipart = formMultipartBinding{}
-	ProtoBuf      = protobufBinding{}
 	YAML          = yamlBinding{}
 	Uri           = uriBinding{}
 	Header        = headerBinding{}
 	TOML          = tomlBinding{}
+	HexForm       = hexFormBinding{}
+	NDJSON        = ndjsonBinding{}
+
+	// StrictJSON is the JSON binding that always rejects object keys not matching
+	// a field of the destination, see EnableDecoderDisallowUnknownFields.
+	StrictJSON = jsonBinding{disallowUnknownFields: true}
+
+	// UniqueKeysJSON is the JSON binding that rejects a body repeating a key in one of its
+	// objects with a *DuplicateKeyError, instead of keeping the last value.
+	UniqueKeysJSON = jsonBinding{rejectDuplicateKeys: true}
+
+	// FormQueryFirst is the Form binding giving the query string precedence over the
+	// request body when a parameter is in both; Form gives the body precedence.
+	FormQueryFirst = formBinding{queryFirst: true}
 )
 
 // Default returns the appropriate Binding instance based on the HTTP method
-// and the content type.
+// and the content type. Parameters of the content type such as charset are ignored.
 func Default(method, contentType string) Binding {
 	if method == "GET" {
 		return Form
 	}
 
-	switch contentType {
+	switch mediaType(contentType) {
 	case MIMEJSON:
 		return JSON
+	case MIMENDJSON:
+		return NDJSON
 	case MIMEXML, MIMEXML2:
 		return XML
 	case MIMEPROTOBUF:
-		return ProtoBuf
+		return protoBufDefault()
 	case MIMEYAML:
 		return YAML
 	case MIMEMultipartPOSTForm:
@@ -108,6 +124,9 @@ func Default(method, contentType string) Binding {
 }
 
 func validate(obj any) error {
+	if err := setDefaults(obj); err != nil {
+		return err
+	}
 	if Validator == nil {
 		return nil
 	}
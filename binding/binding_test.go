@@ -18,9 +18,8 @@ import (
 	"testing"
 	"time"
 
-	"github.com/gin-gonic/gin/testdata/protoexample"
+	"github.com/go-playground/validator/v10"
 	"github.com/stretchr/testify/assert"
-	"google.golang.org/protobuf/proto"
 )
 
 type appkey struct {
@@ -159,14 +158,35 @@ func TestBindingDefault(t *testing.T) {
 	assert.Equal(t, FormMultipart, Default("POST", MIMEMultipartPOSTForm))
 	assert.Equal(t, FormMultipart, Default("PUT", MIMEMultipartPOSTForm))
 
-	assert.Equal(t, ProtoBuf, Default("POST", MIMEPROTOBUF))
-	assert.Equal(t, ProtoBuf, Default("PUT", MIMEPROTOBUF))
-
 	assert.Equal(t, YAML, Default("POST", MIMEYAML))
 	assert.Equal(t, YAML, Default("PUT", MIMEYAML))
 
 	assert.Equal(t, TOML, Default("POST", MIMETOML))
 	assert.Equal(t, TOML, Default("PUT", MIMETOML))
+
+	assert.Equal(t, NDJSON, Default("POST", MIMENDJSON))
+	assert.Equal(t, NDJSON, Default("PUT", MIMENDJSON))
+}
+
+func TestBindingDefaultContentTypeParams(t *testing.T) {
+	assert.Equal(t, JSON, Default("POST", "application/json; charset=utf-8"))
+	assert.Equal(t, JSON, Default("POST", "Application/JSON;charset=UTF-8"))
+	assert.Equal(t, JSON, Default("POST", " application/json ; charset=utf-8"))
+	assert.Equal(t, XML, Default("POST", "application/xml; charset=ISO-8859-1"))
+	assert.Equal(t, XML, Default("PUT", "text/xml;charset=utf-8"))
+	assert.Equal(t, Form, Default("POST", "application/x-www-form-urlencoded; charset=utf-8"))
+	assert.Equal(t, FormMultipart, Default("POST", "multipart/form-data; boundary=----WebKitFormBoundary7MA4YWxkTrZu0gW"))
+	// an invalid parameter does not hide the media type
+	assert.Equal(t, JSON, Default("POST", "application/json; charset"))
+	assert.Equal(t, Form, Default("POST", "application/json-patch+json; charset=utf-8"))
+}
+
+func TestBindingJSONWithCharset(t *testing.T) {
+	req := requestWithBody(http.MethodPost, "/", `{"foo": "bar"}`)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	var obj FooStruct
+	assert.NoError(t, Default(req.Method, req.Header.Get("Content-Type")).Bind(req, &obj))
+	assert.Equal(t, "bar", obj.Foo)
 }
 
 func TestBindingJSONNilBody(t *testing.T) {
@@ -420,6 +440,40 @@ func TestBindingQueryBoolFail(t *testing.T) {
 		"bool_foo=unused", "")
 }
 
+func TestBindingHexForm(t *testing.T) {
+	b := HexForm
+	assert.Equal(t, "hexform", b.Name())
+
+	var obj struct {
+		ID      int64   `form:"id,hexstring"`
+		Owner   uint64  `form:"owner,hexstring"`
+		Parent  *int64  `form:"parent,hexstring"`
+		IDs     []int64 `form:"ids,hexstring"`
+		Missing int64   `form:"missing,hexstring,default=0xff"`
+		Name    string  `form:"name,hexstring"`
+	}
+	req := requestWithBody(http.MethodPost, "/?id=000000000000007b&owner=ffffffffffffffff&ids=ff&ids=12345678901234567",
+		"parent=-0x10&name=foo")
+	req.Header.Add("Content-Type", MIMEPOSTForm)
+	assert.NoError(t, b.Bind(req, &obj))
+	assert.Equal(t, int64(0x7b), obj.ID)
+	assert.Equal(t, uint64(0xffffffffffffffff), obj.Owner)
+	assert.Equal(t, int64(-16), *obj.Parent)
+	assert.Equal(t, []int64{0xff, 12345678901234567}, obj.IDs)
+	assert.Equal(t, int64(0xff), obj.Missing)
+	assert.Equal(t, "foo", obj.Name)
+
+	req = requestWithBody(http.MethodGet, "/?id=zz", "")
+	assert.Error(t, b.Bind(req, &obj))
+
+	SetHexDecodeMode(HexDecodePrefix)
+	defer SetHexDecodeMode(HexDecodeAuto)
+	req = requestWithBody(http.MethodGet, "/?id=10&ids=0x10", "")
+	assert.NoError(t, b.Bind(req, &obj))
+	assert.Equal(t, int64(10), obj.ID)
+	assert.Equal(t, []int64{16}, obj.IDs)
+}
+
 func TestBindingQueryStringMap(t *testing.T) {
 	b := Query
 
@@ -702,30 +756,6 @@ func TestBindingFormMultipartForMapFail(t *testing.T) {
 	assert.Error(t, err)
 }
 
-func TestBindingProtoBuf(t *testing.T) {
-	test := &protoexample.Test{
-		Label: proto.String("yes"),
-	}
-	data, _ := proto.Marshal(test)
-
-	testProtoBodyBinding(t,
-		ProtoBuf, "protobuf",
-		"/", "/",
-		string(data), string(data[1:]))
-}
-
-func TestBindingProtoBufFail(t *testing.T) {
-	test := &protoexample.Test{
-		Label: proto.String("yes"),
-	}
-	data, _ := proto.Marshal(test)
-
-	testProtoBodyBindingFail(t,
-		ProtoBuf, "protobuf",
-		"/", "/",
-		string(data), string(data[1:]))
-}
-
 func TestValidationFails(t *testing.T) {
 	var obj FooStruct
 	req := requestWithBody("POST", "/", `{"bar": "foo"}`)
@@ -791,6 +821,49 @@ func TestHeaderBinding(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestHeaderBindingTyped(t *testing.T) {
+	type auth struct {
+		User string `header:"X-User" binding:"required"`
+	}
+	type tHeader struct {
+		Tenant  int64         `header:"x-tenant-id,hexstring" binding:"required"`
+		IDs     []uint64      `header:"X-Ids,hexstring"`
+		Count   int64         `header:"X-Count"`
+		Timeout time.Duration `header:"X-Timeout"`
+		Auth    auth
+		Token   *string `binding:"required"`
+	}
+
+	req := requestWithBody("GET", "/", "")
+	req.Header.Set("X-Tenant-Id", "ff")
+	req.Header.Add("X-Ids", "a")
+	req.Header.Add("X-Ids", "0x10")
+	req.Header.Set("X-Count", "10")
+	req.Header.Set("X-Timeout", "2s")
+	req.Header.Set("X-User", "gin")
+	req.Header.Set("Token", "t")
+	var h tHeader
+	assert.NoError(t, Header.Bind(req, &h))
+	assert.Equal(t, int64(255), h.Tenant)
+	assert.Equal(t, []uint64{10, 16}, h.IDs)
+	assert.Equal(t, int64(10), h.Count)
+	assert.Equal(t, 2*time.Second, h.Timeout)
+	assert.Equal(t, "gin", h.Auth.User)
+
+	err := Header.Bind(requestWithBody("GET", "/", ""), &tHeader{})
+	var missing *MissingHeaderError
+	assert.ErrorAs(t, err, &missing)
+	assert.Equal(t, []string{"X-Tenant-Id", "X-User", "Token"}, missing.Headers)
+	var verrs validator.ValidationErrors
+	assert.ErrorAs(t, err, &verrs)
+
+	req = requestWithBody("GET", "/", "")
+	req.Header.Set("X-Count", "ten")
+	err = Header.Bind(req, &tHeader{})
+	assert.ErrorContains(t, err, "header X-Count: ")
+	assert.False(t, errors.As(err, &missing))
+}
+
 func TestUriBinding(t *testing.T) {
 	b := Uri
 	assert.Equal(t, "uri", b.Name())
@@ -834,6 +907,30 @@ func TestUriInnerBinding(t *testing.T) {
 	assert.Equal(t, tag.S.Age, expectedAge)
 }
 
+func TestUriBindingHexString(t *testing.T) {
+	var obj struct {
+		ID      int64         `uri:"id,hexstring"`
+		IDs     []uint64      `uri:"ids,hexstring"`
+		Num     int64         `uri:"num"`
+		Default int64         `uri:"missing,default=42"`
+		Timeout time.Duration `uri:"timeout"`
+	}
+	m := map[string][]string{
+		"id":      {"00000000000003e8"},
+		"ids":     {"a", "0x10"},
+		"num":     {"1000"},
+		"timeout": {"1s"},
+	}
+	assert.NoError(t, Uri.BindUri(m, &obj))
+	assert.Equal(t, int64(1000), obj.ID)
+	assert.Equal(t, []uint64{10, 16}, obj.IDs)
+	assert.Equal(t, int64(1000), obj.Num)
+	assert.Equal(t, int64(42), obj.Default)
+	assert.Equal(t, time.Second, obj.Timeout)
+
+	assert.Error(t, Uri.BindUri(map[string][]string{"num": {"3e8"}}, &obj))
+}
+
 func testFormBindingEmbeddedStruct(t *testing.T, method, path, badPath, body, badBody string) {
 	b := Form
 	assert.Equal(t, "form", b.Name())
@@ -1317,54 +1414,6 @@ func testBodyBindingFail(t *testing.T, b Binding, name, path, badPath, body, bad
 	assert.Error(t, err)
 }
 
-func testProtoBodyBinding(t *testing.T, b Binding, name, path, badPath, body, badBody string) {
-	assert.Equal(t, name, b.Name())
-
-	obj := protoexample.Test{}
-	req := requestWithBody("POST", path, body)
-	req.Header.Add("Content-Type", MIMEPROTOBUF)
-	err := b.Bind(req, &obj)
-	assert.NoError(t, err)
-	assert.Equal(t, "yes", *obj.Label)
-
-	obj = protoexample.Test{}
-	req = requestWithBody("POST", badPath, badBody)
-	req.Header.Add("Content-Type", MIMEPROTOBUF)
-	err = ProtoBuf.Bind(req, &obj)
-	assert.Error(t, err)
-}
-
-type hook struct{}
-
-func (h hook) Read([]byte) (int, error) {
-	return 0, errors.New("error")
-}
-
-func testProtoBodyBindingFail(t *testing.T, b Binding, name, path, badPath, body, badBody string) {
-	assert.Equal(t, name, b.Name())
-
-	obj := protoexample.Test{}
-	req := requestWithBody("POST", path, body)
-
-	req.Body = io.NopCloser(&hook{})
-	req.Header.Add("Content-Type", MIMEPROTOBUF)
-	err := b.Bind(req, &obj)
-	assert.Error(t, err)
-
-	invalidobj := FooStruct{}
-	req.Body = io.NopCloser(strings.NewReader(`{"msg":"hello"}`))
-	req.Header.Add("Content-Type", MIMEPROTOBUF)
-	err = b.Bind(req, &invalidobj)
-	assert.Error(t, err)
-	assert.Equal(t, err.Error(), "obj is not ProtoMessage")
-
-	obj = protoexample.Test{}
-	req = requestWithBody("POST", badPath, badBody)
-	req.Header.Add("Content-Type", MIMEPROTOBUF)
-	err = ProtoBuf.Bind(req, &obj)
-	assert.Error(t, err)
-}
-
 func requestWithBody(method, path, body string) (req *http.Request) {
 	req, _ = http.NewRequest(method, path, bytes.NewBufferString(body))
 	return
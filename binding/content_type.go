@@ -0,0 +1,21 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package binding
+
+import (
+	"mime"
+	"strings"
+)
+
+// mediaType returns the lower case media type of a Content-Type header, without its
+// parameters, e.g. "application/json" for "Application/JSON; charset=utf-8".
+func mediaType(contentType string) string {
+	typ, _, err := mime.ParseMediaType(contentType)
+	if err == nil || err == mime.ErrInvalidMediaParameter {
+		return typ
+	}
+	typ, _, _ = strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(typ))
+}
@@ -0,0 +1,90 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package binding
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// setDefaults fills the zero-valued fields of obj that have a `default:"..."` tag, e.g.
+// `default:"20"`, walking nested structs and the elements of slices. The value is coerced
+// like a query value, int64 fields are decimal. Fields with a `binding:"required"` rule are
+// left alone so that validation still reports them.
+func setDefaults(obj any) error {
+	return setDefaultsValue(reflect.ValueOf(obj))
+}
+
+func setDefaultsValue(value reflect.Value) error {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		return setDefaultsValue(value.Elem())
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := setDefaultsValue(value.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		if value.Type() == timeType {
+			return nil
+		}
+		typ := value.Type()
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fv := value.Field(i)
+			def, ok := field.Tag.Lookup("default")
+			if !ok || !fv.CanSet() {
+				if err := setDefaultsValue(fv); err != nil {
+					return err
+				}
+				continue
+			}
+			if !fv.IsZero() || isRequired(field) {
+				continue
+			}
+			if err := setDefault(def, fv, field); err != nil {
+				return fmt.Errorf("default of %s: %w", field.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func setDefault(def string, value reflect.Value, field reflect.StructField) error {
+	if value.Kind() == reflect.Ptr {
+		value.Set(reflect.New(value.Type().Elem()))
+		value = value.Elem()
+	}
+	if value.Kind() == reflect.Int64 && value.Type() != durationType {
+		intVal, err := strconv.ParseInt(def, 10, 64)
+		if err == nil {
+			value.SetInt(intVal)
+		}
+		return err
+	}
+	return setWithProperType(def, value, field)
+}
+
+func isRequired(field reflect.StructField) bool {
+	for rules := field.Tag.Get("binding"); rules != ""; {
+		var rule string
+		rule, rules = head(rules, ",")
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package binding
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type defaultPaging struct {
+	Page     int           `form:"page" json:"page" default:"1"`
+	PageSize int64         `form:"page_size" json:"page_size" default:"20"`
+	Sort     string        `form:"sort" json:"sort" default:"desc"`
+	Deleted  *bool         `form:"deleted" json:"deleted" default:"false"`
+	Timeout  time.Duration `form:"timeout" json:"timeout" default:"5s"`
+	Cursor   string        `form:"cursor" json:"cursor" default:"start" binding:"required"`
+	Filter   struct {
+		Status string `form:"status" json:"status" default:"open"`
+	} `json:"filter"`
+}
+
+func TestDefaultTagQuery(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "/?cursor=c1", nil)
+	var obj defaultPaging
+	require.NoError(t, Query.Bind(req, &obj))
+	assert.Equal(t, 1, obj.Page)
+	assert.Equal(t, int64(20), obj.PageSize)
+	assert.Equal(t, "desc", obj.Sort)
+	if assert.NotNil(t, obj.Deleted) {
+		assert.False(t, *obj.Deleted)
+	}
+	assert.Equal(t, 5*time.Second, obj.Timeout)
+	assert.Equal(t, "c1", obj.Cursor)
+	assert.Equal(t, "open", obj.Filter.Status)
+
+	req, _ = http.NewRequest(http.MethodGet, "/?cursor=c1&page=3&page_size=0000000000000050&sort=asc&deleted=true&timeout=1m&status=closed", nil)
+	obj = defaultPaging{}
+	require.NoError(t, Query.Bind(req, &obj))
+	assert.Equal(t, 3, obj.Page)
+	assert.Equal(t, int64(0x50), obj.PageSize)
+	assert.Equal(t, "asc", obj.Sort)
+	assert.True(t, *obj.Deleted)
+	assert.Equal(t, time.Minute, obj.Timeout)
+	assert.Equal(t, "closed", obj.Filter.Status)
+}
+
+func TestDefaultTagJSON(t *testing.T) {
+	var obj defaultPaging
+	require.NoError(t, JSON.BindBody([]byte(`{"cursor":"c1","page":2,"filter":{}}`), &obj))
+	assert.Equal(t, 2, obj.Page)
+	assert.Equal(t, int64(20), obj.PageSize)
+	assert.Equal(t, "desc", obj.Sort)
+	assert.Equal(t, "open", obj.Filter.Status)
+
+	obj = defaultPaging{}
+	require.NoError(t, JSON.BindBody([]byte(`{"cursor":"c1","sort":"asc","filter":{"status":"closed"}}`), &obj))
+	assert.Equal(t, "asc", obj.Sort)
+	assert.Equal(t, "closed", obj.Filter.Status)
+}
+
+func TestDefaultTagRequired(t *testing.T) {
+	var obj defaultPaging
+	err := JSON.BindBody([]byte(`{}`), &obj)
+	assert.Error(t, err)
+	assert.Empty(t, obj.Cursor)
+	assert.Equal(t, 1, obj.Page)
+}
+
+func TestDefaultTagInvalid(t *testing.T) {
+	var obj struct {
+		Page int `json:"page" default:"one"`
+	}
+	err := JSON.BindBody([]byte(`{}`), &obj)
+	assert.ErrorContains(t, err, "default of Page")
+}
+
+func TestDefaultTagSlice(t *testing.T) {
+	var list []defaultPaging
+	require.NoError(t, JSON.BindBody([]byte(`[{"cursor":"a"},{"cursor":"b","page":4}]`), &list))
+	require.Len(t, list, 2)
+	assert.Equal(t, 1, list[0].Page)
+	assert.Equal(t, 4, list[1].Page)
+	assert.Equal(t, int64(20), list[1].PageSize)
+}
@@ -18,29 +18,54 @@ type defaultValidator struct {
 	validate *validator.Validate
 }
 
+// SliceValidationError holds the validation errors of the invalid elements of a slice or array.
+// ValidateStruct returns each of them as a *SliceElementError giving the index of the element.
 type SliceValidationError []error
 
 // Error concatenates all error elements in SliceValidationError into a single string separated by \n.
 func (err SliceValidationError) Error() string {
-	n := len(err)
-	switch n {
-	case 0:
-		return ""
-	default:
-		var b strings.Builder
-		if err[0] != nil {
-			fmt.Fprintf(&b, "[%d]: %s", 0, err[0].Error())
+	var b strings.Builder
+	for i, e := range err {
+		if e == nil {
+			continue
 		}
-		if n > 1 {
-			for i := 1; i < n; i++ {
-				if err[i] != nil {
-					b.WriteString("\n")
-					fmt.Fprintf(&b, "[%d]: %s", i, err[i].Error())
-				}
-			}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		index := i
+		if ee, ok := e.(*SliceElementError); ok {
+			index = ee.Index
 		}
-		return b.String()
+		fmt.Fprintf(&b, "[%d]: %s", index, e.Error())
 	}
+	return b.String()
+}
+
+// SliceElementError is the validation error of the element at Index of a slice or array.
+type SliceElementError struct {
+	Index int
+	Err   error
+}
+
+func (e *SliceElementError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *SliceElementError) Unwrap() error {
+	return e.Err
+}
+
+// structFieldError is a validator.FieldError of a field of the struct typ.
+// It behaves like the error it embeds; the struct type lets gin.ValidationMessages
+// name the field after its json tags.
+type structFieldError struct {
+	validator.FieldError
+	typ reflect.Type
+}
+
+// StructType returns the type of the validated struct.
+func (e structFieldError) StructType() reflect.Type {
+	return e.typ
 }
 
 var _ StructValidator = (*defaultValidator)(nil)
@@ -62,7 +87,7 @@ func (v *defaultValidator) ValidateStruct(obj any) error {
 		validateRet := make(SliceValidationError, 0)
 		for i := 0; i < count; i++ {
 			if err := v.ValidateStruct(value.Index(i).Interface()); err != nil {
-				validateRet = append(validateRet, err)
+				validateRet = append(validateRet, &SliceElementError{Index: i, Err: err})
 			}
 		}
 		if len(validateRet) == 0 {
@@ -77,7 +102,14 @@ func (v *defaultValidator) ValidateStruct(obj any) error {
 // validateStruct receives struct type
 func (v *defaultValidator) validateStruct(obj any) error {
 	v.lazyinit()
-	return v.validate.Struct(obj)
+	err := v.validate.Struct(obj)
+	if verrs, ok := err.(validator.ValidationErrors); ok {
+		typ := reflect.TypeOf(obj)
+		for i, fe := range verrs {
+			verrs[i] = structFieldError{FieldError: fe, typ: typ}
+		}
+	}
+	return err
 }
 
 // Engine returns the underlying validator engine which powers the default
@@ -93,5 +125,28 @@ func (v *defaultValidator) lazyinit() {
 	v.once.Do(func() {
 		v.validate = validator.New()
 		v.validate.SetTagName("binding")
+		v.validate.RegisterValidation("hexid", isHexID) //nolint: errcheck
 	})
 }
+
+// isHexID implements the hexid rule: a string id as written by the hexstring=16 tag,
+// i.e. 16 lower-case hex digits, or '-' and 15 digits for a negative id.
+func isHexID(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	if field.Kind() != reflect.String {
+		return false
+	}
+	s := field.String()
+	if len(s) != 16 {
+		return false
+	}
+	if s[0] == '-' {
+		s = s[1:]
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -6,7 +6,12 @@ package binding
 
 import (
 	"errors"
+	"sync"
 	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSliceValidationError(t *testing.T) {
@@ -17,6 +22,7 @@ func TestSliceValidationError(t *testing.T) {
 	}{
 		{"has nil elements", SliceValidationError{errors.New("test error"), nil}, "[0]: test error"},
 		{"has zero elements", SliceValidationError{}, ""},
+		{"has leading nil elements", SliceValidationError{nil, errors.New("test error")}, "[1]: test error"},
 		{"has one element", SliceValidationError{errors.New("test one error")}, "[0]: test one error"},
 		{"has two elements",
 			SliceValidationError{
@@ -36,6 +42,13 @@ func TestSliceValidationError(t *testing.T) {
 			},
 			"[0]: first error\n[1]: second error\n[5]: last error",
 		},
+		{"has element errors",
+			SliceValidationError{
+				&SliceElementError{Index: 2, Err: errors.New("first error")},
+				&SliceElementError{Index: 5, Err: errors.New("last error")},
+			},
+			"[2]: first error\n[5]: last error",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -86,3 +99,51 @@ func TestDefaultValidator(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultValidatorSliceErrors(t *testing.T) {
+	type item struct {
+		Name string `json:"name" binding:"required"`
+	}
+	err := (&defaultValidator{}).ValidateStruct([]item{{"a"}, {}, {"c"}, {}})
+
+	var sliceErr SliceValidationError
+	require.ErrorAs(t, err, &sliceErr)
+	require.Len(t, sliceErr, 2)
+	for i, index := range []int{1, 3} {
+		var elemErr *SliceElementError
+		require.ErrorAs(t, sliceErr[i], &elemErr)
+		assert.Equal(t, index, elemErr.Index)
+		assert.Equal(t, "Key: 'item.Name' Error:Field validation for 'Name' failed on the 'required' tag", sliceErr[i].Error())
+
+		var verrs validator.ValidationErrors
+		require.ErrorAs(t, sliceErr[i], &verrs)
+		assert.Equal(t, "Name", verrs[0].Field())
+		assert.Equal(t, "item.Name", verrs[0].Namespace())
+	}
+	assert.Equal(t, "[1]: Key: 'item.Name' Error:Field validation for 'Name' failed on the 'required' tag\n"+
+		"[3]: Key: 'item.Name' Error:Field validation for 'Name' failed on the 'required' tag", err.Error())
+}
+
+func TestDefaultValidatorHexID(t *testing.T) {
+	type hexStruct struct {
+		ID string `binding:"hexid"`
+	}
+	v := &defaultValidator{}
+	for _, id := range []string{"00000000000000ff", "7fffffffffffffff", "-000000000000001"} {
+		assert.NoError(t, v.ValidateStruct(hexStruct{id}), id)
+	}
+	for _, id := range []string{"", "ff", "00000000000000FF", "00000000000000fg", "0x000000000000ff", "000000000000000ff"} {
+		assert.Error(t, v.ValidateStruct(hexStruct{id}), id)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, (&defaultValidator{}).ValidateStruct(hexStruct{"00000000000000ff"}))
+			assert.NoError(t, v.ValidateStruct(hexStruct{"00000000000000ff"}))
+		}()
+	}
+	wg.Wait()
+}
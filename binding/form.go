@@ -7,11 +7,15 @@ package binding
 import (
 	"errors"
 	"net/http"
+	"net/url"
 )
 
 const defaultMemory = 32 << 20
 
-type formBinding struct{}
+type formBinding struct {
+	// queryFirst puts the values of the query string before those of the body.
+	queryFirst bool
+}
 type formPostBinding struct{}
 type formMultipartBinding struct{}
 
@@ -19,19 +23,39 @@ func (formBinding) Name() string {
 	return "form"
 }
 
-func (formBinding) Bind(req *http.Request, obj any) error {
+// Bind binds the parameters of the query string and of the url-encoded or multipart body
+// of req. A field takes the first value of its parameter: the body's one, or the query
+// string's one with FormQueryFirst.
+func (b formBinding) Bind(req *http.Request, obj any) error {
 	if err := req.ParseForm(); err != nil {
 		return err
 	}
 	if err := req.ParseMultipartForm(defaultMemory); err != nil && !errors.Is(err, http.ErrNotMultipart) {
 		return err
 	}
-	if err := mapForm(obj, req.Form); err != nil {
+	if err := mapForm(obj, b.values(req)); err != nil {
 		return err
 	}
 	return validate(obj)
 }
 
+// values merges the body and query string parameters of the parsed req in the order of b.
+// req.Form is not used as is: it has the query string after a url-encoded body but
+// before a multipart one.
+func (b formBinding) values(req *http.Request) url.Values {
+	first, second := req.PostForm, req.URL.Query()
+	if b.queryFirst {
+		first, second = second, first
+	}
+	values := make(url.Values, len(first)+len(second))
+	for _, vs := range []url.Values{first, second} {
+		for k, v := range vs {
+			values[k] = append(values[k], v...)
+		}
+	}
+	return values
+}
+
 func (formPostBinding) Name() string {
 	return "form-urlencoded"
 }
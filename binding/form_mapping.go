@@ -27,7 +27,7 @@ var (
 )
 
 func mapURI(ptr any, m map[string][]string) error {
-	return mapFormByTag(ptr, m, "uri")
+	return mapFormBySetter(ptr, m, uriSource(m), "uri")
 }
 
 func mapForm(ptr any, form map[string][]string) error {
@@ -41,6 +41,10 @@ func MapFormWithTag(ptr any, form map[string][]string, tag string) error {
 var emptyField = reflect.StructField{}
 
 func mapFormByTag(ptr any, form map[string][]string, tag string) error {
+	return mapFormBySetter(ptr, form, formSource(form), tag)
+}
+
+func mapFormBySetter(ptr any, form map[string][]string, setter setter, tag string) error {
 	// Check if ptr is a map
 	ptrVal := reflect.ValueOf(ptr)
 	var pointed any
@@ -56,7 +60,7 @@ func mapFormByTag(ptr any, form map[string][]string, tag string) error {
 		return setFormMap(ptr, form)
 	}
 
-	return mappingByPtr(ptr, formSource(form), tag)
+	return mappingByPtr(ptr, setter, tag)
 }
 
 // setter tries to set value on a walking by fields of a struct
@@ -97,6 +101,11 @@ func mapping(value reflect.Value, field reflect.StructField, setter setter, tag
 			return false, err
 		}
 		if isNew && isSet {
+			if !value.CanSet() {
+				// a nil embedded pointer to an unexported struct type can't be allocated,
+				// encoding/json rejects it likewise
+				return false, fmt.Errorf("cannot set embedded pointer to unexported struct: %v", value.Type().Elem())
+			}
 			value.Set(vPtr)
 		}
 		return isSet, nil
@@ -135,6 +144,7 @@ func mapping(value reflect.Value, field reflect.StructField, setter setter, tag
 type setOptions struct {
 	isDefaultExists bool
 	defaultValue    string
+	isHexString     bool
 }
 
 func tryToSetValue(value reflect.Value, field reflect.StructField, setter setter, tag string) (bool, error) {
@@ -155,9 +165,12 @@ func tryToSetValue(value reflect.Value, field reflect.StructField, setter setter
 	for len(opts) > 0 {
 		opt, opts = head(opts, ",")
 
-		if k, v := head(opt, "="); k == "default" {
+		switch k, v := head(opt, "="); k {
+		case "default":
 			setOpt.isDefaultExists = true
 			setOpt.defaultValue = v
+		case "hexstring":
+			setOpt.isHexString = true
 		}
 	}
 
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMappingBaseTypes(t *testing.T) {
@@ -302,6 +303,56 @@ func TestMappingPtrField(t *testing.T) {
 	assert.EqualValues(t, 2, req2.Items[1].Key)
 }
 
+type Pagination struct {
+	Page int `form:"page" json:"page"`
+	Size int `form:"size" json:"size"`
+}
+
+type pagination struct {
+	Page int `form:"page"`
+}
+
+func TestMappingEmbeddedStruct(t *testing.T) {
+	var byValue struct {
+		Pagination
+		Name string `form:"name"`
+	}
+	err := mappingByPtr(&byValue, formSource{"page": {"2"}, "size": {"50"}, "name": {"x"}}, "form")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, byValue.Page)
+	assert.Equal(t, 50, byValue.Size)
+	assert.Equal(t, "x", byValue.Name)
+
+	var byPtr struct {
+		*Pagination
+	}
+	err = mappingByPtr(&byPtr, formSource{"page": {"2"}, "size": {"50"}}, "form")
+	assert.NoError(t, err)
+	require.NotNil(t, byPtr.Pagination)
+	assert.Equal(t, 2, byPtr.Page)
+	assert.Equal(t, 50, byPtr.Size)
+
+	// left nil when none of its fields is set
+	byPtr.Pagination = nil
+	err = mappingByPtr(&byPtr, formSource{"name": {"x"}}, "form")
+	assert.NoError(t, err)
+	assert.Nil(t, byPtr.Pagination)
+
+	var unexportedValue struct {
+		pagination
+	}
+	err = mappingByPtr(&unexportedValue, formSource{"page": {"2"}}, "form")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, unexportedValue.Page)
+
+	var unexportedPtr struct {
+		*pagination
+	}
+	err = mappingByPtr(&unexportedPtr, formSource{"page": {"2"}}, "form")
+	assert.EqualError(t, err, "cannot set embedded pointer to unexported struct: binding.pagination")
+	assert.Nil(t, unexportedPtr.pagination)
+}
+
 func TestMappingMapField(t *testing.T) {
 	var s struct {
 		M map[string]int
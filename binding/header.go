@@ -5,9 +5,14 @@
 package binding
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"net/textproto"
 	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
 )
 
 type headerBinding struct{}
@@ -22,7 +27,7 @@ func (headerBinding) Bind(req *http.Request, obj any) error {
 		return err
 	}
 
-	return validate(obj)
+	return missingHeaders(obj, validate(obj))
 }
 
 func mapHeader(ptr any, h map[string][]string) error {
@@ -33,6 +38,70 @@ type headerSource map[string][]string
 
 var _ setter = headerSource(nil)
 
+// TrySet sets a field from the header named by its tag, see setTyped. The error of a
+// value that can not be parsed names the header.
 func (hs headerSource) TrySet(value reflect.Value, field reflect.StructField, tagValue string, opt setOptions) (bool, error) {
-	return setByForm(value, field, hs, textproto.CanonicalMIMEHeaderKey(tagValue), opt)
+	key := textproto.CanonicalMIMEHeaderKey(tagValue)
+	isSet, err := setTyped(value, field, hs, key, opt)
+	if err != nil {
+		return isSet, fmt.Errorf("header %s: %w", key, err)
+	}
+	return isSet, err
+}
+
+// MissingHeaderError reports the headers of fields with a failing "required" rule.
+// It wraps the error of the validator.
+type MissingHeaderError struct {
+	Headers []string
+	Err     error
+}
+
+func (e *MissingHeaderError) Error() string {
+	return "missing required header " + strings.Join(e.Headers, ", ")
+}
+
+func (e *MissingHeaderError) Unwrap() error {
+	return e.Err
+}
+
+// missingHeaders turns the validation error err of obj into a *MissingHeaderError
+// when it is only made of failing "required" rules.
+func missingHeaders(obj any, err error) error {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return err
+	}
+	headers := make([]string, 0, len(verrs))
+	for _, fe := range verrs {
+		if fe.Tag() != "required" {
+			return err
+		}
+		headers = append(headers, headerName(reflect.TypeOf(obj), fe.StructNamespace()))
+	}
+	return &MissingHeaderError{Headers: headers, Err: err}
+}
+
+// headerName returns the header bound to the field at the struct namespace
+// "Struct.Field.Sub" of typ, or the field name if it can not be found.
+func headerName(typ reflect.Type, namespace string) string {
+	parts := strings.Split(namespace, ".")[1:]
+	name := parts[len(parts)-1]
+	for _, part := range parts {
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		if typ.Kind() != reflect.Struct {
+			return name
+		}
+		field, ok := typ.FieldByName(part)
+		if !ok {
+			return name
+		}
+		typ = field.Type
+		name = part
+		if tag, _ := head(field.Tag.Get("header"), ","); tag != "" && tag != "-" {
+			name = tag
+		}
+	}
+	return textproto.CanonicalMIMEHeaderKey(name)
 }
@@ -0,0 +1,102 @@
+// Copyright 2014 Manu Martinez-Almeida. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package binding
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+
+	"github.com/gin-gonic/gin/internal/json"
+)
+
+type hexFormBinding struct{}
+
+func (hexFormBinding) Name() string {
+	return "hexform"
+}
+
+func (hexFormBinding) Bind(req *http.Request, obj any) error {
+	if err := req.ParseForm(); err != nil {
+		return err
+	}
+	if err := req.ParseMultipartForm(defaultMemory); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		return err
+	}
+	if err := mappingByPtr(obj, hexFormSource(req.Form), "form"); err != nil {
+		return err
+	}
+	return validate(obj)
+}
+
+// hexFormSource is a formSource that decodes int64 and uint64 fields tagged
+// `hexstring` the same way the json package decodes hexstring fields.
+type hexFormSource map[string][]string
+
+var _ setter = hexFormSource(nil)
+
+// TrySet tries to set a value by request's form source, honoring the hexstring option.
+func (form hexFormSource) TrySet(value reflect.Value, field reflect.StructField, tagValue string, opt setOptions) (isSet bool, err error) {
+	if !opt.isHexString {
+		return setByForm(value, field, form, tagValue, opt)
+	}
+
+	vs, ok := form[tagValue]
+	if !ok && !opt.isDefaultExists {
+		return false, nil
+	}
+	if !ok {
+		vs = []string{opt.defaultValue}
+	}
+
+	if value.Kind() == reflect.Slice {
+		slice := reflect.MakeSlice(value.Type(), len(vs), len(vs))
+		for i, s := range vs {
+			if err = setHexField(s, slice.Index(i), field); err != nil {
+				return false, err
+			}
+		}
+		value.Set(slice)
+		return true, nil
+	}
+
+	var val string
+	if len(vs) > 0 {
+		val = vs[0]
+	}
+	return true, setHexField(val, value, field)
+}
+
+func setHexField(val string, value reflect.Value, field reflect.StructField) error {
+	switch value.Kind() {
+	case reflect.Int64:
+		if val == "" {
+			value.SetInt(0)
+			return nil
+		}
+		intVal, err := json.ParseHexString(val, json.CurrentHexDecodeMode())
+		if err == nil {
+			value.SetInt(intVal)
+		}
+		return err
+	case reflect.Uint64:
+		if val == "" {
+			value.SetUint(0)
+			return nil
+		}
+		uintVal, err := json.ParseHexUint(val, json.CurrentHexDecodeMode())
+		if err == nil {
+			value.SetUint(uintVal)
+		}
+		return err
+	case reflect.Ptr:
+		if !value.Elem().IsValid() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		return setHexField(val, value.Elem(), field)
+	default:
+		return setWithProperType(val, value, field)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+//go:build !jsoniter && !go_json && !(sonic && avx && (linux || windows || darwin) && amd64)
+
+package binding
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONBindingBindBodyHexDecodeErrors(t *testing.T) {
+	var s struct {
+		ID     int64   `json:"id,hexstring"`
+		Parent int64   `json:"parent_id,hexstring"`
+		Owner  uint64  `json:"owner,hexstring"`
+		IDs    []int64 `json:"ids,hexstring"`
+	}
+	err := jsonBinding{}.BindBody([]byte(`{"id":"zz","parent_id":"ff","owner":"-1","ids":["1","x"]}`), &s)
+	require.Error(t, err)
+
+	var hexErrs HexDecodeErrors
+	require.True(t, errors.As(err, &hexErrs))
+	assert.Equal(t, []string{"id", "owner", "ids[1]"}, hexErrs.Fields())
+	assert.Equal(t, "zz", hexErrs[0].Raw)
+	assert.Contains(t, err.Error(), `"id"`)
+	assert.Equal(t, int64(0xff), s.Parent)
+	assert.Equal(t, []int64{1}, s.IDs)
+}
+
+func TestNDJSONBindingHexstring(t *testing.T) {
+	type hexItem struct {
+		ID   int64  `json:"id,hexstring"`
+		Name string `json:"name"`
+	}
+	req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader("{\"id\":\"ff\",\"name\":\"a\"}\n{\"id\":\"10\",\"name\":\"b\"}"))
+	var items []hexItem
+	require.NoError(t, NDJSON.Bind(req, &items))
+	assert.Equal(t, []hexItem{{0xff, "a"}, {0x10, "b"}}, items)
+
+	items = nil
+	err := NDJSON.BindBody([]byte("{\"id\":\"1\",\"name\":\"a\"}\n{\"id\":\"zz\",\"name\":\"b\"}\n"), &items)
+	var lineErr *NDJSONError
+	require.ErrorAs(t, err, &lineErr)
+	assert.Equal(t, 2, lineErr.Line)
+	var hexErrs HexDecodeErrors
+	assert.ErrorAs(t, err, &hexErrs)
+	assert.Equal(t, []hexItem{{1, "a"}}, items)
+}
@@ -6,9 +6,12 @@ package binding
 
 import (
 	"bytes"
+	stdjson "encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin/internal/json"
 )
@@ -24,33 +27,162 @@ var EnableDecoderUseNumber = false
 // keys which do not match any non-ignored, exported fields in the destination.
 var EnableDecoderDisallowUnknownFields = false
 
-type jsonBinding struct{}
+// MaxBodyPresize caps the buffer the JSON binding allocates up front to read a request body
+// from its Content-Length. gin.MaxBodyBytes rejects the requests announcing more than its
+// limit before they are read.
+var MaxBodyPresize int64 = 1 << 20
+
+// HexDecodeError describes a hexstring field that could not be decoded.
+type HexDecodeError = json.HexDecodeError
+
+// HexDecodeErrors is returned by the JSON binding when one or more hexstring
+// fields could not be decoded. It lists every failing field, not just the first.
+type HexDecodeErrors = json.HexDecodeErrors
+
+// HexDecodeMode selects how hexstring values are decoded, see SetHexDecodeMode.
+type HexDecodeMode = json.HexDecodeMode
+
+const (
+	// HexDecodeAuto decodes a value as hex if it has a 0x prefix, at most 16 digits or
+	// digits a-f, and as decimal otherwise. It is the default.
+	HexDecodeAuto = json.HexDecodeAuto
+	// HexDecodePrefix decodes a value as hex only if it has a 0x prefix, and as decimal
+	// otherwise. hexstring fields are then rendered with the 0x prefix.
+	HexDecodePrefix = json.HexDecodePrefix
+)
+
+// SetHexDecodeMode sets how hexstring values are decoded by the JSON, hexform and uri
+// bindings and Context.ParamHex64, and rendered by the JSON renders. Decodings in progress
+// keep the previous mode.
+func SetHexDecodeMode(mode HexDecodeMode) {
+	json.SetHexDecodeMode(mode)
+}
+
+// DuplicateKeyError is returned by UniqueKeysJSON when an object of the body has a key twice.
+type DuplicateKeyError struct {
+	// Path locates the repeated key, e.g. "user.emails[1].type".
+	Path string
+}
+
+func (e *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("json: duplicate key %q", e.Path)
+}
+
+type jsonBinding struct {
+	// disallowUnknownFields rejects unknown keys even if EnableDecoderDisallowUnknownFields is false.
+	disallowUnknownFields bool
+	// rejectDuplicateKeys fails on objects repeating a key.
+	rejectDuplicateKeys bool
+}
 
 func (jsonBinding) Name() string {
 	return "json"
 }
 
-func (jsonBinding) Bind(req *http.Request, obj any) error {
+func (b jsonBinding) Bind(req *http.Request, obj any) error {
 	if req == nil || req.Body == nil {
 		return errors.New("invalid request")
 	}
-	return decodeJSON(req.Body, obj)
+	if req.ContentLength <= 0 && !b.rejectDuplicateKeys {
+		return decodeJSON(req.Body, obj, b.disallowUnknownFields)
+	}
+	body, err := readBody(req.Body, req.ContentLength)
+	if err != nil {
+		return err
+	}
+	return b.BindBody(body, obj)
 }
 
-func (jsonBinding) BindBody(body []byte, obj any) error {
-	return decodeJSON(bytes.NewReader(body), obj)
+func (b jsonBinding) BindBody(body []byte, obj any) error {
+	if b.rejectDuplicateKeys {
+		if err := checkDuplicateKeys(body); err != nil {
+			return err
+		}
+	}
+	if err := json.DecodeBytes(body, obj, b.decodeOptions()); err != nil {
+		return err
+	}
+	return validate(obj)
 }
 
-func decodeJSON(r io.Reader, obj any) error {
-	decoder := json.NewDecoder(r)
-	if EnableDecoderUseNumber {
-		decoder.UseNumber()
-	}
-	if EnableDecoderDisallowUnknownFields {
-		decoder.DisallowUnknownFields()
+func (b jsonBinding) decodeOptions() json.DecodeOptions {
+	return json.DecodeOptions{
+		UseNumber:             EnableDecoderUseNumber,
+		DisallowUnknownFields: EnableDecoderDisallowUnknownFields || b.disallowUnknownFields,
 	}
-	if err := decoder.Decode(obj); err != nil {
+}
+
+func decodeJSON(r io.Reader, obj any, disallowUnknownFields bool) error {
+	if err := json.Decode(r, obj, jsonBinding{disallowUnknownFields: disallowUnknownFields}.decodeOptions()); err != nil {
 		return err
 	}
 	return validate(obj)
 }
+
+// readBody reads r whole into a buffer allocated for size bytes, the Content-Length of the
+// request, but no more than MaxBodyPresize: a larger body still grows the buffer as it is
+// actually received, so a client cannot make the server allocate by announcing a length.
+func readBody(r io.Reader, size int64) ([]byte, error) {
+	if size > MaxBodyPresize {
+		size = MaxBodyPresize
+	}
+	if size < 0 {
+		size = 0
+	}
+	// bytes.Buffer.ReadFrom wants bytes.MinRead spare bytes to detect the end of r
+	buf := bytes.NewBuffer(make([]byte, 0, size+bytes.MinRead))
+	_, err := buf.ReadFrom(r)
+	return buf.Bytes(), err
+}
+
+// checkDuplicateKeys returns a *DuplicateKeyError for the first object key repeated in body.
+// Syntax errors are left to the decoder, so they read the same as with JSON.
+func checkDuplicateKeys(body []byte) error {
+	dec := stdjson.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var dup *DuplicateKeyError
+	if err := scanDuplicateKeys(dec, ""); errors.As(err, &dup) {
+		return dup
+	}
+	return nil
+}
+
+func scanDuplicateKeys(dec *stdjson.Decoder, path string) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case stdjson.Delim('{'):
+		keys := make(map[string]struct{})
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := tok.(string)
+			keyPath := key
+			if path != "" {
+				keyPath = path + "." + key
+			}
+			if _, ok := keys[key]; ok {
+				return &DuplicateKeyError{Path: keyPath}
+			}
+			keys[key] = struct{}{}
+			if err := scanDuplicateKeys(dec, keyPath); err != nil {
+				return err
+			}
+		}
+	case stdjson.Delim('['):
+		for i := 0; dec.More(); i++ {
+			if err := scanDuplicateKeys(dec, path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+	default:
+		return nil
+	}
+	// the closing delimiter
+	_, err = dec.Token()
+	return err
+}
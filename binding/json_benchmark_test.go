@@ -0,0 +1,58 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package binding
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type benchJSONItem struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Text string `json:"text"`
+}
+
+// benchJSONBody returns a JSON array of almost 1MB, under MaxBodyPresize.
+func benchJSONBody() []byte {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i := 0; buf.Len() < 1<<20-200; i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(`{"id":` + strconv.Itoa(i) + `,"name":"item ` + strconv.Itoa(i) + `","text":"` + strings.Repeat("x", 100) + `"}`)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes()
+}
+
+func benchmarkJSONBind(b *testing.B, contentLength bool) {
+	body := benchJSONBody()
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		req, _ := http.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+		if !contentLength {
+			req.ContentLength = -1
+		}
+		var items []benchJSONItem
+		if err := JSON.Bind(req, &items); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkJSONBindContentLength(b *testing.B) {
+	benchmarkJSONBind(b, true)
+}
+
+func BenchmarkJSONBindNoContentLength(b *testing.B) {
+	benchmarkJSONBind(b, false)
+}
@@ -5,7 +5,12 @@
 package binding
 
 import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -28,3 +33,81 @@ func TestJSONBindingBindBodyMap(t *testing.T) {
 	assert.Equal(t, "FOO", s["foo"])
 	assert.Equal(t, "world", s["hello"])
 }
+
+func TestJSONBindingDuplicateKeys(t *testing.T) {
+	var obj any
+	for body, path := range map[string]string{
+		`{"role":"user","role":"admin"}`:                    "role",
+		`{"user":{"id":1,"name":"a","id":2}}`:               "user.id",
+		`{"items":[{"k":1},{"k":2,"k":3}]}`:                 "items[1].k",
+		`[{"a":{"b":[]}},{"a":1,"a":2}]`:                    "[1].a",
+		`{"tags":["x","x"],"meta":{"x":1},"other":{"x":1}}`: "",
+	} {
+		err := UniqueKeysJSON.BindBody([]byte(body), &obj)
+		if path == "" {
+			assert.NoError(t, err, body)
+			continue
+		}
+		var dup *DuplicateKeyError
+		require.ErrorAs(t, err, &dup, body)
+		assert.Equal(t, path, dup.Path)
+		// the JSON binding keeps the last value
+		assert.NoError(t, JSON.BindBody([]byte(body), &obj), body)
+	}
+
+	// syntax errors are reported by the decoder
+	err := UniqueKeysJSON.BindBody([]byte(`{"a":1,"b"`), &obj)
+	var dup *DuplicateKeyError
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &dup))
+
+	// without a Content-Length the body is read whole
+	req, _ := http.NewRequest(http.MethodPost, "/", iotest.OneByteReader(strings.NewReader(`{"a":1,"a":2}`)))
+	require.ErrorAs(t, UniqueKeysJSON.Bind(req, &obj), &dup)
+	assert.Equal(t, "a", dup.Path)
+	assert.Equal(t, `json: duplicate key "a"`, dup.Error())
+}
+
+func TestStrictJSONBinding(t *testing.T) {
+	var s struct {
+		Foo string `json:"foo"`
+	}
+	assert.Equal(t, "json", StrictJSON.Name())
+	require.NoError(t, StrictJSON.BindBody([]byte(`{"foo":"FOO"}`), &s))
+	assert.Equal(t, "FOO", s.Foo)
+
+	err := StrictJSON.BindBody([]byte(`{"foo":"FOO","bar":1}`), &s)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bar")
+	require.NoError(t, JSON.BindBody([]byte(`{"foo":"FOO","bar":1}`), &s))
+}
+
+func TestJSONBindingReadBodyPresize(t *testing.T) {
+	body, err := readBody(strings.NewReader(`{"foo":"FOO"}`), 13)
+	require.NoError(t, err)
+	assert.Equal(t, `{"foo":"FOO"}`, string(body))
+	assert.Equal(t, 13+bytes.MinRead, cap(body))
+
+	// an announced length over MaxBodyPresize does not allocate it
+	body, err = readBody(strings.NewReader(`{}`), 1<<40)
+	require.NoError(t, err)
+	assert.Equal(t, `{}`, string(body))
+	assert.LessOrEqual(t, int64(cap(body)), MaxBodyPresize+bytes.MinRead)
+
+	var s struct {
+		Foo string `json:"foo"`
+	}
+	req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"foo":"FOO"}`))
+	require.NoError(t, JSON.Bind(req, &s))
+	assert.Equal(t, "FOO", s.Foo)
+
+	// a body shorter than announced is read until its end
+	req, _ = http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"foo":"BAR"}`))
+	req.ContentLength = 100
+	require.NoError(t, JSON.Bind(req, &s))
+	assert.Equal(t, "BAR", s.Foo)
+
+	req, _ = http.NewRequest(http.MethodPost, "/", iotest.ErrReader(errors.New("read failed")))
+	req.ContentLength = 10
+	assert.EqualError(t, JSON.Bind(req, &s), "read failed")
+}
@@ -0,0 +1,86 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package binding
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+
+	"github.com/gin-gonic/gin/internal/json"
+)
+
+var errNDJSONTarget = errors.New("ndjson: obj must be a pointer to a slice")
+
+// NDJSONError reports the line of an NDJSON body that could not be decoded.
+// Line numbers start at 1 and count blank lines.
+type NDJSONError struct {
+	Line int
+	Err  error
+}
+
+func (e *NDJSONError) Error() string {
+	return fmt.Sprintf("ndjson: line %d: %v", e.Line, e.Err)
+}
+
+func (e *NDJSONError) Unwrap() error {
+	return e.Err
+}
+
+type ndjsonBinding struct{}
+
+func (ndjsonBinding) Name() string {
+	return "ndjson"
+}
+
+func (ndjsonBinding) Bind(req *http.Request, obj any) error {
+	if req == nil || req.Body == nil {
+		return errors.New("invalid request")
+	}
+	return decodeNDJSON(req.Body, obj)
+}
+
+func (ndjsonBinding) BindBody(body []byte, obj any) error {
+	return decodeNDJSON(bytes.NewReader(body), obj)
+}
+
+// decodeNDJSON appends one element to the slice obj points to for every non-blank
+// line of r. It stops at the first line that fails to decode; the elements of the
+// previous lines stay in the slice.
+func decodeNDJSON(r io.Reader, obj any) error {
+	rv := reflect.ValueOf(obj)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return errNDJSONTarget
+	}
+	slice := rv.Elem()
+	elemType := slice.Type().Elem()
+	opts := json.DecodeOptions{
+		UseNumber:             EnableDecoderUseNumber,
+		DisallowUnknownFields: EnableDecoderDisallowUnknownFields,
+	}
+
+	br := bufio.NewReader(r)
+	for line := 1; ; line++ {
+		data, err := br.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if len(bytes.TrimSpace(data)) > 0 {
+			elem := reflect.New(elemType)
+			if decodeErr := json.Decode(bytes.NewReader(data), elem.Interface(), opts); decodeErr != nil {
+				return &NDJSONError{Line: line, Err: decodeErr}
+			}
+			slice.Set(reflect.Append(slice, elem.Elem()))
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+	return validate(obj)
+}
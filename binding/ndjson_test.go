@@ -0,0 +1,71 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package binding
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ndjsonItem struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name" binding:"required"`
+}
+
+func TestNDJSONBindingBind(t *testing.T) {
+	assert.Equal(t, "ndjson", NDJSON.Name())
+
+	body := "{\"id\":255,\"name\":\"a\"}\n\n  \r\n{\"id\":16,\"name\":\"b\"}"
+	req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	var items []ndjsonItem
+	require.NoError(t, NDJSON.Bind(req, &items))
+	assert.Equal(t, []ndjsonItem{{0xff, "a"}, {0x10, "b"}}, items)
+
+	req, _ = http.NewRequest(http.MethodPost, "/", nil)
+	assert.Error(t, NDJSON.Bind(req, &items))
+}
+
+func TestNDJSONBindingBindBody(t *testing.T) {
+	var items []*ndjsonItem
+	require.NoError(t, NDJSON.BindBody([]byte("{\"id\":1,\"name\":\"a\"}\n"), &items))
+	require.Len(t, items, 1)
+	assert.Equal(t, "a", items[0].Name)
+
+	items = nil
+	require.NoError(t, NDJSON.BindBody(nil, &items))
+	assert.Empty(t, items)
+}
+
+func TestNDJSONBindingPartialFailure(t *testing.T) {
+	body := "{\"id\":1,\"name\":\"a\"}\n\n{\"id\":\"zz\",\"name\":\"b\"}\n{\"id\":3,\"name\":\"c\"}\n"
+	var items []ndjsonItem
+	err := NDJSON.BindBody([]byte(body), &items)
+
+	var lineErr *NDJSONError
+	require.ErrorAs(t, err, &lineErr)
+	assert.Equal(t, 3, lineErr.Line)
+	assert.Contains(t, err.Error(), "ndjson: line 3: ")
+	assert.Equal(t, []ndjsonItem{{1, "a"}}, items)
+
+	items = nil
+	err = NDJSON.BindBody([]byte("{\"id\":1,\"name\":\"a\"}\n{\"id\":"), &items)
+	require.ErrorAs(t, err, &lineErr)
+	assert.Equal(t, 2, lineErr.Line)
+}
+
+func TestNDJSONBindingValidate(t *testing.T) {
+	var items []ndjsonItem
+	assert.Error(t, NDJSON.BindBody([]byte("{\"id\":1}"), &items))
+}
+
+func TestNDJSONBindingTarget(t *testing.T) {
+	var item ndjsonItem
+	assert.ErrorIs(t, NDJSON.BindBody([]byte("{}"), &item), errNDJSONTarget)
+	assert.ErrorIs(t, NDJSON.BindBody([]byte("{}"), []ndjsonItem{}), errNDJSONTarget)
+}
@@ -0,0 +1,13 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+//go:build noprotobuf
+
+package binding
+
+// protoBufDefault binds protobuf bodies like other unknown content types
+// when building with the noprotobuf tag.
+func protoBufDefault() Binding {
+	return Form
+}
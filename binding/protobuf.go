@@ -2,6 +2,8 @@
 // Use of this source code is governed by a MIT style
 // license that can be found in the LICENSE file.
 
+//go:build !noprotobuf
+
 package binding
 
 import (
@@ -12,6 +14,14 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
+// ProtoBuf binds application/x-protobuf bodies into a proto.Message.
+// It is left out when building with the noprotobuf tag.
+var ProtoBuf = protobufBinding{}
+
+func protoBufDefault() Binding {
+	return ProtoBuf
+}
+
 type protobufBinding struct{}
 
 func (protobufBinding) Name() string {
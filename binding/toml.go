@@ -6,12 +6,30 @@ package binding
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 
 	"github.com/pelletier/go-toml/v2"
 )
 
+// TOMLError reports where a TOML body failed to parse. Line and Column start at 1;
+// Err is the *toml.DecodeError of github.com/pelletier/go-toml/v2.
+type TOMLError struct {
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *TOMLError) Error() string {
+	return fmt.Sprintf("%v (line %d, column %d)", e.Err, e.Line, e.Column)
+}
+
+func (e *TOMLError) Unwrap() error {
+	return e.Err
+}
+
 type tomlBinding struct{}
 
 func (tomlBinding) Name() string {
@@ -29,7 +47,12 @@ func (tomlBinding) BindBody(body []byte, obj any) error {
 func decodeToml(r io.Reader, obj any) error {
 	decoder := toml.NewDecoder(r)
 	if err := decoder.Decode(obj); err != nil {
+		var decodeErr *toml.DecodeError
+		if errors.As(err, &decodeErr) {
+			line, column := decodeErr.Position()
+			return &TOMLError{Line: line, Column: column, Err: err}
+		}
 		return err
 	}
-	return decoder.Decode(obj)
+	return validate(obj)
 }
@@ -5,8 +5,13 @@
 package binding
 
 import (
+	"errors"
+	"net/http"
+	"strings"
 	"testing"
 
+	"github.com/pelletier/go-toml/v2"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -20,3 +25,66 @@ func TestTOMLBindingBindBody(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "FOO", s.Foo)
 }
+
+type tomlServer struct {
+	Host string `toml:"host" binding:"required"`
+	Port int    `toml:"port"`
+}
+
+type tomlConfig struct {
+	Name     string `toml:"name"`
+	Database struct {
+		DSN  string `toml:"dsn"`
+		Pool struct {
+			Max int `toml:"max"`
+		} `toml:"pool"`
+	} `toml:"database"`
+	Servers []tomlServer `toml:"servers" binding:"dive"`
+}
+
+func TestTOMLBindingBindNested(t *testing.T) {
+	body := `name = "push"
+
+[database]
+dsn = "mysql://db"
+
+[database.pool]
+max = 8
+
+[[servers]]
+host = "a"
+port = 80
+
+[[servers]]
+host = "b"
+port = 81
+`
+	req, _ := http.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	var cfg tomlConfig
+	require.NoError(t, TOML.Bind(req, &cfg))
+	assert.Equal(t, "push", cfg.Name)
+	assert.Equal(t, "mysql://db", cfg.Database.DSN)
+	assert.Equal(t, 8, cfg.Database.Pool.Max)
+	assert.Equal(t, []tomlServer{{"a", 80}, {"b", 81}}, cfg.Servers)
+}
+
+func TestTOMLBindingParseError(t *testing.T) {
+	var cfg tomlConfig
+	err := TOML.BindBody([]byte("name = \"push\"\n\n[database]\ndsn = \n"), &cfg)
+
+	var tomlErr *TOMLError
+	require.ErrorAs(t, err, &tomlErr)
+	assert.Equal(t, 4, tomlErr.Line)
+	assert.Equal(t, 7, tomlErr.Column)
+	assert.Contains(t, err.Error(), "(line 4, column 7)")
+	var decodeErr *toml.DecodeError
+	assert.ErrorAs(t, err, &decodeErr)
+}
+
+func TestTOMLBindingValidate(t *testing.T) {
+	var cfg tomlConfig
+	err := TOML.BindBody([]byte("[[servers]]\nport = 80\n"), &cfg)
+	assert.Error(t, err)
+	var tomlErr *TOMLError
+	assert.False(t, errors.As(err, &tomlErr))
+}
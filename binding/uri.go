@@ -4,6 +4,12 @@
 
 package binding
 
+import (
+	"reflect"
+	"strconv"
+	"time"
+)
+
 type uriBinding struct{}
 
 func (uriBinding) Name() string {
@@ -16,3 +22,44 @@ func (uriBinding) BindUri(m map[string][]string, obj any) error {
 	}
 	return validate(obj)
 }
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// uriSource is the setter of path params, see setTyped.
+type uriSource map[string][]string
+
+var _ setter = uriSource(nil)
+
+// TrySet tries to set a value by the path params.
+func (uri uriSource) TrySet(value reflect.Value, field reflect.StructField, tagValue string, opt setOptions) (isSet bool, err error) {
+	return setTyped(value, field, uri, tagValue, opt)
+}
+
+// setTyped is setByForm for sources that are not forms, such as path params and headers:
+// fields tagged `hexstring` are decoded like hexFormSource does, e.g. `uri:"id,hexstring"`,
+// and other int64 fields are always decimal.
+func setTyped(value reflect.Value, field reflect.StructField, form map[string][]string, tagValue string, opt setOptions) (isSet bool, err error) {
+	if opt.isHexString {
+		return hexFormSource(form).TrySet(value, field, tagValue, opt)
+	}
+	if value.Kind() != reflect.Int64 || value.Type() == durationType {
+		return setByForm(value, field, form, tagValue, opt)
+	}
+
+	vs, ok := form[tagValue]
+	if !ok && !opt.isDefaultExists {
+		return false, nil
+	}
+	val := opt.defaultValue
+	if ok && len(vs) > 0 {
+		val = vs[0]
+	}
+	if val == "" {
+		val = "0"
+	}
+	intVal, err := strconv.ParseInt(val, 10, 64)
+	if err == nil {
+		value.SetInt(intVal)
+	}
+	return true, err
+}
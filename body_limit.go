@@ -0,0 +1,62 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"io"
+	"net/http"
+)
+
+// MaxBodyBytes returns a middleware that limits the request body of the following handlers
+// to n bytes. A request whose Content-Length is over the limit is aborted at once with 413
+// Request Entity Too Large and a {"error": "..."} JSON body; a body that turns out larger
+// while it is read fails the read, and MustBindWith, MustBindJSON and the Bind* shortcuts
+// then answer 413 the same way instead of 400.
+// Without this middleware request bodies are not limited.
+func MaxBodyBytes(n int64) HandlerFunc {
+	return func(c *Context) {
+		if c.Request.ContentLength > n {
+			abortBodyTooLarge(c, newMaxBytesError(n))
+			return
+		}
+		if c.Request.Body != nil {
+			c.Request.Body = &maxBytesBody{ReadCloser: http.MaxBytesReader(c.Writer, c.Request.Body, n)}
+		}
+		c.Next()
+	}
+}
+
+// maxBytesBody remembers that the limit of the http.MaxBytesReader it wraps was hit,
+// as decoders such as jsoniter do not keep its error.
+type maxBytesBody struct {
+	io.ReadCloser
+	err error
+}
+
+func (b *maxBytesBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if b.err == nil && isMaxBytesError(err) {
+		b.err = err
+	}
+	return n, err
+}
+
+// bodyTooLarge returns the http.MaxBytesReader error behind a failed bind, if any.
+func (c *Context) bodyTooLarge(err error) error {
+	if isMaxBytesError(err) {
+		return err
+	}
+	if c.Request != nil {
+		if body, ok := c.Request.Body.(*maxBytesBody); ok {
+			return body.err
+		}
+	}
+	return nil
+}
+
+func abortBodyTooLarge(c *Context, err error) {
+	c.Error(err).SetType(ErrorTypeBind) //nolint: errcheck
+	c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, H{"error": err.Error()})
+}
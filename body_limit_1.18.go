@@ -0,0 +1,28 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+//go:build !go1.19
+
+package gin
+
+import "errors"
+
+// maxBytesErrorText is the message of the error http.MaxBytesReader fails with.
+const maxBytesErrorText = "http: request body too large"
+
+// newMaxBytesError returns the error http.MaxBytesReader fails with past the limit n.
+func newMaxBytesError(n int64) error {
+	return errors.New(maxBytesErrorText)
+}
+
+// isMaxBytesError reports whether err comes from reading past the limit of an
+// http.MaxBytesReader, which has no error type before Go 1.19.
+func isMaxBytesError(err error) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if err.Error() == maxBytesErrorText {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+//go:build go1.19
+
+package gin
+
+import (
+	"errors"
+	"net/http"
+)
+
+// newMaxBytesError returns the error http.MaxBytesReader fails with past the limit n.
+func newMaxBytesError(n int64) error {
+	return &http.MaxBytesError{Limit: n}
+}
+
+// isMaxBytesError reports whether err comes from reading past the limit of an
+// http.MaxBytesReader.
+func isMaxBytesError(err error) bool {
+	var mbe *http.MaxBytesError
+	return errors.As(err, &mbe)
+}
@@ -0,0 +1,123 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type bodyLimitReq struct {
+	Name string `json:"name"`
+}
+
+func performBodyLimit(router *Engine, body string, contentLength int64) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/", io.NopCloser(strings.NewReader(body)))
+	req.ContentLength = contentLength
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMaxBodyBytes(t *testing.T) {
+	body := `{"name":"gin"}`
+	limit := int64(len(body))
+	router := New()
+	router.POST("/", MaxBodyBytes(limit), func(c *Context) {
+		var req bodyLimitReq
+		if c.BindJSON(&req) != nil {
+			return
+		}
+		c.String(http.StatusOK, req.Name)
+	})
+
+	w := performBodyLimit(router, body, -1)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gin", w.Body.String())
+
+	// one byte over the limit, length unknown: fails while binding
+	w = performBodyLimit(router, `{"name":"ginn"}`, -1)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	var res map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, "http: request body too large", res["error"])
+
+	// one byte over the limit, announced by Content-Length: rejected at once
+	w = performBodyLimit(router, `{"name":"ginn"}`, limit+1)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.JSONEq(t, `{"error":"http: request body too large"}`, w.Body.String())
+}
+
+func TestMaxBodyBytesMustBindJSON(t *testing.T) {
+	router := New()
+	router.POST("/", MaxBodyBytes(4), func(c *Context) {
+		var req bodyLimitReq
+		if !c.MustBindJSON(&req) {
+			assert.Len(t, c.Errors.ByType(ErrorTypeBind), 1)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	w := performBodyLimit(router, `{"name":"gin"}`, -1)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	w = performBodyLimit(router, `{`, -1)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestMaxBodyBytesUnlimitedByDefault(t *testing.T) {
+	router := New()
+	router.POST("/", func(c *Context) {
+		var req bodyLimitReq
+		if c.BindJSON(&req) != nil {
+			return
+		}
+		c.String(http.StatusOK, req.Name)
+	})
+
+	w := performBodyLimit(router, `{"name":"`+strings.Repeat("a", 1<<20)+`"}`, -1)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestMaxBodyBytesMultipartReader(t *testing.T) {
+	buf := new(strings.Builder)
+	mw := multipart.NewWriter(buf)
+	w, err := mw.CreateFormFile("file", "large.bin")
+	require.NoError(t, err)
+	_, err = w.Write([]byte(strings.Repeat("a", 1024)))
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+
+	var readErr error
+	router := New()
+	router.POST("/", MaxBodyBytes(512), func(c *Context) {
+		mr, err := c.MultipartReader()
+		require.NoError(t, err)
+		part, err := mr.NextPart()
+		require.NoError(t, err)
+		_, readErr = io.Copy(io.Discard, part)
+		if mbe := c.bodyTooLarge(readErr); mbe != nil {
+			abortBodyTooLarge(c, mbe)
+		}
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", io.NopCloser(strings.NewReader(buf.String())))
+	req.ContentLength = -1
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.True(t, isMaxBytesError(readErr))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
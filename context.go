@@ -5,22 +5,33 @@
 package gin
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"io"
 	"log"
 	"math"
 	"mime/multipart"
 	"net"
 	"net/http"
+	"net/netip"
 	"net/url"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/gin-contrib/sse"
 	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/internal/json"
 	"github.com/gin-gonic/gin/render"
 )
 
@@ -35,6 +46,7 @@ const (
 	MIMEMultipartPOSTForm = binding.MIMEMultipartPOSTForm
 	MIMEYAML              = binding.MIMEYAML
 	MIMETOML              = binding.MIMETOML
+	MIMENDJSON            = binding.MIMENDJSON
 )
 
 // BodyBytesKey indicates a default body bytes key.
@@ -43,6 +55,9 @@ const BodyBytesKey = "_gin-gonic/gin/bodybyteskey"
 // ContextKey is the key that a Context returns itself for.
 const ContextKey = "_gin-gonic/gin/contextkey"
 
+// DefaultRequestIDKey is the default value of Engine.RequestIDKey.
+const DefaultRequestIDKey = "request_id"
+
 // abortIndex represents a typical value used in abort functions.
 const abortIndex int8 = math.MaxInt8 >> 1
 
@@ -58,9 +73,16 @@ type Context struct {
 	index    int8
 	fullPath string
 
-	engine       *Engine
-	params       *Params
-	skippedNodes *[]skippedNode
+	engine         *Engine
+	params         *Params
+	skippedNodes   *[]skippedNode
+	allowedMethods []string
+
+	// detached is set on copies, which are not cancelled with the request.
+	detached bool
+
+	// remoteAddrPort caches the result of RemoteAddrPort.
+	remoteAddrPort addrPortCache
 
 	// This mutex protects Keys map.
 	mu sync.RWMutex
@@ -74,6 +96,9 @@ type Context struct {
 	// Accepted defines a list of manually accepted formats for content negotiation.
 	Accepted []string
 
+	// acceptRejected lists the media ranges of the Accept header with q=0.
+	acceptRejected []string
+
 	// queryCache caches the query result from c.Request.URL.Query().
 	queryCache url.Values
 
@@ -100,21 +125,27 @@ func (c *Context) reset() {
 	c.Keys = nil
 	c.Errors = c.Errors[:0]
 	c.Accepted = nil
+	c.acceptRejected = nil
 	c.queryCache = nil
 	c.formCache = nil
 	c.sameSite = 0
+	c.allowedMethods = nil
+	c.remoteAddrPort = addrPortCache{}
 	*c.params = (*c.params)[:0]
 	*c.skippedNodes = (*c.skippedNodes)[:0]
 }
 
 // Copy returns a copy of the current context that can be safely used outside the request's scope.
 // This has to be used when the context has to be passed to a goroutine.
+// Used as a context.Context, the copy has no deadline and is not cancelled with the request;
+// Value still falls back to c.Request.Context().
 func (c *Context) Copy() *Context {
 	cp := Context{
 		writermem: c.writermem,
 		Request:   c.Request,
 		Params:    c.Params,
 		engine:    c.engine,
+		detached:  true,
 	}
 	cp.writermem.ResponseWriter = nil
 	cp.Writer = &cp.writermem
@@ -130,6 +161,13 @@ func (c *Context) Copy() *Context {
 	return &cp
 }
 
+// AllowedMethods returns the methods registered for the request path when the request
+// is answered with 405 Method Not Allowed, so NoMethod handlers can use them. The
+// 'Allow' response header is already set from it. It returns nil otherwise.
+func (c *Context) AllowedMethods() []string {
+	return c.allowedMethods
+}
+
 // HandlerName returns the main handler's name. For example if the handler is "handleGetUsers()",
 // this function will return "main.handleGetUsers".
 func (c *Context) HandlerName() string {
@@ -205,6 +243,21 @@ func (c *Context) AbortWithStatusJSON(code int, jsonObj any) {
 	c.JSON(code, jsonObj)
 }
 
+// failBody is the JSON body written by Fail.
+type failBody struct {
+	Code      int    `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// Fail calls `Abort()` and writes the status code with a {"code":code,"message":msg,"request_id":"..."}
+// JSON body, rendered with the package JSON encoder. The request id is read from the Context key
+// Engine.RequestIDKey, falling back to the X-Request-Id response or request header; it is left out
+// when there is none.
+func (c *Context) Fail(code int, msg string) {
+	c.AbortWithStatusJSON(code, failBody{Code: code, Message: msg, RequestID: requestID(c)})
+}
+
 // AbortWithError calls `AbortWithStatus()` and `Error()` internally.
 // This method stops the chain, writes the status code and pushes the specified error to `c.Errors`.
 // See Context.Error() for more details.
@@ -273,6 +326,27 @@ func (c *Context) MustGet(key string) any {
 	panic("Key \"" + key + "\" does not exist")
 }
 
+// GetTyped returns the value for the given key of c if it exists and is a T, otherwise
+// the zero T and false.
+//
+//	user, ok := gin.GetTyped[*User](c, "user")
+func GetTyped[T any](c *Context, key string) (T, bool) {
+	value, _ := c.Get(key)
+	typed, ok := value.(T)
+	return typed, ok
+}
+
+// MustGetTyped returns the value for the given key of c, it panics if the key does not
+// exist or its value is not a T.
+func MustGetTyped[T any](c *Context, key string) T {
+	value := c.MustGet(key)
+	typed, ok := value.(T)
+	if !ok {
+		panic(fmt.Sprintf("Key %q holds %T, not %v", key, value, reflect.TypeOf((*T)(nil)).Elem()))
+	}
+	return typed
+}
+
 // GetString returns the value associated with the key as a string.
 func (c *Context) GetString(key string) (s string) {
 	if val, ok := c.Get(key); ok && val != nil {
@@ -403,6 +477,122 @@ func (c *Context) AddParam(key, value string) {
 	c.Params = append(c.Params, Param{Key: key, Value: value})
 }
 
+// ErrParamMissing is wrapped by the ParamError returned when the path parameter does not exist.
+var ErrParamMissing = errors.New("missing path parameter")
+
+var errInvalidUUID = errors.New("invalid UUID")
+
+// ParamError is returned by the typed Param getters when a path parameter is missing
+// or cannot be parsed.
+type ParamError struct {
+	Key   string
+	Value string
+	Err   error
+}
+
+func (e *ParamError) Error() string {
+	if e.Err == ErrParamMissing {
+		return "path parameter " + strconv.Quote(e.Key) + " is missing"
+	}
+	return "path parameter " + strconv.Quote(e.Key) + " = " + strconv.Quote(e.Value) + ": " + e.Err.Error()
+}
+
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
+func newParamError(key, value string, err error) error {
+	if numErr, ok := err.(*strconv.NumError); ok {
+		err = numErr.Err
+	}
+	return &ParamError{Key: key, Value: value, Err: err}
+}
+
+func (c *Context) typedParam(key string) (string, error) {
+	value, ok := c.Params.Get(key)
+	if !ok {
+		return "", &ParamError{Key: key, Err: ErrParamMissing}
+	}
+	return value, nil
+}
+
+// ParamInt returns the value of the path parameter key parsed as a decimal int.
+// The error is a *ParamError carrying the parameter name and raw value.
+func (c *Context) ParamInt(key string) (int, error) {
+	value, err := c.typedParam(key)
+	if err != nil {
+		return 0, err
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, newParamError(key, value, err)
+	}
+	return i, nil
+}
+
+// ParamInt64 returns the value of the path parameter key parsed as a decimal int64.
+// The error is a *ParamError carrying the parameter name and raw value.
+func (c *Context) ParamInt64(key string) (int64, error) {
+	value, err := c.typedParam(key)
+	if err != nil {
+		return 0, err
+	}
+	i, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, newParamError(key, value, err)
+	}
+	return i, nil
+}
+
+// ParamHex64 returns the value of the path parameter key parsed as a hexstring id,
+// with the same rules as the hexstring json tag.
+// The error is a *ParamError carrying the parameter name and raw value.
+func (c *Context) ParamHex64(key string) (int64, error) {
+	value, err := c.typedParam(key)
+	if err != nil {
+		return 0, err
+	}
+	i, err := json.ParseHexString(value, json.CurrentHexDecodeMode())
+	if err != nil {
+		return 0, newParamError(key, value, err)
+	}
+	return i, nil
+}
+
+// ParamBool returns the value of the path parameter key parsed with strconv.ParseBool.
+// The error is a *ParamError carrying the parameter name and raw value.
+func (c *Context) ParamBool(key string) (bool, error) {
+	value, err := c.typedParam(key)
+	if err != nil {
+		return false, err
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, newParamError(key, value, err)
+	}
+	return b, nil
+}
+
+// ParamUUID returns the value of the path parameter key parsed as a UUID in the
+// canonical xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx form. The result converts directly
+// to uuid.UUID types, e.g. uuid.UUID(id).
+// The error is a *ParamError carrying the parameter name and raw value.
+func (c *Context) ParamUUID(key string) ([16]byte, error) {
+	var id [16]byte
+	value, err := c.typedParam(key)
+	if err != nil {
+		return id, err
+	}
+	if len(value) != 36 || value[8] != '-' || value[13] != '-' || value[18] != '-' || value[23] != '-' {
+		return id, newParamError(key, value, errInvalidUUID)
+	}
+	digits := value[:8] + value[9:13] + value[14:18] + value[19:23] + value[24:]
+	if _, err := hex.Decode(id[:], []byte(digits)); err != nil {
+		return id, newParamError(key, value, errInvalidUUID)
+	}
+	return id, nil
+}
+
 // Query returns the keyed url query value if it exists,
 // otherwise it returns an empty string `("")`.
 // It is shortcut for `c.Request.URL.Query().Get(key)`
@@ -450,6 +640,10 @@ func (c *Context) GetQuery(key string) (string, bool) {
 
 // QueryArray returns a slice of strings for a given query key.
 // The length of the slice depends on the number of params with the given key.
+// With Engine.QueryArrayBrackets enabled, "key[]" params count as the given key:
+//
+//	GET /?tags=a&tags[]=b&tags[]=c
+//	c.QueryArray("tags") == []string{"a", "b", "c"}
 func (c *Context) QueryArray(key string) (values []string) {
 	values, _ = c.GetQueryArray(key)
 	return
@@ -470,6 +664,12 @@ func (c *Context) initQueryCache() {
 func (c *Context) GetQueryArray(key string) (values []string, ok bool) {
 	c.initQueryCache()
 	values, ok = c.queryCache[key]
+	if c.engine != nil && c.engine.QueryArrayBrackets && !strings.HasSuffix(key, "[]") {
+		if bracketed, exist := c.queryCache[key+"[]"]; exist {
+			values = append(values[:len(values):len(values)], bracketed...)
+			ok = true
+		}
+	}
 	return
 }
 
@@ -486,6 +686,51 @@ func (c *Context) GetQueryMap(key string) (map[string]string, bool) {
 	return c.get(c.queryCache, key)
 }
 
+// QueryMapArray returns a map of string slices for a given query key.
+// See GetQueryMapArray for the params it accepts.
+func (c *Context) QueryMapArray(key string) (dicts map[string][]string) {
+	dicts, _ = c.GetQueryMapArray(key)
+	return
+}
+
+// GetQueryMapArray returns a map of string slices for a given query key, plus a
+// boolean value whether at least one value exists for the given key.
+// A param counts when its name is exactly "key[sub]" or "key[sub][]" with a non-empty
+// sub that contains no brackets; the values of both forms are collected under sub,
+// those of "key[sub]" first.
+// Other names such as "key[]", "key[a][b]" or "key[a]x" are ignored.
+//
+//	GET /?meta[x]=1&meta[x][]=2&meta[y]=3
+//	c.QueryMapArray("meta") == map[string][]string{"x": {"1", "2"}, "y": {"3"}}
+func (c *Context) GetQueryMapArray(key string) (map[string][]string, bool) {
+	c.initQueryCache()
+	return c.getArray(c.queryCache, key)
+}
+
+// QueryPrefixMap returns the query params whose name starts with prefix, keyed by the
+// rest of the name. See GetQueryPrefixMap.
+func (c *Context) QueryPrefixMap(prefix string) (dicts map[string]string) {
+	dicts, _ = c.GetQueryPrefixMap(prefix)
+	return
+}
+
+// GetQueryPrefixMap returns the query params whose name starts with prefix, keyed by the
+// rest of the name, plus a boolean value whether at least one such param exists.
+// A param given several times maps to its first value; a param named prefix alone is ignored.
+//
+//	GET /?filter.name=bob&filter.age=30&filter.age=40
+//	c.QueryPrefixMap("filter.") == map[string]string{"name": "bob", "age": "30"}
+func (c *Context) GetQueryPrefixMap(prefix string) (map[string]string, bool) {
+	c.initQueryCache()
+	dicts := make(map[string]string)
+	for k, v := range c.queryCache {
+		if len(k) > len(prefix) && strings.HasPrefix(k, prefix) && len(v) > 0 {
+			dicts[k[len(prefix):]] = v[0]
+		}
+	}
+	return dicts, len(dicts) > 0
+}
+
 // PostForm returns the specified key from a POST urlencoded form or multipart form
 // when it exists, otherwise it returns an empty string `("")`.
 func (c *Context) PostForm(key string) (value string) {
@@ -574,6 +819,42 @@ func (c *Context) get(m map[string][]string, key string) (map[string]string, boo
 	return dicts, exist
 }
 
+// getArray is an internal method and returns a map of all values whose names
+// have the form key[sub] or key[sub][].
+func (c *Context) getArray(m map[string][]string, key string) (map[string][]string, bool) {
+	dicts := make(map[string][]string)
+	var bracketed map[string][]string
+	for k, v := range m {
+		if len(k) <= len(key)+2 || k[:len(key)] != key || k[len(key)] != '[' {
+			continue
+		}
+		sub := k[len(key)+1:]
+		isArray := strings.HasSuffix(sub, "[]")
+		if isArray {
+			sub = sub[:len(sub)-2]
+		}
+		if len(sub) < 2 || sub[len(sub)-1] != ']' {
+			continue
+		}
+		sub = sub[:len(sub)-1]
+		if strings.ContainsAny(sub, "[]") {
+			continue
+		}
+		if isArray {
+			if bracketed == nil {
+				bracketed = make(map[string][]string)
+			}
+			bracketed[sub] = v
+			continue
+		}
+		dicts[sub] = append(dicts[sub], v...)
+	}
+	for sub, v := range bracketed {
+		dicts[sub] = append(dicts[sub], v...)
+	}
+	return dicts, len(dicts) > 0
+}
+
 // FormFile returns the first file for the provided form key.
 func (c *Context) FormFile(name string) (*multipart.FileHeader, error) {
 	if c.Request.MultipartForm == nil {
@@ -595,8 +876,33 @@ func (c *Context) MultipartForm() (*multipart.Form, error) {
 	return c.Request.MultipartForm, err
 }
 
+// MultipartReader returns a reader over the parts of a multipart body, so that large
+// uploads can be streamed part by part instead of being buffered like MultipartForm does.
+// It returns http.ErrNotMultipart if the Content-Type is not multipart, and an error if the
+// body was already consumed by MultipartForm, FormFile or PostForm.
+// Behind MaxBodyBytes, reading past the limit fails with the error of http.MaxBytesReader.
+func (c *Context) MultipartReader() (*multipart.Reader, error) {
+	return c.Request.MultipartReader()
+}
+
 // SaveUploadedFile uploads the form file to specific dst.
 func (c *Context) SaveUploadedFile(file *multipart.FileHeader, dst string) error {
+	return saveUploadedFile(file, dst, io.Discard)
+}
+
+// SaveUploadedFileChecksummed uploads the form file to specific dst like SaveUploadedFile,
+// and returns the hex encoded SHA-256 digest of its content, computed while it is written.
+// The partially written dst is removed on error.
+func (c *Context) SaveUploadedFileChecksummed(file *multipart.FileHeader, dst string) (string, error) {
+	hash := sha256.New()
+	if err := saveUploadedFile(file, dst, hash); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// saveUploadedFile copies the form file to dst and to w.
+func saveUploadedFile(file *multipart.FileHeader, dst string, w io.Writer) error {
 	src, err := file.Open()
 	if err != nil {
 		return err
@@ -611,9 +917,14 @@ func (c *Context) SaveUploadedFile(file *multipart.FileHeader, dst string) error
 	if err != nil {
 		return err
 	}
-	defer out.Close()
 
-	_, err = io.Copy(out, src)
+	_, err = io.Copy(io.MultiWriter(out, w), src)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(dst) //nolint: errcheck
+	}
 	return err
 }
 
@@ -641,6 +952,11 @@ func (c *Context) BindXML(obj any) error {
 	return c.MustBindWith(obj, binding.XML)
 }
 
+// BindForm is a shortcut for c.MustBindWith(obj, binding.Form), see ShouldBindForm.
+func (c *Context) BindForm(obj any) error {
+	return c.MustBindWith(obj, binding.Form)
+}
+
 // BindQuery is a shortcut for c.MustBindWith(obj, binding.Query).
 func (c *Context) BindQuery(obj any) error {
 	return c.MustBindWith(obj, binding.Query)
@@ -656,6 +972,11 @@ func (c *Context) BindTOML(obj any) error {
 	return c.MustBindWith(obj, binding.TOML)
 }
 
+// BindNDJSON is a shortcut for c.MustBindWith(obj, binding.NDJSON).
+func (c *Context) BindNDJSON(obj any) error {
+	return c.MustBindWith(obj, binding.NDJSON)
+}
+
 // BindHeader is a shortcut for c.MustBindWith(obj, binding.Header).
 func (c *Context) BindHeader(obj any) error {
 	return c.MustBindWith(obj, binding.Header)
@@ -672,16 +993,52 @@ func (c *Context) BindUri(obj any) error {
 }
 
 // MustBindWith binds the passed struct pointer using the specified binding engine.
-// It will abort the request with HTTP 400 if any error occurs.
+// It will abort the request with HTTP 400 if any error occurs, or with HTTP 413 when
+// the body is over the limit set by MaxBodyBytes.
 // See the binding package.
 func (c *Context) MustBindWith(obj any, b binding.Binding) error {
 	if err := c.ShouldBindWith(obj, b); err != nil {
+		if mbe := c.bodyTooLarge(err); mbe != nil {
+			abortBodyTooLarge(c, mbe)
+			return err
+		}
 		c.AbortWithError(http.StatusBadRequest, err).SetType(ErrorTypeBind) //nolint: errcheck
 		return err
 	}
 	return nil
 }
 
+// DefaultBindErrorHandler aborts the request with HTTP 400 and a {"error": "..."} JSON body.
+func DefaultBindErrorHandler(c *Context, err error) {
+	c.AbortWithStatusJSON(http.StatusBadRequest, H{"error": err.Error()})
+}
+
+// MustBindJSON binds the passed struct pointer using binding.JSON. If an error occurs
+// it is recorded with ErrorTypeBind, the response is written by Engine.BindErrorHandler
+// (DefaultBindErrorHandler if unset) and false is returned so the handler can return early.
+// A body over the limit set by MaxBodyBytes is answered with 413 instead:
+//
+//	if !c.MustBindJSON(&req) {
+//	    return
+//	}
+func (c *Context) MustBindJSON(obj any) bool {
+	err := c.ShouldBindWith(obj, binding.JSON)
+	if err == nil {
+		return true
+	}
+	if mbe := c.bodyTooLarge(err); mbe != nil {
+		abortBodyTooLarge(c, mbe)
+		return false
+	}
+	c.Error(err).SetType(ErrorTypeBind) //nolint: errcheck
+	handler := DefaultBindErrorHandler
+	if c.engine != nil && c.engine.BindErrorHandler != nil {
+		handler = c.engine.BindErrorHandler
+	}
+	handler(c, err)
+	return false
+}
+
 // ShouldBind checks the Method and Content-Type to select a binding engine automatically,
 // Depending on the "Content-Type" header different bindings are used, for example:
 //
@@ -701,11 +1058,42 @@ func (c *Context) ShouldBindJSON(obj any) error {
 	return c.ShouldBindWith(obj, binding.JSON)
 }
 
+// ShouldBindJSONStrict is like ShouldBindJSON but fails with a *binding.DuplicateKeyError
+// when an object of the body repeats a key, where ShouldBindJSON keeps the last value.
+// It is a shortcut for c.ShouldBindWith(obj, binding.UniqueKeysJSON).
+func (c *Context) ShouldBindJSONStrict(obj any) error {
+	return c.ShouldBindWith(obj, binding.UniqueKeysJSON)
+}
+
+// BindAndValidate binds the JSON body into obj like ShouldBindJSON and returns the problems
+// as a map that can be rendered directly, or nil if there are none. A failing validation rule
+// gives one entry per field keyed by its JSON path, e.g. "items[0].id", as ValidationMessages
+// does; any other error gives a single "body" entry. Besides the validator's rules, the binding
+// tag accepts "hexid" for string ids written like the hexstring=16 tag.
+func (c *Context) BindAndValidate(obj any) map[string]string {
+	err := c.ShouldBindWith(obj, binding.JSON)
+	if err == nil {
+		return nil
+	}
+	if problems := ValidationMessages(err); problems != nil {
+		return problems
+	}
+	return map[string]string{"body": err.Error()}
+}
+
 // ShouldBindXML is a shortcut for c.ShouldBindWith(obj, binding.XML).
 func (c *Context) ShouldBindXML(obj any) error {
 	return c.ShouldBindWith(obj, binding.XML)
 }
 
+// ShouldBindForm is a shortcut for c.ShouldBindWith(obj, binding.Form): it binds the
+// parameters of both the query string and the url-encoded or multipart body, so ?x=1 and
+// a body x=1 fill the same field. When a parameter is in both, the body wins, unless
+// Engine.FormQueryFirst is enabled.
+func (c *Context) ShouldBindForm(obj any) error {
+	return c.ShouldBindWith(obj, binding.Form)
+}
+
 // ShouldBindQuery is a shortcut for c.ShouldBindWith(obj, binding.Query).
 func (c *Context) ShouldBindQuery(obj any) error {
 	return c.ShouldBindWith(obj, binding.Query)
@@ -721,11 +1109,22 @@ func (c *Context) ShouldBindTOML(obj any) error {
 	return c.ShouldBindWith(obj, binding.TOML)
 }
 
+// ShouldBindNDJSON is a shortcut for c.ShouldBindWith(obj, binding.NDJSON).
+// obj must be a pointer to a slice, one element is appended per line.
+func (c *Context) ShouldBindNDJSON(obj any) error {
+	return c.ShouldBindWith(obj, binding.NDJSON)
+}
+
 // ShouldBindHeader is a shortcut for c.ShouldBindWith(obj, binding.Header).
 func (c *Context) ShouldBindHeader(obj any) error {
 	return c.ShouldBindWith(obj, binding.Header)
 }
 
+// ShouldBindHex is a shortcut for c.ShouldBindWith(obj, binding.HexForm).
+func (c *Context) ShouldBindHex(obj any) error {
+	return c.ShouldBindWith(obj, binding.HexForm)
+}
+
 // ShouldBindUri binds the passed struct pointer using the specified binding engine.
 func (c *Context) ShouldBindUri(obj any) error {
 	m := make(map[string][]string)
@@ -735,9 +1134,29 @@ func (c *Context) ShouldBindUri(obj any) error {
 	return binding.Uri.BindUri(m, obj)
 }
 
+// ErrInvalidUTF8Body is returned by the JSON bindings of the Context when
+// Engine.ValidateUTF8Body is enabled and the request body is not valid UTF-8.
+var ErrInvalidUTF8Body = errors.New("gin: request body is not valid UTF-8")
+
 // ShouldBindWith binds the passed struct pointer using the specified binding engine.
 // See the binding package.
 func (c *Context) ShouldBindWith(obj any, b binding.Binding) error {
+	if b == binding.JSON && c.engine != nil && c.engine.DisallowUnknownFields {
+		b = binding.StrictJSON
+	}
+	if b == binding.Form && c.engine != nil && c.engine.FormQueryFirst {
+		b = binding.FormQueryFirst
+	}
+	if (b == binding.JSON || b == binding.StrictJSON || b == binding.UniqueKeysJSON) && c.engine != nil && c.engine.ValidateUTF8Body {
+		// GetRawData puts a reader over the buffered body back in c.Request.Body
+		body, err := c.GetRawData()
+		if err != nil {
+			return err
+		}
+		if !utf8.Valid(body) {
+			return ErrInvalidUTF8Body
+		}
+	}
 	return b.Bind(c.Request, obj)
 }
 
@@ -754,15 +1173,58 @@ func (c *Context) ShouldBindBodyWith(obj any, bb binding.BindingBody) (err error
 		}
 	}
 	if body == nil {
-		body, err = io.ReadAll(c.Request.Body)
-		if err != nil {
-			return err
+		body = []byte{}
+		if c.Request.Body != nil {
+			body, err = io.ReadAll(c.Request.Body)
+			if err != nil {
+				return err
+			}
 		}
 		c.Set(BodyBytesKey, body)
 	}
+	if bb == binding.JSON && c.engine != nil && c.engine.DisallowUnknownFields {
+		bb = binding.StrictJSON
+	}
+	if (bb == binding.JSON || bb == binding.StrictJSON || bb == binding.UniqueKeysJSON) && c.engine != nil && c.engine.ValidateUTF8Body && !utf8.Valid(body) {
+		return ErrInvalidUTF8Body
+	}
 	return bb.BindBody(body, obj)
 }
 
+// ShouldBindBodyWithJSON is a shortcut for c.ShouldBindBodyWith(obj, binding.JSON).
+// The body is decoded with the package JSON decoder every time, so hexstring tags apply
+// to each shape it is bound into.
+func (c *Context) ShouldBindBodyWithJSON(obj any) error {
+	return c.ShouldBindBodyWith(obj, binding.JSON)
+}
+
+// ErrNoBinding is returned by ShouldBindWithFallback when no binding is given.
+var ErrNoBinding = errors.New("gin: no binding to try")
+
+// ShouldBindWithFallback tries each of bindings in order, for clients that send a wrong
+// Content-Type, and returns nil once one of them binds and validates obj, or else the error
+// of the last one. The body is buffered, see GetRawData, so that every binding reads it whole,
+// and obj is reset to its zero value before each retry. As a binding only fails on invalid
+// input, put the most specific first and mark fields `binding:"required"`: a form binding,
+// for one, accepts a JSON body and finds no field in it.
+func (c *Context) ShouldBindWithFallback(obj any, bindings ...binding.Binding) error {
+	err := ErrNoBinding
+	for i, b := range bindings {
+		if _, err = c.GetRawData(); err != nil {
+			return err
+		}
+		if i > 0 {
+			if v := reflect.ValueOf(obj); v.Kind() == reflect.Ptr && !v.IsNil() {
+				v.Elem().Set(reflect.Zero(v.Elem().Type()))
+			}
+		}
+		if err = c.ShouldBindWith(obj, b); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
 // ClientIP implements one best effort algorithm to return the real client IP.
 // It calls c.RemoteIP() under the hood, to check if the remote IP is a trusted proxy or not.
 // If it is it will then try to parse the headers defined in Engine.RemoteIPHeaders (defaulting to [X-Forwarded-For, X-Real-Ip]).
@@ -814,6 +1276,30 @@ func (c *Context) RemoteIP() string {
 	return ip
 }
 
+// addrPortCache holds the netip.AddrPort parsed from raw.
+type addrPortCache struct {
+	parsed   bool
+	raw      string
+	addrPort netip.AddrPort
+	err      error
+}
+
+// RemoteAddrPort parses Request.RemoteAddr, "1.2.3.4:80" or "[::1]:80", into a netip.AddrPort.
+// IPv4-mapped IPv6 addresses are unmapped, so IPv4 clients always get an IPv4 address.
+// The result is cached for the request; it is parsed again only if Request.RemoteAddr changes.
+// Like RemoteIP, it does not consider proxy headers, see ClientIP.
+func (c *Context) RemoteAddrPort() (netip.AddrPort, error) {
+	raw := c.Request.RemoteAddr
+	if cache := &c.remoteAddrPort; !cache.parsed || cache.raw != raw {
+		addrPort, err := netip.ParseAddrPort(strings.TrimSpace(raw))
+		if err == nil {
+			addrPort = netip.AddrPortFrom(addrPort.Addr().Unmap(), addrPort.Port())
+		}
+		*cache = addrPortCache{parsed: true, raw: raw, addrPort: addrPort, err: err}
+	}
+	return c.remoteAddrPort.addrPort, c.remoteAddrPort.err
+}
+
 // ContentType returns the Content-Type header of the request.
 func (c *Context) ContentType() string {
 	return filterFlags(c.requestHeader("Content-Type"))
@@ -866,14 +1352,43 @@ func (c *Context) Header(key, value string) {
 	c.Writer.Header().Set(key, value)
 }
 
+// SetHeaderIfAbsent works like Header but leaves the response header untouched when
+// it is already set, so the first middleware setting it wins. A header removed with
+// c.Header(key, "") counts as absent.
+func (c *Context) SetHeaderIfAbsent(key, value string) {
+	if len(c.Writer.Header().Values(key)) > 0 {
+		return
+	}
+	c.Header(key, value)
+}
+
 // GetHeader returns value from request headers.
 func (c *Context) GetHeader(key string) string {
 	return c.requestHeader(key)
 }
 
 // GetRawData returns stream data.
+// The body is read once and kept under BodyBytesKey, which ShouldBindBodyWith shares, so it
+// can be called several times; c.Request.Body is replaced by a fresh reader over the same
+// bytes each time, so that binding it afterwards still works. The returned slice must not
+// be modified. Behind MaxBodyBytes, a body over the limit fails and is not kept.
 func (c *Context) GetRawData() ([]byte, error) {
-	return io.ReadAll(c.Request.Body)
+	if cb, ok := c.Get(BodyBytesKey); ok {
+		if body, ok := cb.([]byte); ok {
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+			return body, nil
+		}
+	}
+	body := []byte{}
+	if c.Request.Body != nil {
+		var err error
+		if body, err = io.ReadAll(c.Request.Body); err != nil {
+			return nil, err
+		}
+	}
+	c.Set(BodyBytesKey, body)
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
 }
 
 // SetSameSite with cookie
@@ -900,6 +1415,19 @@ func (c *Context) SetCookie(name, value string, maxAge int, path, domain string,
 	})
 }
 
+// SetCookieStruct adds a Set-Cookie header for cookie as is, giving control over all of
+// its attributes, such as a per-cookie SameSite or Partitioned. Unlike SetCookie the value
+// is not escaped and the path is not defaulted; only an unset SameSite falls back to the
+// one given to SetSameSite. Invalid cookies may be silently dropped.
+func (c *Context) SetCookieStruct(cookie *http.Cookie) {
+	if cookie.SameSite == 0 && c.sameSite != 0 {
+		cp := *cookie
+		cp.SameSite = c.sameSite
+		cookie = &cp
+	}
+	http.SetCookie(c.Writer, cookie)
+}
+
 // Cookie returns the named cookie provided in the request or
 // ErrNoCookie if not found. And return the named cookie is unescaped.
 // If multiple cookies match the given name, only one cookie will
@@ -913,6 +1441,31 @@ func (c *Context) Cookie(name string) (string, error) {
 	return val, nil
 }
 
+// CookieInt returns the named cookie provided in the request parsed as a decimal int.
+// It returns http.ErrNoCookie if not found, or the *strconv.NumError if it does not parse.
+func (c *Context) CookieInt(name string) (int, error) {
+	val, err := c.Cookie(name)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(val)
+}
+
+// CookieBool returns the named cookie provided in the request parsed with strconv.ParseBool.
+// It returns http.ErrNoCookie if not found, or the *strconv.NumError if it does not parse.
+func (c *Context) CookieBool(name string) (bool, error) {
+	val, err := c.Cookie(name)
+	if err != nil {
+		return false, err
+	}
+	return strconv.ParseBool(val)
+}
+
+// Cookies returns all the cookies provided in the request, with their values as sent.
+func (c *Context) Cookies() []*http.Cookie {
+	return c.Request.Cookies()
+}
+
 // Render writes the response headers and calls render.Render to render data.
 func (c *Context) Render(code int, r render.Render) {
 	c.Status(code)
@@ -953,22 +1506,70 @@ func (c *Context) SecureJSON(code int, obj any) {
 	c.Render(code, render.SecureJSON{Prefix: c.engine.secureJSONPrefix, Data: obj})
 }
 
+// ErrInvalidJSONPCallback is recorded by JSONP when the callback query parameter is not a safe name.
+var ErrInvalidJSONPCallback = errors.New("gin: invalid JSONP callback")
+
+// regJSONPCallback matches the JSONP callback names accepted by Context.JSONP.
+var regJSONPCallback = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$.]*$`)
+
 // JSONP serializes the given struct as JSON into the response body.
 // It adds padding to response body to request data from a server residing in a different domain than the client.
 // It also sets the Content-Type as "application/javascript".
+// A callback that does not match ^[A-Za-z_$][A-Za-z0-9_$.]*$ is never echoed: the request is
+// aborted with HTTP 400 and ErrInvalidJSONPCallback is pushed to c.Errors instead.
 func (c *Context) JSONP(code int, obj any) {
 	callback := c.DefaultQuery("callback", "")
 	if callback == "" {
 		c.Render(code, render.JSON{Data: obj})
 		return
 	}
+	if !regJSONPCallback.MatchString(callback) {
+		c.AbortWithError(http.StatusBadRequest, ErrInvalidJSONPCallback).SetType(ErrorTypePublic) //nolint: errcheck
+		return
+	}
 	c.Render(code, render.JsonpJSON{Callback: callback, Data: obj})
 }
 
 // JSON serializes the given struct as JSON into the response body.
 // It also sets the Content-Type as "application/json".
+// obj goes through Engine.ResponseWrapper first, if set.
 func (c *Context) JSON(code int, obj any) {
-	c.Render(code, render.JSON{Data: obj})
+	c.Render(code, render.JSON{Data: c.wrapResponse(code, obj)})
+}
+
+// JSONBytes returns the body c.JSON(http.StatusOK, obj) would write, without writing
+// anything, e.g. to derive an ETag from it first:
+//
+//	body, err := c.JSONBytes(obj)
+//	...
+//	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+func (c *Context) JSONBytes(obj any) ([]byte, error) {
+	return json.Marshal(c.wrapResponse(http.StatusOK, obj))
+}
+
+// wrapResponse returns what Engine.ResponseWrapper makes of obj.
+func (c *Context) wrapResponse(code int, obj any) any {
+	if c.engine == nil || c.engine.ResponseWrapper == nil {
+		return obj
+	}
+	if c.engine.ResponseWrapperSkipErrors && code >= http.StatusBadRequest {
+		return obj
+	}
+	return c.engine.ResponseWrapper(c, obj)
+}
+
+// HexJSON serializes the given struct as JSON into the response body, rendering
+// every int64 as a 16 digit hex string regardless of struct tags.
+// It also sets the Content-Type as "application/json".
+func (c *Context) HexJSON(code int, obj any) {
+	c.Render(code, render.HexJSON{Data: obj})
+}
+
+// JSONWith serializes the given struct as JSON into the response body using the given marshaler,
+// e.g. a differently configured jsoniter.API, instead of the package default.
+// It also sets the Content-Type as "application/json".
+func (c *Context) JSONWith(code int, marshaler render.JSONMarshaler, obj any) {
+	c.Render(code, render.CustomJSON{Marshaler: marshaler, Data: obj})
 }
 
 // AsciiJSON serializes the given struct as JSON into the response body with unicode to ASCII string.
@@ -979,8 +1580,9 @@ func (c *Context) AsciiJSON(code int, obj any) {
 
 // PureJSON serializes the given struct as JSON into the response body.
 // PureJSON, unlike JSON, does not replace special html characters with their unicode entities.
+// obj goes through Engine.ResponseWrapper first, if set.
 func (c *Context) PureJSON(code int, obj any) {
-	c.Render(code, render.PureJSON{Data: obj})
+	c.Render(code, render.PureJSON{Data: c.wrapResponse(code, obj)})
 }
 
 // XML serializes the given struct as XML into the response body.
@@ -999,11 +1601,6 @@ func (c *Context) TOML(code int, obj any) {
 	c.Render(code, render.TOML{Data: obj})
 }
 
-// ProtoBuf serializes the given struct as ProtoBuf into the response body.
-func (c *Context) ProtoBuf(code int, obj any) {
-	c.Render(code, render.ProtoBuf{Data: obj})
-}
-
 // String writes the given string into the response body.
 func (c *Context) String(code int, format string, values ...any) {
 	c.Render(code, render.String{Format: format, Data: values})
@@ -1069,6 +1666,35 @@ func (c *Context) FileAttachment(filepath, filename string) {
 	http.ServeFile(c.Writer, c.Request, filepath)
 }
 
+// FileAttachmentFromReader copies reader into the body stream as an attachment named
+// filename, so generated content can be downloaded without a file on disk.
+// Content-Length is set when size >= 0; with a negative size it is omitted and the
+// response is sent chunked. Non-ASCII filenames are sent as an RFC 5987 filename*
+// parameter, with a filename fallback where those characters are replaced by '_'.
+// An empty contentType defaults to application/octet-stream.
+func (c *Context) FileAttachmentFromReader(reader io.Reader, size int64, filename, contentType string) {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.DataFromReader(http.StatusOK, size, contentType, reader, map[string]string{
+		"Content-Disposition": attachmentDisposition(filename),
+	})
+}
+
+// attachmentDisposition returns the Content-Disposition value of an attachment named filename.
+func attachmentDisposition(filename string) string {
+	if isASCII(filename) {
+		return `attachment; filename="` + escapeQuotes(filename) + `"`
+	}
+	fallback := strings.Map(func(r rune) rune {
+		if r > unicode.MaxASCII {
+			return '_'
+		}
+		return r
+	}, filename)
+	return `attachment; filename="` + escapeQuotes(fallback) + `"; filename*=UTF-8''` + encodeExtValue(filename)
+}
+
 // SSEvent writes a Server-Sent Event into the body stream.
 func (c *Context) SSEvent(name string, message any) {
 	c.Render(-1, sse.Event{
@@ -1077,6 +1703,30 @@ func (c *Context) SSEvent(name string, message any) {
 	})
 }
 
+// SSEventJSON writes a Server-Sent Event into the body stream and flushes it. Unlike
+// SSEvent, message is always encoded with the package JSON encoder, strings included,
+// so hexstring fields apply. The event stream, Cache-Control and Connection headers
+// are set on the first event.
+func (c *Context) SSEventJSON(name string, message any) error {
+	return render.SSE{Event: name, Data: message}.Render(c.Writer)
+}
+
+// ErrPushNotSupported is returned by Context.Push when the connection can not push,
+// e.g. for HTTP/1.x requests.
+var ErrPushNotSupported = errors.New("gin: server push is not supported by this connection")
+
+// Push initiates an HTTP/2 server push of target, see http.Pusher. It returns
+// ErrPushNotSupported when c.Writer is not an http.Pusher, and the error of the
+// pusher otherwise, such as http.ErrNotSupported when the client disabled push.
+// Push should be called before writing the response body.
+func (c *Context) Push(target string, opts *http.PushOptions) error {
+	pusher := c.Writer.Pusher()
+	if pusher == nil {
+		return ErrPushNotSupported
+	}
+	return pusher.Push(target, opts)
+}
+
 // Stream sends a streaming response and returns a boolean
 // indicates "Is client disconnected in middle of stream"
 func (c *Context) Stream(step func(w io.Writer) bool) bool {
@@ -1096,6 +1746,124 @@ func (c *Context) Stream(step func(w io.Writer) bool) bool {
 	}
 }
 
+// StreamCounted sends a streaming response like Stream, calling step until it returns false
+// or an error, and flushing the writer after each call if it supports flushing. It returns
+// the number of bytes written and the first error: the one returned by step, or by a write
+// or a flush, which also stops the stream, or the error of the request context when the
+// client went away. Writes after a failed one are not attempted.
+func (c *Context) StreamCounted(step func(w io.Writer) (bool, error)) (int64, error) {
+	cw := &countingWriter{w: c.Writer}
+	var done <-chan struct{}
+	if c.Request != nil {
+		done = c.Request.Context().Done()
+	}
+	for {
+		select {
+		case <-done:
+			return cw.n, c.Request.Context().Err()
+		default:
+		}
+		keepOpen, err := step(cw)
+		if err == nil {
+			err = cw.err
+		}
+		if err == nil {
+			err = flushWriter(c.Writer)
+		}
+		if err != nil || !keepOpen {
+			return cw.n, err
+		}
+	}
+}
+
+// countingWriter counts the bytes written to w and keeps the first write error.
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
+
+// flushWriter flushes w, returning the flush error when w reports it, see
+// responseWriter.FlushError. Writers that cannot flush are left alone.
+func flushWriter(w ResponseWriter) error {
+	if fw, ok := w.(interface{ FlushError() error }); ok {
+		if err := fw.FlushError(); !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+		return nil
+	}
+	w.Flush()
+	return nil
+}
+
+// streamJSONFlushEvery is the number of elements StreamJSON writes between flushes.
+const streamJSONFlushEvery = 100
+
+// StreamJSON writes the values received from ch as a JSON array, marshaling each one
+// as it arrives instead of buffering the whole slice. The array is closed when ch is
+// closed; a channel closed without values gives "[]". The response is flushed every
+// few elements.
+// If a value fails to marshal, or the client goes away, the stream stops: the error
+// is added to c.Errors, the context is aborted and the body is left truncated so the
+// client cannot mistake it for a complete array. The sender should stop on
+// c.Request.Context().Done(), as StreamJSON no longer receives from ch by then.
+func (c *Context) StreamJSON(code int, ch <-chan any) {
+	c.Status(code)
+	c.Header("Content-Type", MIMEJSON+"; charset=utf-8")
+	w := c.Writer
+	done := c.Request.Context().Done()
+
+	if _, err := w.WriteString("["); err != nil {
+		c.Error(err) //nolint: errcheck
+		c.Abort()
+		return
+	}
+	for n := 0; ; n++ {
+		var (
+			v  any
+			ok bool
+		)
+		select {
+		case v, ok = <-ch:
+		case <-done:
+			c.Error(c.Request.Context().Err()) //nolint: errcheck
+			c.Abort()
+			return
+		}
+		if !ok {
+			break
+		}
+		b, err := json.Marshal(v)
+		if err == nil && n > 0 {
+			_, err = w.WriteString(",")
+		}
+		if err == nil {
+			_, err = w.Write(b)
+		}
+		if err != nil {
+			c.Error(err) //nolint: errcheck
+			c.Abort()
+			w.Flush()
+			return
+		}
+		if (n+1)%streamJSONFlushEvery == 0 {
+			w.Flush()
+		}
+	}
+	w.WriteString("]") //nolint: errcheck
+	w.Flush()
+}
+
 /************************************/
 /******** CONTENT NEGOTIATION *******/
 /************************************/
@@ -1140,40 +1908,73 @@ func (c *Context) Negotiate(code int, config Negotiate) {
 	}
 }
 
-// NegotiateFormat returns an acceptable Accept format.
+// NegotiateJSON serializes the given struct as JSON into the response body, choosing how
+// hexstring int64 fields are rendered from the "ids" parameter of the application/json
+// media type in the Accept header:
+//
+//	Accept: application/json; ids=hex    hexstring fields as hex strings (default)
+//	Accept: application/json; ids=dec    hexstring fields as decimal numbers
+//
+// The parameter value is case-insensitive. A missing or unknown value keeps the hex rendering.
+// Either way the Engine.ResponseWrapper applies, as for JSON.
+func (c *Context) NegotiateJSON(code int, obj any) {
+	if acceptParam(c.requestHeader("Accept"), MIMEJSON, "ids") == "dec" {
+		c.Render(code, render.DecimalJSON{Data: c.wrapResponse(code, obj)})
+		return
+	}
+	c.JSON(code, obj)
+}
+
+// NegotiateFormat returns the offered format the client prefers, or "" if none is acceptable.
+// The media ranges of the Accept header are ranked by q-value, and an offer takes the rank of
+// the most specific range matching it, e.g. "text/html" over "text/*" over "*/*", so a range
+// with q=0 excludes the offers it matches. Ties go to the earlier offer, as does a request
+// without Accept header, while one whose ranges all have q=0 accepts nothing.
 func (c *Context) NegotiateFormat(offered ...string) string {
 	assert1(len(offered) > 0, "you must provide at least one offer")
 
 	if c.Accepted == nil {
-		c.Accepted = parseAccept(c.requestHeader("Accept"))
+		c.Accepted, c.acceptRejected = parseAccept(c.requestHeader("Accept"))
 	}
 	if len(c.Accepted) == 0 {
-		return offered[0]
-	}
-	for _, accepted := range c.Accepted {
-		for _, offer := range offered {
-			// According to RFC 2616 and RFC 2396, non-ASCII characters are not allowed in headers,
-			// therefore we can just iterate over the string without casting it into []rune
-			i := 0
-			for ; i < len(accepted) && i < len(offer); i++ {
-				if accepted[i] == '*' || offer[i] == '*' {
-					return offer
-				}
-				if accepted[i] != offer[i] {
-					break
-				}
-			}
-			if i == len(accepted) {
-				return offer
+		if len(c.acceptRejected) == 0 {
+			return offered[0]
+		}
+		return ""
+	}
+	best, bestRank := "", len(c.Accepted)
+	for _, offer := range offered {
+		rank, specificity := -1, -1
+		for i, accepted := range c.Accepted {
+			if s := mediaRangeSpecificity(accepted); s > specificity && mediaRangeMatch(accepted, offer) {
+				rank, specificity = i, s
 			}
 		}
+		if rank < 0 || rank >= bestRank {
+			continue
+		}
+		if c.rejectsFormat(offer, specificity) {
+			continue
+		}
+		best, bestRank = offer, rank
 	}
-	return ""
+	return best
 }
 
-// SetAccepted sets Accept header data.
+// rejectsFormat reports whether a range with q=0 at least as specific as the given one matches offer.
+func (c *Context) rejectsFormat(offer string, specificity int) bool {
+	for _, rejected := range c.acceptRejected {
+		if mediaRangeSpecificity(rejected) >= specificity && mediaRangeMatch(rejected, offer) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetAccepted sets Accept header data, most preferred format first.
 func (c *Context) SetAccepted(formats ...string) {
 	c.Accepted = formats
+	c.acceptRejected = nil
 }
 
 /************************************/
@@ -1187,25 +1988,29 @@ func (c *Context) hasRequestContext() bool {
 	return hasFallback && hasRequestContext
 }
 
-// Deadline returns that there is no deadline (ok==false) when c.Request has no Context.
+// Deadline returns the deadline of c.Request.Context(), or that there is no deadline (ok==false)
+// when c.Request has no Context, Engine.ContextWithFallback is disabled or c is a Copy.
 func (c *Context) Deadline() (deadline time.Time, ok bool) {
-	if !c.hasRequestContext() {
+	if c.detached || !c.hasRequestContext() {
 		return
 	}
 	return c.Request.Context().Deadline()
 }
 
-// Done returns nil (chan which will wait forever) when c.Request has no Context.
+// Done returns the channel of c.Request.Context(), closed when the request is cancelled, or nil
+// (chan which will wait forever) when c.Request has no Context, Engine.ContextWithFallback is
+// disabled or c is a Copy.
 func (c *Context) Done() <-chan struct{} {
-	if !c.hasRequestContext() {
+	if c.detached || !c.hasRequestContext() {
 		return nil
 	}
 	return c.Request.Context().Done()
 }
 
-// Err returns nil when c.Request has no Context.
+// Err returns the error of c.Request.Context(), or nil when c.Request has no Context,
+// Engine.ContextWithFallback is disabled or c is a Copy.
 func (c *Context) Err() error {
-	if !c.hasRequestContext() {
+	if c.detached || !c.hasRequestContext() {
 		return nil
 	}
 	return c.Request.Context().Err()
@@ -0,0 +1,28 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+//go:build go1.23
+
+package gin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextSetCookieStructPartitioned(t *testing.T) {
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.SetCookieStruct(&http.Cookie{
+		Name:        "session",
+		Value:       "gin",
+		Path:        "/",
+		Secure:      true,
+		SameSite:    http.SameSiteNoneMode,
+		Partitioned: true,
+	})
+	assert.Equal(t, "session=gin; Path=/; Secure; SameSite=None; Partitioned", c.Writer.Header().Get("Set-Cookie"))
+}
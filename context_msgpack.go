@@ -0,0 +1,32 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+//go:build !nomsgpack
+
+package gin
+
+import (
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+)
+
+// MIMEMSGPACK is the MessagePack Content-Type, binding.Default also accepts
+// binding.MIMEMSGPACK2.
+const MIMEMSGPACK = binding.MIMEMSGPACK
+
+// BindMsgPack is a shortcut for c.MustBindWith(obj, binding.MsgPack).
+func (c *Context) BindMsgPack(obj any) error {
+	return c.MustBindWith(obj, binding.MsgPack)
+}
+
+// ShouldBindMsgPack is a shortcut for c.ShouldBindWith(obj, binding.MsgPack).
+func (c *Context) ShouldBindMsgPack(obj any) error {
+	return c.ShouldBindWith(obj, binding.MsgPack)
+}
+
+// MsgPack serializes the given struct as MessagePack into the response body.
+// Field names are read from the codec struct tag, then from the json one.
+func (c *Context) MsgPack(code int, obj any) {
+	c.Render(code, render.MsgPack{Data: obj})
+}
@@ -0,0 +1,82 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+//go:build !nomsgpack
+
+package gin
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
+)
+
+type msgpackAddress struct {
+	City string   `codec:"city"`
+	Tags []string `json:"tags"`
+}
+
+type msgpackUser struct {
+	ID      int64             `codec:"id" binding:"required"`
+	Name    string            `codec:"name"`
+	Address msgpackAddress    `codec:"address"`
+	Others  []*msgpackAddress `codec:"others,omitempty"`
+	Meta    map[string]int    `codec:"meta"`
+}
+
+func TestContextMsgPackRoundTrip(t *testing.T) {
+	user := msgpackUser{
+		ID:      7,
+		Name:    "gin",
+		Address: msgpackAddress{City: "Taipei", Tags: []string{"home"}},
+		Others:  []*msgpackAddress{{City: "Tokyo"}},
+		Meta:    map[string]int{"visits": 3},
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	c.MsgPack(http.StatusCreated, user)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "application/msgpack; charset=utf-8", w.Header().Get("Content-Type"))
+
+	var raw map[string]any
+	require.NoError(t, codec.NewDecoderBytes(w.Body.Bytes(), &codec.MsgpackHandle{}).Decode(&raw))
+	assert.Contains(t, raw, "id")
+	assert.Contains(t, raw, "name")
+	assert.Contains(t, raw, "address")
+
+	for _, contentType := range []string{binding.MIMEMSGPACK, binding.MIMEMSGPACK2} {
+		c, _ = CreateTestContext(httptest.NewRecorder())
+		c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewReader(w.Body.Bytes()))
+		c.Request.Header.Set("Content-Type", contentType)
+
+		var got msgpackUser
+		require.NoError(t, c.ShouldBind(&got))
+		assert.Equal(t, user, got)
+	}
+}
+
+func TestContextShouldBindMsgPack(t *testing.T) {
+	var body bytes.Buffer
+	require.NoError(t, codec.NewEncoder(&body, &codec.MsgpackHandle{}).Encode(map[string]any{"name": "gin"}))
+
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewReader(body.Bytes()))
+	var user msgpackUser
+	assert.Error(t, c.ShouldBindMsgPack(&user))
+	assert.Equal(t, "gin", user.Name)
+	assert.False(t, c.IsAborted())
+
+	w := httptest.NewRecorder()
+	c, _ = CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewReader(body.Bytes()))
+	assert.Error(t, c.BindMsgPack(&msgpackUser{}))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,31 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+//go:build !noprotobuf
+
+package gin
+
+import (
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+	"google.golang.org/protobuf/proto"
+)
+
+// MIMEPROTOBUF is the Protocol Buffers Content-Type, binding.Default binds it with binding.ProtoBuf.
+const MIMEPROTOBUF = binding.MIMEPROTOBUF
+
+// BindProtoBuf is a shortcut for c.MustBindWith(msg, binding.ProtoBuf).
+func (c *Context) BindProtoBuf(msg proto.Message) error {
+	return c.MustBindWith(msg, binding.ProtoBuf)
+}
+
+// ShouldBindProtoBuf is a shortcut for c.ShouldBindWith(msg, binding.ProtoBuf).
+func (c *Context) ShouldBindProtoBuf(msg proto.Message) error {
+	return c.ShouldBindWith(msg, binding.ProtoBuf)
+}
+
+// ProtoBuf serializes the given message as ProtoBuf into the response body.
+func (c *Context) ProtoBuf(code int, msg proto.Message) {
+	c.Render(code, render.ProtoBuf{Data: msg})
+}
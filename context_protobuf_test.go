@@ -0,0 +1,71 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+//go:build !noprotobuf
+
+package gin
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	testdata "github.com/gin-gonic/gin/testdata/protoexample"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// TestContextRenderProtoBuf tests that the response is serialized as ProtoBuf
+// and Content-Type is set to application/x-protobuf
+// and we just use the example protobuf to check if the response is correct
+func TestContextRenderProtoBuf(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+
+	reps := []int64{int64(1), int64(2)}
+	label := "test"
+	data := &testdata.Test{
+		Label: &label,
+		Reps:  reps,
+	}
+
+	c.ProtoBuf(http.StatusCreated, data)
+
+	protoData, err := proto.Marshal(data)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, string(protoData), w.Body.String())
+	assert.Equal(t, "application/x-protobuf", w.Header().Get("Content-Type"))
+}
+
+func TestContextProtoBufRoundTrip(t *testing.T) {
+	label := "gin"
+	msg := &testdata.Test{Label: &label, Reps: []int64{1, 2, 3}}
+
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	c.ProtoBuf(http.StatusOK, msg)
+	assert.Equal(t, MIMEPROTOBUF, w.Header().Get("Content-Type"))
+
+	c, _ = CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewReader(w.Body.Bytes()))
+	c.Request.Header.Set("Content-Type", MIMEPROTOBUF)
+	var got testdata.Test
+	require.NoError(t, c.ShouldBind(&got))
+	assert.True(t, proto.Equal(msg, &got))
+
+	c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewReader(w.Body.Bytes()))
+	got = testdata.Test{}
+	require.NoError(t, c.ShouldBindProtoBuf(&got))
+	assert.True(t, proto.Equal(msg, &got))
+
+	w = httptest.NewRecorder()
+	c, _ = CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewBufferString("\xff"))
+	assert.Error(t, c.BindProtoBuf(&testdata.Test{}))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -7,6 +7,7 @@ package gin
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"html/template"
@@ -17,7 +18,9 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 	"testing"
@@ -25,9 +28,9 @@ import (
 
 	"github.com/gin-contrib/sse"
 	"github.com/gin-gonic/gin/binding"
-	testdata "github.com/gin-gonic/gin/testdata/protoexample"
+	"github.com/go-playground/validator/v10"
+	jsoniter "github.com/json-iterator/go"
 	"github.com/stretchr/testify/assert"
-	"google.golang.org/protobuf/proto"
 )
 
 var _ context.Context = (*Context)(nil)
@@ -111,6 +114,88 @@ func TestContextMultipartForm(t *testing.T) {
 	assert.NoError(t, c.SaveUploadedFile(f.File["file"][0], "test"))
 }
 
+func TestContextSaveUploadedFileChecksummed(t *testing.T) {
+	buf := new(bytes.Buffer)
+	mw := multipart.NewWriter(buf)
+	w, err := mw.CreateFormFile("file", "hello.txt")
+	if assert.NoError(t, err) {
+		_, err = w.Write([]byte("hello world\n"))
+		assert.NoError(t, err)
+	}
+	mw.Close()
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("POST", "/", buf)
+	c.Request.Header.Set("Content-Type", mw.FormDataContentType())
+	f, err := c.FormFile("file")
+	assert.NoError(t, err)
+
+	dst := filepath.Join(t.TempDir(), "uploads", "hello.txt")
+	sum, err := c.SaveUploadedFileChecksummed(f, dst)
+	assert.NoError(t, err)
+	// sha256sum of "hello world\n"
+	assert.Equal(t, "a948904f2f0f479b8f8197694b30184b0d2ed1c1cd2a1ec0fb85d299a192a447", sum)
+	data, err := os.ReadFile(dst)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world\n", string(data))
+
+	sum, err = c.SaveUploadedFileChecksummed(f, t.TempDir())
+	assert.Error(t, err)
+	assert.Empty(t, sum)
+	_, err = c.SaveUploadedFileChecksummed(&multipart.FileHeader{Filename: "file"}, dst)
+	assert.Error(t, err)
+}
+
+func TestContextMultipartReader(t *testing.T) {
+	const size = 8 << 20
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		assert.NoError(t, mw.WriteField("foo", "bar"))
+		w, err := mw.CreateFormFile("file", "large.bin")
+		if assert.NoError(t, err) {
+			_, err = io.Copy(w, strings.NewReader(strings.Repeat("a", size)))
+			assert.NoError(t, err)
+		}
+		pw.CloseWithError(mw.Close())
+	}()
+
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.engine.MaxMultipartMemory = 1 << 10
+	c.Request, _ = http.NewRequest(http.MethodPost, "/", pr)
+	c.Request.Header.Set("Content-Type", mw.FormDataContentType())
+
+	mr, err := c.MultipartReader()
+	assert.NoError(t, err)
+	sizes := map[string]int64{}
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(t, err) {
+			return
+		}
+		n, err := io.Copy(io.Discard, part)
+		assert.NoError(t, err)
+		sizes[part.FormName()] = n
+	}
+	assert.Equal(t, map[string]int64{"foo": 3, "file": size}, sizes)
+}
+
+func TestContextMultipartReaderErrors(t *testing.T) {
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest(http.MethodPost, "/", strings.NewReader("foo=bar"))
+	c.Request.Header.Set("Content-Type", MIMEPOSTForm)
+	_, err := c.MultipartReader()
+	assert.ErrorIs(t, err, http.ErrNotMultipart)
+
+	c.Request = createMultipartRequest()
+	_, err = c.MultipartForm()
+	assert.NoError(t, err)
+	_, err = c.MultipartReader()
+	assert.Error(t, err)
+}
+
 func TestSaveUploadedOpenFailed(t *testing.T) {
 	buf := new(bytes.Buffer)
 	mw := multipart.NewWriter(buf)
@@ -206,6 +291,32 @@ func TestContextSetGet(t *testing.T) {
 	assert.Panics(t, func() { c.MustGet("no_exist") })
 }
 
+func TestContextGetTyped(t *testing.T) {
+	type user struct{ Name string }
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Set("user", &user{Name: "gin"})
+	c.Set("tenant", "acme")
+	c.Set("nil", nil)
+
+	u, ok := GetTyped[*user](c, "user")
+	assert.True(t, ok)
+	assert.Equal(t, "gin", u.Name)
+	assert.Equal(t, "gin", MustGetTyped[*user](c, "user").Name)
+	s, ok := GetTyped[fmt.Stringer](c, "tenant")
+	assert.False(t, ok)
+	assert.Nil(t, s)
+
+	n, ok := GetTyped[int](c, "tenant")
+	assert.False(t, ok)
+	assert.Zero(t, n)
+	assert.PanicsWithValue(t, `Key "tenant" holds string, not int`, func() { MustGetTyped[int](c, "tenant") })
+	assert.PanicsWithValue(t, `Key "nil" holds <nil>, not *gin.user`, func() { MustGetTyped[*user](c, "nil") })
+
+	_, ok = GetTyped[string](c, "missing")
+	assert.False(t, ok)
+	assert.PanicsWithValue(t, `Key "missing" does not exist`, func() { MustGetTyped[string](c, "missing") })
+}
+
 func TestContextSetGetValues(t *testing.T) {
 	c, _ := CreateTestContext(httptest.NewRecorder())
 	c.Set("string", "this is a string")
@@ -521,6 +632,61 @@ func TestContextQueryAndPostForm(t *testing.T) {
 	assert.Equal(t, 0, len(dicts))
 }
 
+func TestContextQueryArrayBrackets(t *testing.T) {
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("GET", "/?tags[]=b&tags=a&tags[]=c&only[]=x&plain=1", nil)
+
+	// disabled by default
+	assert.Equal(t, []string{"a"}, c.QueryArray("tags"))
+	_, ok := c.GetQueryArray("only")
+	assert.False(t, ok)
+
+	c.engine.QueryArrayBrackets = true
+	assert.Equal(t, []string{"a", "b", "c"}, c.QueryArray("tags"))
+	values, ok := c.GetQueryArray("only")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"x"}, values)
+	assert.Equal(t, []string{"1"}, c.QueryArray("plain"))
+	assert.Equal(t, []string{"b", "c"}, c.QueryArray("tags[]"))
+	assert.Equal(t, "a", c.Query("tags"))
+	// the cached values are not modified
+	assert.Equal(t, []string{"a"}, c.queryCache["tags"])
+}
+
+func TestContextQueryMapArray(t *testing.T) {
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("GET",
+		"/?meta[x][]=2&meta[x]=1&meta[x][]=3&meta[y]=4&meta[]=no&meta[a][b]=no&meta[z]w=no&meta[]]=no&metax[q]=no&tags[]=no", nil)
+
+	dicts, ok := c.GetQueryMapArray("meta")
+	assert.True(t, ok)
+	assert.Equal(t, map[string][]string{"x": {"1", "2", "3"}, "y": {"4"}}, dicts)
+	assert.Equal(t, dicts, c.QueryMapArray("meta"))
+
+	dicts, ok = c.GetQueryMapArray("tags")
+	assert.False(t, ok)
+	assert.Empty(t, dicts)
+
+	dicts, ok = c.GetQueryMapArray("nokey")
+	assert.False(t, ok)
+	assert.Empty(t, dicts)
+}
+
+func TestContextQueryPrefixMap(t *testing.T) {
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("GET", "/?filter.name=bob&filter.age=30&filter.age=40&filter.=x&sort=asc", nil)
+
+	dicts, ok := c.GetQueryPrefixMap("filter.")
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{"name": "bob", "age": "30"}, dicts)
+	assert.Equal(t, dicts, c.QueryPrefixMap("filter."))
+
+	dicts, ok = c.GetQueryPrefixMap("page.")
+	assert.False(t, ok)
+	assert.NotNil(t, dicts)
+	assert.Empty(t, dicts)
+}
+
 func TestContextPostFormMultipart(t *testing.T) {
 	c, _ := CreateTestContext(httptest.NewRecorder())
 	c.Request = createMultipartRequest()
@@ -627,6 +793,27 @@ func TestContextSetCookiePathEmpty(t *testing.T) {
 	assert.Equal(t, "user=gin; Path=/; Domain=localhost; Max-Age=1; HttpOnly; Secure; SameSite=Lax", c.Writer.Header().Get("Set-Cookie"))
 }
 
+func TestContextSetCookieStruct(t *testing.T) {
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookieStruct(&http.Cookie{
+		Name:     "user",
+		Value:    "gin",
+		Path:     "/api",
+		MaxAge:   1,
+		Secure:   true,
+		SameSite: http.SameSiteNoneMode,
+	})
+	cookie := &http.Cookie{Name: "lang", Value: "en"}
+	c.SetCookieStruct(cookie)
+
+	assert.Equal(t, []string{
+		"user=gin; Path=/api; Max-Age=1; Secure; SameSite=None",
+		"lang=en; SameSite=Lax",
+	}, c.Writer.Header().Values("Set-Cookie"))
+	assert.Equal(t, http.SameSite(0), cookie.SameSite)
+}
+
 func TestContextGetCookie(t *testing.T) {
 	c, _ := CreateTestContext(httptest.NewRecorder())
 	c.Request, _ = http.NewRequest("GET", "/get", nil)
@@ -638,6 +825,42 @@ func TestContextGetCookie(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestContextGetCookieTyped(t *testing.T) {
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("GET", "/get", nil)
+	c.Request.Header.Set("Cookie", "id=42; remember=true; name=gin; flag=1")
+
+	id, err := c.CookieInt("id")
+	assert.NoError(t, err)
+	assert.Equal(t, 42, id)
+	remember, err := c.CookieBool("remember")
+	assert.NoError(t, err)
+	assert.True(t, remember)
+	flag, err := c.CookieBool("flag")
+	assert.NoError(t, err)
+	assert.True(t, flag)
+
+	_, err = c.CookieInt("name")
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+	_, err = c.CookieBool("name")
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+
+	_, err = c.CookieInt("nokey")
+	assert.ErrorIs(t, err, http.ErrNoCookie)
+	_, err = c.CookieBool("nokey")
+	assert.ErrorIs(t, err, http.ErrNoCookie)
+
+	cookies := c.Cookies()
+	if assert.Len(t, cookies, 4) {
+		assert.Equal(t, "id", cookies[0].Name)
+		assert.Equal(t, "42", cookies[0].Value)
+		assert.Equal(t, "flag", cookies[3].Name)
+	}
+
+	c.Request.Header.Del("Cookie")
+	assert.Empty(t, c.Cookies())
+}
+
 func TestContextBodyAllowedForStatus(t *testing.T) {
 	assert.False(t, false, bodyAllowedForStatus(http.StatusProcessing))
 	assert.False(t, false, bodyAllowedForStatus(http.StatusNoContent))
@@ -676,6 +899,77 @@ func TestContextRenderJSON(t *testing.T) {
 	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
 }
 
+func TestContextJSONBytes(t *testing.T) {
+	obj := struct {
+		ID   int64  `json:"id"`
+		HTML string `json:"html"`
+		Tags []int  `json:"tags"`
+	}{ID: 255, HTML: "<b>", Tags: []int{1, 2}}
+
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	body, err := c.JSONBytes(obj)
+	assert.NoError(t, err)
+	assert.False(t, c.Writer.Written())
+	assert.Empty(t, w.Header())
+
+	c.JSON(http.StatusOK, obj)
+	assert.Equal(t, w.Body.String(), string(body))
+
+	// the response wrapper applies too
+	w = httptest.NewRecorder()
+	c, router := CreateTestContext(w)
+	router.ResponseWrapper = func(c *Context, obj any) any {
+		return H{"data": obj}
+	}
+	body, err = c.JSONBytes(obj)
+	assert.NoError(t, err)
+	c.JSON(http.StatusOK, obj)
+	assert.Equal(t, w.Body.String(), string(body))
+	assert.Contains(t, string(body), `{"data":{"id":255`)
+
+	_, err = c.JSONBytes(make(chan int))
+	assert.Error(t, err)
+}
+
+// Tests that every int64 in the response is serialized as hex
+func TestContextRenderHexJSON(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+
+	c.HexJSON(http.StatusCreated, struct {
+		ID int64 `json:"id"`
+	}{255})
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "{\"id\":\"00000000000000ff\"}", w.Body.String())
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+}
+
+// Tests that the response is serialized with the given marshaler
+func TestContextRenderJSONWith(t *testing.T) {
+	obj := struct {
+		HTML string `json:"html"`
+	}{"<b>"}
+	escaped := jsoniter.Config{EscapeHTML: true}.Froze()
+	raw := jsoniter.Config{EscapeHTML: false}.Froze()
+
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	c.JSONWith(http.StatusCreated, escaped, obj)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "{\"html\":\"\\u003cb\\u003e\"}", w.Body.String())
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+	w = httptest.NewRecorder()
+	c, _ = CreateTestContext(w)
+	c.JSONWith(http.StatusOK, raw, obj)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "{\"html\":\"<b>\"}", w.Body.String())
+}
+
 // Tests that the response is serialized as JSONP
 // and Content-Type is set to application/javascript
 func TestContextRenderJSONP(t *testing.T) {
@@ -690,6 +984,32 @@ func TestContextRenderJSONP(t *testing.T) {
 	assert.Equal(t, "application/javascript; charset=utf-8", w.Header().Get("Content-Type"))
 }
 
+func TestContextRenderJSONPCallbackValidation(t *testing.T) {
+	for _, callback := range []string{"jQuery_123", "$cb", "_", "app.handlers.done"} {
+		w := httptest.NewRecorder()
+		c, _ := CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/?callback="+callback, nil)
+
+		c.JSONP(http.StatusOK, H{"id": 1})
+
+		assert.Equal(t, http.StatusOK, w.Code, callback)
+		assert.Equal(t, callback+`({"id":1});`, w.Body.String())
+	}
+
+	for _, callback := range []string{"<script>alert(1)</script>", "1cb", "a(b)", "cb;alert(1)", "a-b", ".cb"} {
+		w := httptest.NewRecorder()
+		c, _ := CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/?callback="+url.QueryEscape(callback), nil)
+
+		c.JSONP(http.StatusOK, H{"id": 1})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, callback)
+		assert.Empty(t, w.Body.String())
+		assert.True(t, c.IsAborted())
+		assert.ErrorIs(t, c.Errors.Last(), ErrInvalidJSONPCallback)
+	}
+}
+
 // Tests that the response is serialized as JSONP
 // and Content-Type is set to application/json
 func TestContextRenderJSONPWithoutCallback(t *testing.T) {
@@ -817,6 +1137,48 @@ func TestContextRenderPureJSON(t *testing.T) {
 	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
 }
 
+type envelope struct {
+	Data any   `json:"data"`
+	TS   int64 `json:"ts"`
+}
+
+func TestContextRenderResponseWrapper(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+	router := New()
+	router.ResponseWrapper = func(c *Context, obj any) any {
+		return envelope{Data: obj, TS: 1700000000}
+	}
+	router.GET("/json", func(c *Context) {
+		c.JSON(http.StatusOK, user{Name: "gin"})
+	})
+	router.GET("/pure", func(c *Context) {
+		c.PureJSON(http.StatusOK, user{Name: "<gin>"})
+	})
+	router.GET("/error", func(c *Context) {
+		c.AbortWithStatusJSON(http.StatusNotFound, H{"error": "not found"})
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/json")
+	assert.Equal(t, "{\"data\":{\"name\":\"gin\"},\"ts\":1700000000}", w.Body.String())
+	w = PerformRequest(router, http.MethodGet, "/pure")
+	assert.Equal(t, "{\"data\":{\"name\":\"<gin>\"},\"ts\":1700000000}\n", w.Body.String())
+	w = PerformRequest(router, http.MethodGet, "/error")
+	assert.Equal(t, "{\"data\":{\"error\":\"not found\"},\"ts\":1700000000}", w.Body.String())
+
+	router.ResponseWrapperSkipErrors = true
+	w = PerformRequest(router, http.MethodGet, "/error")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "{\"error\":\"not found\"}", w.Body.String())
+	w = PerformRequest(router, http.MethodGet, "/json")
+	assert.Equal(t, "{\"data\":{\"name\":\"gin\"},\"ts\":1700000000}", w.Body.String())
+
+	router.ResponseWrapper = nil
+	w = PerformRequest(router, http.MethodGet, "/json")
+	assert.Equal(t, "{\"name\":\"gin\"}", w.Body.String())
+}
+
 // Tests that the response executes the templates
 // and responds with Content-Type set to text/html
 func TestContextRenderHTML(t *testing.T) {
@@ -990,6 +1352,20 @@ func TestContextRenderSSE(t *testing.T) {
 	assert.Equal(t, strings.Replace(w.Body.String(), " ", "", -1), strings.Replace("event:float\ndata:1.5\n\nid:123\ndata:text\n\nevent:chat\ndata:{\"bar\":\"foo\",\"foo\":\"bar\"}\n\n", " ", "", -1))
 }
 
+func TestContextSSEventJSON(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	c.Header("Cache-Control", "no-store")
+
+	assert.NoError(t, c.SSEventJSON("chat", H{"foo": "bar"}))
+	assert.NoError(t, c.SSEventJSON("chat", "text"))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "event:chat\ndata:{\"foo\":\"bar\"}\n\nevent:chat\ndata:\"text\"\n\n", w.Body.String())
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+}
+
 func TestContextRenderFile(t *testing.T) {
 	w := httptest.NewRecorder()
 	c, _ := CreateTestContext(w)
@@ -1059,6 +1435,45 @@ func TestContextRenderUTF8Attachment(t *testing.T) {
 	assert.Equal(t, `attachment; filename*=UTF-8''`+url.QueryEscape(newFilename), w.Header().Get("Content-Disposition"))
 }
 
+func TestContextFileAttachmentFromReader(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+
+	c.FileAttachmentFromReader(strings.NewReader("a,b\n1,2\n"), 8, "report.csv", "text/csv")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "a,b\n1,2\n", w.Body.String())
+	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	assert.Equal(t, "8", w.Header().Get("Content-Length"))
+	assert.Equal(t, `attachment; filename="report.csv"`, w.Header().Get("Content-Disposition"))
+}
+
+func TestContextFileAttachmentFromReaderUnknownSize(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+
+	c.FileAttachmentFromReader(strings.NewReader("data"), -1, `a "q".bin`, "")
+
+	assert.Equal(t, "data", w.Body.String())
+	assert.Equal(t, "application/octet-stream", w.Header().Get("Content-Type"))
+	assert.Empty(t, w.Header().Values("Content-Length"))
+	assert.Equal(t, `attachment; filename="a \"q\".bin"`, w.Header().Get("Content-Disposition"))
+}
+
+func TestContextFileAttachmentFromReaderUTF8(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+
+	c.FileAttachmentFromReader(strings.NewReader("data"), 4, "报表 2023+1.csv", "text/csv")
+
+	assert.Equal(t,
+		`attachment; filename="__ 2023+1.csv"; filename*=UTF-8''%E6%8A%A5%E8%A1%A8%202023+1.csv`,
+		w.Header().Get("Content-Disposition"))
+}
+
 // TestContextRenderYAML tests that the response is serialized as YAML
 // and Content-Type is set to application/x-yaml
 func TestContextRenderYAML(t *testing.T) {
@@ -1085,30 +1500,6 @@ func TestContextRenderTOML(t *testing.T) {
 	assert.Equal(t, "application/toml; charset=utf-8", w.Header().Get("Content-Type"))
 }
 
-// TestContextRenderProtoBuf tests that the response is serialized as ProtoBuf
-// and Content-Type is set to application/x-protobuf
-// and we just use the example protobuf to check if the response is correct
-func TestContextRenderProtoBuf(t *testing.T) {
-	w := httptest.NewRecorder()
-	c, _ := CreateTestContext(w)
-
-	reps := []int64{int64(1), int64(2)}
-	label := "test"
-	data := &testdata.Test{
-		Label: &label,
-		Reps:  reps,
-	}
-
-	c.ProtoBuf(http.StatusCreated, data)
-
-	protoData, err := proto.Marshal(data)
-	assert.NoError(t, err)
-
-	assert.Equal(t, http.StatusCreated, w.Code)
-	assert.Equal(t, string(protoData), w.Body.String())
-	assert.Equal(t, "application/x-protobuf", w.Header().Get("Content-Type"))
-}
-
 func TestContextHeaders(t *testing.T) {
 	c, _ := CreateTestContext(httptest.NewRecorder())
 	c.Header("Content-Type", "text/plain")
@@ -1125,6 +1516,21 @@ func TestContextHeaders(t *testing.T) {
 	assert.False(t, exist)
 }
 
+func TestContextSetHeaderIfAbsent(t *testing.T) {
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.SetHeaderIfAbsent("Cache-Control", "no-store")
+	c.SetHeaderIfAbsent("Cache-Control", "max-age=60")
+	assert.Equal(t, "no-store", c.Writer.Header().Get("Cache-Control"))
+
+	c.Header("Cache-Control", "")
+	c.SetHeaderIfAbsent("Cache-Control", "max-age=60")
+	assert.Equal(t, "max-age=60", c.Writer.Header().Get("Cache-Control"))
+
+	c.SetHeaderIfAbsent("X-Custom", "")
+	_, exist := c.Writer.Header()["X-Custom"]
+	assert.False(t, exist)
+}
+
 // TODO
 func TestContextRenderRedirectWithRelativePath(t *testing.T) {
 	w := httptest.NewRecorder()
@@ -1331,6 +1737,51 @@ func TestContextNegotiationFormat2(t *testing.T) {
 	assert.Equal(t, "", c.NegotiateFormat("image/tiff"))
 }
 
+func TestContextNegotiationFormatQuality(t *testing.T) {
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+	c.Request.Header.Add("Accept", "application/json;q=0.8, text/html;q=0.9")
+
+	assert.Equal(t, MIMEHTML, c.NegotiateFormat(MIMEJSON, MIMEHTML))
+	assert.Equal(t, MIMEJSON, c.NegotiateFormat(MIMEJSON, MIMEXML))
+	assert.Empty(t, c.NegotiateFormat(MIMEXML))
+
+	c, _ = CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+	c.Request.Header.Add("Accept", "text/html;q=0.5, */*")
+
+	assert.Equal(t, MIMEJSON, c.NegotiateFormat(MIMEHTML, MIMEJSON))
+	assert.Equal(t, MIMEPlain, c.NegotiateFormat(MIMEHTML, MIMEPlain))
+	assert.Equal(t, MIMEHTML, c.NegotiateFormat(MIMEHTML))
+}
+
+func TestContextNegotiationFormatRejected(t *testing.T) {
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+	c.Request.Header.Add("Accept", "text/*, text/plain;q=0, application/json;q=0.1, */*;q=0")
+
+	assert.Equal(t, MIMEHTML, c.NegotiateFormat(MIMEPlain, MIMEHTML))
+	assert.Empty(t, c.NegotiateFormat(MIMEPlain))
+	assert.Equal(t, MIMEJSON, c.NegotiateFormat(MIMEXML, MIMEJSON))
+	assert.Empty(t, c.NegotiateFormat(MIMEXML))
+
+	c.SetAccepted(MIMEPlain)
+	assert.Equal(t, MIMEPlain, c.NegotiateFormat(MIMEJSON, MIMEPlain))
+
+	c, _ = CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+	c.Request.Header.Add("Accept", "application/json;q=0")
+
+	assert.Empty(t, c.NegotiateFormat(MIMEJSON))
+	assert.Empty(t, c.NegotiateFormat(MIMEHTML, MIMEJSON))
+
+	c, _ = CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+	c.Request.Header.Add("Accept", "*/*;q=0")
+
+	assert.Empty(t, c.NegotiateFormat(MIMEJSON, MIMEHTML))
+}
+
 func TestContextIsAborted(t *testing.T) {
 	c, _ := CreateTestContext(httptest.NewRecorder())
 	assert.False(t, c.IsAborted())
@@ -1391,6 +1842,51 @@ func TestContextAbortWithStatusJSON(t *testing.T) {
 	assert.Equal(t, "{\"foo\":\"fooValue\",\"bar\":\"barValue\"}", jsonStringBody)
 }
 
+func TestContextFail(t *testing.T) {
+	after := false
+	router := New()
+	router.Use(func(c *Context) {
+		c.Set(DefaultRequestIDKey, "req-1")
+	})
+	router.GET("/", func(c *Context) {
+		c.Fail(http.StatusConflict, "already exists")
+	}, func(c *Context) {
+		after = true
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/")
+
+	assert.False(t, after)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `{"code":409,"message":"already exists","request_id":"req-1"}`, w.Body.String())
+}
+
+func TestContextFailRequestIDKey(t *testing.T) {
+	router := New()
+	router.RequestIDKey = "rid"
+	router.GET("/", func(c *Context) {
+		c.Set("rid", "req-2")
+		c.Fail(http.StatusBadRequest, "bad")
+	})
+	router.GET("/header", func(c *Context) {
+		c.Fail(http.StatusBadRequest, "bad")
+	})
+	router.GET("/none", func(c *Context) {
+		c.Fail(http.StatusInternalServerError, "oops")
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/")
+	assert.Equal(t, `{"code":400,"message":"bad","request_id":"req-2"}`, w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/header", header{Key: "X-Request-Id", Value: "req-3"})
+	assert.Equal(t, `{"code":400,"message":"bad","request_id":"req-3"}`, w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/none")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, `{"code":500,"message":"oops"}`, w.Body.String())
+}
+
 func TestContextError(t *testing.T) {
 	c, _ := CreateTestContext(httptest.NewRecorder())
 	assert.Empty(t, c.Errors)
@@ -1451,6 +1947,54 @@ func TestContextAbortWithError(t *testing.T) {
 	assert.True(t, c.IsAborted())
 }
 
+func TestContextRemoteAddrPort(t *testing.T) {
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+
+	testCases := []struct {
+		remoteAddr string
+		want       string
+	}{
+		{"10.10.10.10:8080", "10.10.10.10:8080"},
+		{" 10.10.10.11:80 ", "10.10.10.11:80"},
+		{"[2001:db8::1]:443", "[2001:db8::1]:443"},
+		{"[fe80::1%eth0]:8080", "[fe80::1%eth0]:8080"},
+		{"[::ffff:192.168.1.7]:9000", "192.168.1.7:9000"},
+	}
+	for _, tc := range testCases {
+		c.Request.RemoteAddr = tc.remoteAddr
+		addrPort, err := c.RemoteAddrPort()
+		assert.NoError(t, err, tc.remoteAddr)
+		assert.Equal(t, tc.want, addrPort.String())
+	}
+
+	c.Request.RemoteAddr = "10.10.10.10:8080"
+	addrPort, _ := c.RemoteAddrPort()
+	assert.True(t, addrPort.Addr().Is4())
+	prefix, _ := addrPort.Addr().Prefix(24)
+	assert.Equal(t, "10.10.10.0/24", prefix.String())
+
+	for _, remoteAddr := range []string{"", "10.10.10.10", "2001:db8::1:443", "[::1]", "host:80"} {
+		c.Request.RemoteAddr = remoteAddr
+		_, err := c.RemoteAddrPort()
+		assert.Error(t, err, remoteAddr)
+	}
+}
+
+func TestContextRemoteAddrPortCached(t *testing.T) {
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+	c.Request.RemoteAddr = "[::1]:80"
+
+	first, _ := c.RemoteAddrPort()
+	assert.Equal(t, addrPortCache{parsed: true, raw: "[::1]:80", addrPort: first}, c.remoteAddrPort)
+	second, _ := c.RemoteAddrPort()
+	assert.Equal(t, first, second)
+
+	c.reset()
+	assert.Equal(t, addrPortCache{}, c.remoteAddrPort)
+}
+
 func TestContextClientIP(t *testing.T) {
 	c, _ := CreateTestContext(httptest.NewRecorder())
 	c.Request, _ = http.NewRequest("POST", "/", nil)
@@ -1576,11 +2120,38 @@ func TestContextClientIP(t *testing.T) {
 	assert.Empty(t, c.ClientIP())
 }
 
-func resetContextForClientIPTests(c *Context) {
-	c.Request.Header.Set("X-Real-IP", " 10.10.10.10  ")
-	c.Request.Header.Set("X-Forwarded-For", "  20.20.20.20, 30.30.30.30")
-	c.Request.Header.Set("X-Appengine-Remote-Addr", "50.50.50.50")
-	c.Request.Header.Set("CF-Connecting-IP", "60.60.60.60")
+func TestContextClientIPCustomHeaders(t *testing.T) {
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("POST", "/", nil)
+	resetContextForClientIPTests(c)
+	defer func() { c.engine.RemoteIPHeaders = []string{"X-Forwarded-For", "X-Real-IP"} }()
+	c.engine.RemoteIPHeaders = []string{PlatformCloudflare, "X-Forwarded-For", "X-Real-IP"}
+
+	// trusted peer: headers are tried in order
+	_ = c.engine.SetTrustedProxies([]string{"40.40.40.40"})
+	assert.Equal(t, "60.60.60.60", c.ClientIP())
+	c.Request.Header.Set("CF-Connecting-IP", "not an ip")
+	assert.Equal(t, "30.30.30.30", c.ClientIP())
+	c.Request.Header.Del("CF-Connecting-IP")
+	c.Request.Header.Del("X-Forwarded-For")
+	assert.Equal(t, "10.10.10.10", c.ClientIP())
+
+	// untrusted peer: spoofed headers are ignored
+	resetContextForClientIPTests(c)
+	_ = c.engine.SetTrustedProxies([]string{"30.30.30.30"})
+	assert.Equal(t, "40.40.40.40", c.ClientIP())
+	c.Request.Header.Set("CF-Connecting-IP", "1.2.3.4")
+	assert.Equal(t, "40.40.40.40", c.ClientIP())
+
+	_ = c.engine.SetTrustedProxies(nil)
+	assert.Equal(t, "40.40.40.40", c.ClientIP())
+}
+
+func resetContextForClientIPTests(c *Context) {
+	c.Request.Header.Set("X-Real-IP", " 10.10.10.10  ")
+	c.Request.Header.Set("X-Forwarded-For", "  20.20.20.20, 30.30.30.30")
+	c.Request.Header.Set("X-Appengine-Remote-Addr", "50.50.50.50")
+	c.Request.Header.Set("CF-Connecting-IP", "60.60.60.60")
 	c.Request.RemoteAddr = "  40.40.40.40:42123 "
 	c.engine.TrustedPlatform = ""
 	c.engine.trustedCIDRs = defaultTrustedCIDRs
@@ -1627,6 +2198,186 @@ func TestContextBindWithJSON(t *testing.T) {
 	assert.Equal(t, 0, w.Body.Len())
 }
 
+func TestContextMustBindJSONCustomHandler(t *testing.T) {
+	var obj struct {
+		Foo string `json:"foo" binding:"required"`
+	}
+	w := httptest.NewRecorder()
+	c, engine := CreateTestContext(w)
+	engine.BindErrorHandler = func(c *Context, err error) {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, H{"code": 1001})
+	}
+	c.Request, _ = http.NewRequest("POST", "/", bytes.NewBufferString(`{}`))
+
+	assert.False(t, c.MustBindJSON(&obj))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, `{"code":1001}`, w.Body.String())
+}
+
+func TestContextShouldBindNDJSON(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/", bytes.NewBufferString("{\"foo\":\"a\"}\n{\"foo\":\"b\"}\n"))
+	c.Request.Header.Add("Content-Type", MIMENDJSON)
+
+	var objs []struct {
+		Foo string `json:"foo"`
+	}
+	assert.NoError(t, c.ShouldBind(&objs))
+	assert.Len(t, objs, 2)
+	assert.Equal(t, "b", objs[1].Foo)
+	assert.Equal(t, 0, w.Body.Len())
+}
+
+func TestContextBindAndValidate(t *testing.T) {
+	type item struct {
+		ID string `json:"id" binding:"hexid"`
+	}
+	type request struct {
+		Name  string  `json:"name" binding:"required"`
+		Age   int     `json:"age,omitempty" binding:"gte=18"`
+		Owner string  `binding:"hexid"`
+		Items []*item `json:"items" binding:"dive"`
+	}
+	newContext := func(body string) *Context {
+		c, _ := CreateTestContext(httptest.NewRecorder())
+		c.Request, _ = http.NewRequest("POST", "/", bytes.NewBufferString(body))
+		return c
+	}
+
+	var req request
+	assert.Nil(t, newContext(`{"name":"gin","age":18,"Owner":"00000000000000ff","items":[{"id":"0000000000000001"}]}`).BindAndValidate(&req))
+	assert.Equal(t, "gin", req.Name)
+
+	problems := newContext(`{"age":3,"Owner":"ff","items":[{"id":"0000000000000001"},{"id":"x"}]}`).BindAndValidate(&request{})
+	assert.Equal(t, map[string]string{
+		"name":        "is required",
+		"age":         "failed on the 'gte=18' rule",
+		"Owner":       "failed on the 'hexid' rule",
+		"items[1].id": "failed on the 'hexid' rule",
+	}, problems)
+
+	// the elements of a slice body are keyed by their index
+	problems = newContext(`[{"id":"0000000000000001"},{"id":"x"}]`).BindAndValidate(&[]*item{})
+	assert.Equal(t, map[string]string{"[1].id": "failed on the 'hexid' rule"}, problems)
+
+	problems = newContext(`{"name":`).BindAndValidate(&request{})
+	assert.Len(t, problems, 1)
+	assert.Contains(t, problems, "body")
+}
+
+func TestContextShouldBindJSONValidationMessages(t *testing.T) {
+	type address struct {
+		City string `json:"city" binding:"required"`
+	}
+	type signup struct {
+		Email    string   `json:"email" binding:"required,email"`
+		Password string   `json:"password" binding:"min=8"`
+		Age      int      `json:"age" binding:"gte=18"`
+		Address  address  `json:"address"`
+		Hidden   string   `json:"-" binding:"required"`
+		Tags     []string `json:"tags,omitempty" binding:"max=1"`
+	}
+	newContext := func(body string) *Context {
+		c, _ := CreateTestContext(httptest.NewRecorder())
+		c.Request, _ = http.NewRequest("POST", "/", bytes.NewBufferString(body))
+		return c
+	}
+
+	err := newContext(`{"email":"nope","password":"short","age":3,"address":{"city":"x"},"tags":["a","b"]}`).ShouldBindJSON(&signup{})
+	var verrs validator.ValidationErrors
+	assert.ErrorAs(t, err, &verrs)
+	assert.Len(t, verrs, 5)
+	// the validator keeps reporting Go names
+	assert.Equal(t, "Email", verrs[0].Field())
+	assert.Equal(t, "signup.Email", verrs[0].Namespace())
+	assert.Contains(t, err.Error(), "Key: 'signup.Email' Error:Field validation for 'Email' failed on the 'email' tag")
+	assert.Equal(t, map[string]string{
+		"email":    "failed on the 'email' rule",
+		"password": "failed on the 'min=8' rule",
+		"age":      "failed on the 'gte=18' rule",
+		"Hidden":   "is required",
+		"tags":     "failed on the 'max=1' rule",
+	}, ValidationMessages(err))
+
+	// every invalid element of a slice body is reported at its index
+	type item struct {
+		ID   int    `json:"id" binding:"required"`
+		Name string `json:"name" binding:"required"`
+	}
+	err = newContext(`[{"name":"a"},{"id":2,"name":"b"},{}]`).ShouldBindJSON(&[]item{})
+	assert.Equal(t, map[string]string{
+		"[0].id":   "is required",
+		"[2].id":   "is required",
+		"[2].name": "is required",
+	}, ValidationMessages(err))
+
+	assert.Nil(t, ValidationMessages(newContext(`{"email":`).ShouldBindJSON(&signup{})))
+	assert.Nil(t, ValidationMessages(nil))
+}
+
+func TestContextBindJSONDisallowUnknownFields(t *testing.T) {
+	var obj struct {
+		Foo string `json:"foo"`
+	}
+	w := httptest.NewRecorder()
+	c, engine := CreateTestContext(w)
+	engine.DisallowUnknownFields = true
+
+	c.Request, _ = http.NewRequest("POST", "/", bytes.NewBufferString(`{"foo":"bar"}`))
+	assert.NoError(t, c.ShouldBindJSON(&obj))
+	assert.Equal(t, "bar", obj.Foo)
+
+	c.Request, _ = http.NewRequest("POST", "/", bytes.NewBufferString(`{"foo":"bar","extra":1}`))
+	err := c.ShouldBindJSON(&obj)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "extra")
+
+	c.Request, _ = http.NewRequest("POST", "/", bytes.NewBufferString(`{"foo":"bar","extra":1}`))
+	assert.Error(t, c.ShouldBindBodyWith(&obj, binding.JSON))
+
+	c.Request, _ = http.NewRequest("POST", "/", bytes.NewBufferString(`{"foo":"bar","extra":1}`))
+	c.Request.Header.Set("Content-Type", MIMEJSON)
+	assert.Error(t, c.ShouldBind(&obj))
+
+	engine.DisallowUnknownFields = false
+	c.Request, _ = http.NewRequest("POST", "/", bytes.NewBufferString(`{"foo":"baz","extra":1}`))
+	assert.NoError(t, c.ShouldBindJSON(&obj))
+	assert.Equal(t, "baz", obj.Foo)
+}
+
+func TestContextBindJSONValidateUTF8Body(t *testing.T) {
+	var obj struct {
+		Foo string `json:"foo"`
+	}
+	invalid := "{\"foo\":\"b\xffr\"}"
+	newContext := func(body string, validate bool) (*Context, *httptest.ResponseRecorder) {
+		w := httptest.NewRecorder()
+		c, engine := CreateTestContext(w)
+		engine.ValidateUTF8Body = validate
+		c.Request, _ = http.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		c.Request.Header.Set("Content-Type", MIMEJSON)
+		return c, w
+	}
+
+	c, _ := newContext(`{"foo":"bär"}`, true)
+	assert.NoError(t, c.ShouldBindJSON(&obj))
+	assert.Equal(t, "bär", obj.Foo)
+
+	c, _ = newContext(invalid, true)
+	assert.ErrorIs(t, c.ShouldBindJSON(&obj), ErrInvalidUTF8Body)
+
+	c, _ = newContext(invalid, true)
+	assert.ErrorIs(t, c.ShouldBindBodyWith(&obj, binding.JSON), ErrInvalidUTF8Body)
+
+	c, w := newContext(invalid, true)
+	assert.ErrorIs(t, c.Bind(&obj), ErrInvalidUTF8Body)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	c, _ = newContext(invalid, false)
+	assert.NoError(t, c.ShouldBindJSON(&obj))
+}
+
 func TestContextBindWithXML(t *testing.T) {
 	w := httptest.NewRecorder()
 	c, _ := CreateTestContext(w)
@@ -1773,6 +2524,61 @@ func TestContextShouldBindWithJSON(t *testing.T) {
 	assert.Equal(t, 0, w.Body.Len())
 }
 
+func TestContextShouldBindJSONStrict(t *testing.T) {
+	var obj struct {
+		Role string `json:"role"`
+	}
+
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"role":"user","role":"admin"}`))
+	var dup *binding.DuplicateKeyError
+	assert.ErrorAs(t, c.ShouldBindJSONStrict(&obj), &dup)
+	assert.Equal(t, "role", dup.Path)
+	assert.Empty(t, obj.Role)
+
+	c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"role":"user","role":"admin"}`))
+	assert.NoError(t, c.ShouldBindJSON(&obj))
+	assert.Equal(t, "admin", obj.Role)
+
+	c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"role":"user"}`))
+	assert.NoError(t, c.ShouldBindJSONStrict(&obj))
+	assert.Equal(t, "user", obj.Role)
+}
+
+func TestContextShouldBindWithFallback(t *testing.T) {
+	type event struct {
+		Type string `json:"type" form:"type" binding:"required"`
+		ID   int    `json:"id" form:"id"`
+	}
+
+	// a JSON body sent as a form
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"type":"push","id":7}`))
+	c.Request.Header.Set("Content-Type", MIMEPOSTForm)
+	var obj event
+	assert.NoError(t, c.ShouldBindWithFallback(&obj, binding.Form, binding.JSON))
+	assert.Equal(t, event{Type: "push", ID: 7}, obj)
+
+	// the first binding that works wins
+	c, _ = CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest(http.MethodPost, "/?id=3", bytes.NewBufferString("type=pull"))
+	c.Request.Header.Set("Content-Type", MIMEPOSTForm)
+	obj = event{}
+	assert.NoError(t, c.ShouldBindWithFallback(&obj, binding.JSON, binding.Form))
+	assert.Equal(t, event{Type: "pull", ID: 3}, obj)
+
+	// a failed attempt leaves nothing behind
+	c, _ = CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"id":5}`))
+	obj = event{}
+	err := c.ShouldBindWithFallback(&obj, binding.JSON, binding.Form)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "'Type' failed on the 'required' tag")
+	assert.Equal(t, event{}, obj)
+
+	assert.Equal(t, ErrNoBinding, c.ShouldBindWithFallback(&obj))
+}
+
 func TestContextShouldBindWithXML(t *testing.T) {
 	w := httptest.NewRecorder()
 	c, _ := CreateTestContext(w)
@@ -1816,6 +2622,34 @@ func TestContextShouldBindHeader(t *testing.T) {
 	assert.Equal(t, 0, w.Body.Len())
 }
 
+func TestContextShouldBindHeaderTyped(t *testing.T) {
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("GET", "/", nil)
+	c.Request.Header.Set("X-Tenant-Id", "00000000000003e8")
+	c.Request.Header.Set("X-Retries", "10")
+	c.Request.Header.Set("X-Debug", "true")
+	c.Request.Header.Set("If-Modified-Since", "Mon, 02 Jan 2006 15:04:05 GMT")
+
+	type headers struct {
+		TenantID int64     `header:"X-Tenant-Id,hexstring" binding:"required"`
+		Retries  int64     `header:"x-retries"`
+		Debug    bool      `header:"X-Debug"`
+		Since    time.Time `header:"If-Modified-Since" time_format:"Mon, 02 Jan 2006 15:04:05 GMT" time_utc:"1"`
+	}
+	var h headers
+	assert.NoError(t, c.ShouldBindHeader(&h))
+	assert.Equal(t, int64(1000), h.TenantID)
+	assert.Equal(t, int64(10), h.Retries)
+	assert.True(t, h.Debug)
+	assert.Equal(t, time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC), h.Since)
+
+	c.Request.Header.Del("X-Tenant-Id")
+	err := c.ShouldBindHeader(&headers{})
+	var missing *binding.MissingHeaderError
+	assert.ErrorAs(t, err, &missing)
+	assert.Equal(t, "missing required header X-Tenant-Id", err.Error())
+}
+
 func TestContextShouldBindWithQuery(t *testing.T) {
 	w := httptest.NewRecorder()
 	c, _ := CreateTestContext(w)
@@ -1836,6 +2670,103 @@ func TestContextShouldBindWithQuery(t *testing.T) {
 	assert.Equal(t, 0, w.Body.Len())
 }
 
+func TestContextShouldBindEmbeddedStruct(t *testing.T) {
+	type Pagination struct {
+		Page int `form:"page" json:"page"`
+		Size int `form:"size" json:"size"`
+	}
+	type listRequest struct {
+		Pagination
+		Sort string `form:"sort" json:"sort"`
+	}
+	type listPtrRequest struct {
+		*Pagination
+	}
+
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("GET", "/?page=2&size=50&sort=name", nil)
+	var query listRequest
+	assert.NoError(t, c.ShouldBindQuery(&query))
+	assert.Equal(t, listRequest{Pagination{Page: 2, Size: 50}, "name"}, query)
+
+	var queryPtr listPtrRequest
+	assert.NoError(t, c.ShouldBindQuery(&queryPtr))
+	if assert.NotNil(t, queryPtr.Pagination) {
+		assert.Equal(t, Pagination{Page: 2, Size: 50}, *queryPtr.Pagination)
+	}
+
+	c.Request, _ = http.NewRequest("POST", "/", strings.NewReader(`{"page":2,"size":50,"sort":"name"}`))
+	var body listRequest
+	assert.NoError(t, c.ShouldBindJSON(&body))
+	assert.Equal(t, listRequest{Pagination{Page: 2, Size: 50}, "name"}, body)
+}
+
+func TestContextShouldBindForm(t *testing.T) {
+	type params struct {
+		Page  string   `form:"page"`
+		Sort  string   `form:"sort"`
+		Order string   `form:"order"`
+		Tags  []string `form:"tags"`
+	}
+	multipartBody := func() (*bytes.Buffer, string) {
+		buf := new(bytes.Buffer)
+		mw := multipart.NewWriter(buf)
+		assert.NoError(t, mw.WriteField("sort", "name"))
+		assert.NoError(t, mw.WriteField("order", "desc"))
+		mw.Close()
+		return buf, mw.FormDataContentType()
+	}
+
+	for _, queryFirst := range []bool{false, true} {
+		winner := "name"
+		if queryFirst {
+			winner = "date"
+		}
+
+		c, _ := CreateTestContext(httptest.NewRecorder())
+		c.engine.FormQueryFirst = queryFirst
+		c.Request, _ = http.NewRequest(http.MethodPost, "/?page=2&sort=date&tags=q", strings.NewReader("sort=name&order=desc&tags=b"))
+		c.Request.Header.Set("Content-Type", MIMEPOSTForm)
+		var obj params
+		assert.NoError(t, c.ShouldBindForm(&obj))
+		assert.Equal(t, "2", obj.Page)
+		assert.Equal(t, "desc", obj.Order)
+		assert.Equal(t, winner, obj.Sort)
+		if queryFirst {
+			assert.Equal(t, []string{"q", "b"}, obj.Tags)
+		} else {
+			assert.Equal(t, []string{"b", "q"}, obj.Tags)
+		}
+
+		body, contentType := multipartBody()
+		c, _ = CreateTestContext(httptest.NewRecorder())
+		c.engine.FormQueryFirst = queryFirst
+		c.Request, _ = http.NewRequest(http.MethodPost, "/?page=3&sort=date", body)
+		c.Request.Header.Set("Content-Type", contentType)
+		obj = params{}
+		assert.NoError(t, c.BindForm(&obj))
+		assert.Equal(t, "3", obj.Page)
+		assert.Equal(t, "desc", obj.Order)
+		assert.Equal(t, winner, obj.Sort)
+	}
+}
+
+func TestContextShouldBindHex(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+
+	c.Request, _ = http.NewRequest("GET", "/?id=000000000000007b&ids=ff&ids=0x10", nil)
+
+	var obj struct {
+		ID  int64   `form:"id,hexstring"`
+		IDs []int64 `form:"ids,hexstring"`
+	}
+	assert.NoError(t, c.ShouldBindHex(&obj))
+	assert.Equal(t, int64(0x7b), obj.ID)
+	assert.Equal(t, []int64{0xff, 0x10}, obj.IDs)
+	assert.Equal(t, 0, w.Body.Len())
+}
+
 func TestContextShouldBindWithYAML(t *testing.T) {
 	w := httptest.NewRecorder()
 	c, _ := CreateTestContext(w)
@@ -1889,6 +2820,32 @@ func TestContextBadAutoShouldBind(t *testing.T) {
 	assert.False(t, c.IsAborted())
 }
 
+func TestContextShouldBindBodyWithJSON(t *testing.T) {
+	type envelope struct {
+		Kind string `json:"kind" binding:"required"`
+	}
+	type user struct {
+		Kind string `json:"kind"`
+		ID   int64  `json:"id"`
+	}
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/", bytes.NewBufferString(`{"kind":"user","id":255}`))
+
+	var peek envelope
+	assert.NoError(t, c.ShouldBindBodyWithJSON(&peek))
+	assert.Equal(t, "user", peek.Kind)
+
+	var u user
+	assert.NoError(t, c.ShouldBindBodyWithJSON(&u))
+	assert.Equal(t, user{Kind: "user", ID: 255}, u)
+	assert.Equal(t, []byte(`{"kind":"user","id":255}`), c.MustGet(BodyBytesKey))
+
+	c.Request, _ = http.NewRequest("POST", "/", nil)
+	c.Keys = nil
+	assert.Error(t, c.ShouldBindBodyWithJSON(&peek))
+}
+
 func TestContextShouldBindBodyWith(t *testing.T) {
 	type typeA struct {
 		Foo string `json:"foo" xml:"foo" binding:"required"`
@@ -2027,6 +2984,42 @@ func TestContextGetRawData(t *testing.T) {
 	assert.Equal(t, "Fetch binary post data", string(data))
 }
 
+func TestContextGetRawDataTwice(t *testing.T) {
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"foo":"bar"}`))
+	c.Request.Header.Add("Content-Type", MIMEJSON)
+
+	first, err := c.GetRawData()
+	assert.NoError(t, err)
+	second, err := c.GetRawData()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"foo":"bar"}`, string(first))
+	assert.Equal(t, first, second)
+
+	var obj struct {
+		Foo string `json:"foo"`
+	}
+	assert.NoError(t, c.ShouldBindJSON(&obj))
+	assert.Equal(t, "bar", obj.Foo)
+	assert.NoError(t, c.ShouldBindBodyWith(&obj, binding.JSON))
+}
+
+func TestContextGetRawDataMaxBodyBytes(t *testing.T) {
+	var rawErr error
+	router := New()
+	router.POST("/", MaxBodyBytes(4), func(c *Context) {
+		_, rawErr = c.GetRawData()
+		_, ok := c.Get(BodyBytesKey)
+		assert.False(t, ok)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", io.NopCloser(strings.NewReader("too large")))
+	req.ContentLength = -1
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.True(t, isMaxBytesError(rawErr))
+}
+
 func TestContextRenderDataFromReader(t *testing.T) {
 	w := httptest.NewRecorder()
 	c, _ := CreateTestContext(w)
@@ -2120,6 +3113,158 @@ func TestContextStreamWithClientGone(t *testing.T) {
 	assert.Equal(t, "test", w.Body.String())
 }
 
+// failingWriter accepts limit bytes, then fails; it cannot flush.
+type failingWriter struct {
+	header http.Header
+	body   bytes.Buffer
+	limit  int
+}
+
+func (w *failingWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *failingWriter) WriteHeader(int) {}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.body.Len()+len(p) > w.limit {
+		return 0, errors.New("connection reset")
+	}
+	return w.body.Write(p)
+}
+
+func TestContextStreamCounted(t *testing.T) {
+	w := CreateTestResponseRecorder()
+	c, _ := CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+
+	steps := 0
+	n, err := c.StreamCounted(func(w io.Writer) (bool, error) {
+		steps++
+		_, err := io.WriteString(w, "data: tick\n\n")
+		return steps < 3, err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(36), n)
+	assert.Equal(t, 3, steps)
+	assert.True(t, w.Flushed)
+	assert.Equal(t, strings.Repeat("data: tick\n\n", 3), w.Body.String())
+
+	stepErr := errors.New("source closed")
+	c, _ = CreateTestContext(CreateTestResponseRecorder())
+	n, err = c.StreamCounted(func(w io.Writer) (bool, error) {
+		_, _ = io.WriteString(w, "partial")
+		return true, stepErr
+	})
+	assert.ErrorIs(t, err, stepErr)
+	assert.Equal(t, int64(7), n)
+}
+
+func TestContextStreamCountedWriteError(t *testing.T) {
+	fw := &failingWriter{header: http.Header{}, limit: 10}
+	c, _ := CreateTestContext(fw)
+
+	steps := 0
+	n, err := c.StreamCounted(func(w io.Writer) (bool, error) {
+		steps++
+		// the error of the second write is ignored by the step
+		w.Write([]byte("12345")) //nolint: errcheck
+		w.Write([]byte("678"))   //nolint: errcheck
+		return true, nil
+	})
+	assert.EqualError(t, err, "connection reset")
+	assert.Equal(t, 2, steps)
+	assert.Equal(t, int64(8), n)
+	assert.Equal(t, "12345678", fw.body.String())
+}
+
+func TestContextStreamCountedClientGone(t *testing.T) {
+	c, _ := CreateTestContext(CreateTestResponseRecorder())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c.Request, _ = http.NewRequestWithContext(ctx, http.MethodGet, "/", nil)
+
+	n, err := c.StreamCounted(func(w io.Writer) (bool, error) {
+		t.Error("step called after the client went away")
+		return false, nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, n)
+}
+
+func TestContextStreamJSON(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+
+	ch := make(chan any)
+	go func() {
+		defer close(ch)
+		for i := 0; i < 10000; i++ {
+			ch <- H{"i": i}
+		}
+	}()
+	c.StreamJSON(http.StatusOK, ch)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.True(t, w.Flushed)
+	var items []struct{ I int }
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &items))
+	assert.Len(t, items, 10000)
+	for i, item := range items {
+		assert.Equal(t, i, item.I)
+	}
+}
+
+func TestContextStreamJSONEmpty(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+
+	ch := make(chan any)
+	close(ch)
+	c.StreamJSON(http.StatusCreated, ch)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "[]", w.Body.String())
+}
+
+func TestContextStreamJSONError(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+
+	ch := make(chan any, 3)
+	ch <- 1
+	ch <- make(chan int)
+	ch <- 3
+	close(ch)
+	c.StreamJSON(http.StatusOK, ch)
+
+	assert.True(t, c.IsAborted())
+	assert.Len(t, c.Errors, 1)
+	assert.Equal(t, "[1", w.Body.String())
+}
+
+func TestContextStreamJSONClientGone(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	ctx, cancel := context.WithCancel(context.Background())
+	c.Request, _ = http.NewRequestWithContext(ctx, http.MethodGet, "/", nil)
+
+	ch := make(chan any)
+	go func() {
+		ch <- 1
+		cancel()
+	}()
+	c.StreamJSON(http.StatusOK, ch)
+
+	assert.True(t, c.IsAborted())
+	assert.ErrorIs(t, c.Errors.Last(), context.Canceled)
+	assert.Equal(t, "[1", w.Body.String())
+}
+
 func TestContextResetInHandler(t *testing.T) {
 	w := CreateTestResponseRecorder()
 	c, _ := CreateTestContext(w)
@@ -2217,6 +3362,64 @@ func TestContextWithFallbackDeadlineFromRequestContext(t *testing.T) {
 	assert.True(t, ok)
 }
 
+func TestContextCancelledWithRequest(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	result := make(chan error, 1)
+	router := New()
+	router.GET("/", func(c *Context) {
+		close(started)
+		// *Context is passed where a context.Context is expected
+		result <- waitDone(c)
+	})
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/", nil)
+	go func() {
+		<-started
+		cancel()
+	}()
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.ErrorIs(t, <-result, context.Canceled)
+}
+
+func TestContextCopyNotCancelledWithRequest(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), "key", "value")) //nolint:staticcheck
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequestWithContext(ctx, http.MethodGet, "/", nil)
+	cp := c.Copy()
+	cancel()
+
+	assert.ErrorIs(t, c.Err(), context.Canceled)
+	assert.NoError(t, cp.Err())
+	assert.Nil(t, cp.Done())
+	assert.Equal(t, "value", cp.Value("key"))
+}
+
+func waitDone(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(time.Second):
+		return nil
+	}
+}
+
+func TestContextWithoutFallback(t *testing.T) {
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	assert.True(t, c.engine.ContextWithFallback)
+	c.engine.ContextWithFallback = false
+
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), "key", "value"), time.Second) //nolint:staticcheck
+	defer cancel()
+	c.Request, _ = http.NewRequestWithContext(ctx, http.MethodGet, "/", nil)
+
+	assert.Nil(t, c.Done())
+	assert.Nil(t, c.Value("key"))
+	_, ok := c.Deadline()
+	assert.False(t, ok)
+}
+
 func TestContextWithFallbackDoneFromRequestContext(t *testing.T) {
 	c, _ := CreateTestContext(httptest.NewRecorder())
 	// enable ContextWithFallback feature flag
@@ -2393,6 +3596,54 @@ func TestContextAddParam(t *testing.T) {
 	assert.Equal(t, value, v)
 }
 
+func TestContextTypedParams(t *testing.T) {
+	c := &Context{}
+	c.AddParam("id", "42")
+	c.AddParam("hex", "ff")
+	c.AddParam("flag", "true")
+	c.AddParam("uuid", "123e4567-e89b-12d3-a456-426614174000")
+	c.AddParam("bad", "x1")
+
+	i, err := c.ParamInt("id")
+	assert.NoError(t, err)
+	assert.Equal(t, 42, i)
+	i64, err := c.ParamInt64("id")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), i64)
+	i64, err = c.ParamHex64("hex")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(255), i64)
+	b, err := c.ParamBool("flag")
+	assert.NoError(t, err)
+	assert.True(t, b)
+	id, err := c.ParamUUID("uuid")
+	assert.NoError(t, err)
+	assert.Equal(t, [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}, id)
+
+	_, err = c.ParamInt64("missing")
+	assert.ErrorIs(t, err, ErrParamMissing)
+	assert.EqualError(t, err, `path parameter "missing" is missing`)
+
+	_, err = c.ParamInt("bad")
+	assert.EqualError(t, err, `path parameter "bad" = "x1": invalid syntax`)
+	var paramErr *ParamError
+	assert.ErrorAs(t, err, &paramErr)
+	assert.Equal(t, "bad", paramErr.Key)
+	assert.Equal(t, "x1", paramErr.Value)
+
+	_, err = c.ParamInt64("uuid")
+	assert.ErrorAs(t, err, &paramErr)
+	_, err = c.ParamHex64("bad")
+	assert.ErrorAs(t, err, &paramErr)
+	_, err = c.ParamBool("bad")
+	assert.ErrorAs(t, err, &paramErr)
+	_, err = c.ParamUUID("id")
+	assert.EqualError(t, err, `path parameter "id" = "42": invalid UUID`)
+	c.AddParam("uuid2", "123e4567-e89b-12d3-a456-42661417400g")
+	_, err = c.ParamUUID("uuid2")
+	assert.ErrorAs(t, err, &paramErr)
+}
+
 func TestCreateTestContextWithRouteParams(t *testing.T) {
 	w := httptest.NewRecorder()
 	engine := New()
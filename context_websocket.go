@@ -0,0 +1,24 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+//go:build websocket
+
+package gin
+
+import (
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+// Upgrade upgrades the request to the WebSocket protocol with upgrader, sending the headers
+// already set on c.Writer along with the handshake response. The handler chain is aborted
+// either way: on success the connection belongs to the returned *websocket.Conn and gin does
+// not write a response, on failure upgrader has already answered with an HTTP error.
+// It is only available when building with the websocket tag.
+func (c *Context) Upgrade(upgrader *websocket.Upgrader) (*websocket.Conn, error) {
+	c.Abort()
+	c.Writer.WriteHeader(http.StatusSwitchingProtocols)
+	return upgrader.Upgrade(c.Writer, c.Request, c.Writer.Header())
+}
@@ -0,0 +1,76 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+//go:build websocket
+
+package gin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextUpgradeEcho(t *testing.T) {
+	status := make(chan int, 1)
+	router := New()
+	router.Use(func(c *Context) {
+		c.Header("X-Request-Id", "42")
+		c.Next()
+		status <- c.Writer.Status()
+	})
+	router.GET("/ws", func(c *Context) {
+		conn, err := c.Upgrade(&websocket.Upgrader{})
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+		for {
+			mt, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err = conn.WriteMessage(mt, msg); err != nil {
+				return
+			}
+		}
+	}, func(c *Context) {
+		t.Error("handler after Upgrade must not run")
+	})
+
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	conn, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/ws", nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+	assert.Equal(t, "42", resp.Header.Get("X-Request-Id"))
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("hello gin")))
+	mt, msg, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, websocket.TextMessage, mt)
+	assert.Equal(t, "hello gin", string(msg))
+	conn.Close()
+
+	assert.Equal(t, http.StatusSwitchingProtocols, <-status)
+}
+
+func TestContextUpgradeFailed(t *testing.T) {
+	router := New()
+	router.GET("/ws", func(c *Context) {
+		_, err := c.Upgrade(&websocket.Upgrader{})
+		assert.Error(t, err)
+	}, func(c *Context) {
+		t.Error("handler after Upgrade must not run")
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/ws")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -5,7 +5,9 @@
 package gin
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"reflect"
 	"strings"
 
@@ -173,3 +175,64 @@ func (a errorMsgs) String() string {
 	}
 	return buffer.String()
 }
+
+// APIError is an error answered with its HTTP status and a JSON body by ErrorRenderer, e.g.
+//
+//	c.Error(gin.APIError{Status: http.StatusNotFound, Code: "user_not_found", Message: "no such user"})
+//	return
+type APIError struct {
+	Status  int    `json:"-"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Meta    any    `json:"meta,omitempty"`
+}
+
+var _ error = APIError{}
+
+// Error implements the error interface.
+func (e APIError) Error() string {
+	if e.Message == "" {
+		return e.Code
+	}
+	if e.Code == "" {
+		return e.Message
+	}
+	return e.Code + ": " + e.Message
+}
+
+// asAPIError returns the APIError err is or wraps, as a value or a pointer.
+func asAPIError(err error) (*APIError, bool) {
+	var ptr *APIError
+	if errors.As(err, &ptr) && ptr != nil {
+		return ptr, true
+	}
+	var val APIError
+	if errors.As(err, &val) {
+		return &val, true
+	}
+	return nil, false
+}
+
+// ErrorRenderer returns a middleware that renders the last error of Context.Errors when it
+// is an APIError, or wraps one, after the rest of the chain ran. The response has the status
+// of the error, 500 if it is not set, and the error as JSON body:
+// {"code": "...", "message": "...", "meta": ...}. Responses already written and other errors
+// are left as they are.
+func ErrorRenderer() HandlerFunc {
+	return func(c *Context) {
+		c.Next()
+		last := c.Errors.Last()
+		if last == nil || c.Writer.Written() {
+			return
+		}
+		apiErr, ok := asAPIError(last.Err)
+		if !ok {
+			return
+		}
+		status := apiErr.Status
+		if status < 100 {
+			status = http.StatusInternalServerError
+		}
+		c.AbortWithStatusJSON(status, apiErr)
+	}
+}
@@ -7,6 +7,7 @@ package gin
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/gin-gonic/gin/internal/json"
@@ -126,3 +127,58 @@ func TestErrorUnwrap(t *testing.T) {
 	var testErr TestErr
 	assert.True(t, errors.As(err, &testErr))
 }
+
+func TestErrorRenderer(t *testing.T) {
+	router := New()
+	router.Use(ErrorRenderer())
+	router.GET("/value", func(c *Context) {
+		c.Error(APIError{Status: http.StatusNotFound, Code: "user_not_found", Message: "no such user"}) //nolint: errcheck
+	})
+	router.GET("/pointer", func(c *Context) {
+		err := fmt.Errorf("load: %w", &APIError{
+			Status:  http.StatusConflict,
+			Code:    "version_conflict",
+			Message: "stale version",
+			Meta:    H{"version": 3},
+		})
+		c.Error(errors.New("first")) //nolint: errcheck
+		c.Error(err)                 //nolint: errcheck
+	})
+	router.GET("/nostatus", func(c *Context) {
+		c.Error(APIError{Code: "internal"}) //nolint: errcheck
+	})
+	router.GET("/written", func(c *Context) {
+		c.String(http.StatusOK, "ok")
+		c.Error(APIError{Status: http.StatusBadRequest, Code: "late"}) //nolint: errcheck
+	})
+	router.GET("/other", func(c *Context) {
+		c.Error(errors.New("not an API error")) //nolint: errcheck
+		c.Status(http.StatusAccepted)
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/value")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, `{"code":"user_not_found","message":"no such user"}`, w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/pointer")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, `{"code":"version_conflict","message":"stale version","meta":{"version":3}}`, w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/nostatus")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, `{"code":"internal","message":""}`, w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/written")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ok", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/other")
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestAPIErrorMessage(t *testing.T) {
+	assert.Equal(t, "not_found: no such user", APIError{Code: "not_found", Message: "no such user"}.Error())
+	assert.Equal(t, "not_found", APIError{Code: "not_found"}.Error())
+	assert.Equal(t, "no such user", (&APIError{Message: "no such user"}).Error())
+}
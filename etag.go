@@ -0,0 +1,106 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"net/http"
+	"strings"
+)
+
+// ETagOption configures the ETag middleware.
+type ETagOption func(*etagConfig)
+
+type etagConfig struct {
+	hash func() hash.Hash
+	weak bool
+}
+
+// ETagHash sets the hash the tag is computed with, sha256.New by default.
+func ETagHash(h func() hash.Hash) ETagOption {
+	return func(conf *etagConfig) {
+		conf.hash = h
+	}
+}
+
+// ETagWeak makes the generated tags weak, W/"...", for responses that are equivalent but
+// not byte for byte identical, e.g. compressed afterwards.
+func ETagWeak() ETagOption {
+	return func(conf *etagConfig) {
+		conf.weak = true
+	}
+}
+
+// ETag returns a middleware that tags the 2xx responses to GET and HEAD requests with an
+// ETag header, the hex encoded hash of the body, unless the handler set one. The response
+// is buffered to hash it, see BufferResponse. When the If-None-Match header of the request
+// matches the tag, the response is replaced by 304 Not Modified without body.
+// Streamed responses and other methods are passed through.
+func ETag(opts ...ETagOption) HandlerFunc {
+	conf := etagConfig{hash: sha256.New}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	return func(c *Context) {
+		method := c.Request.Method
+		w := c.Writer
+		if (method != http.MethodGet && method != http.MethodHead) || w.Written() {
+			c.Next()
+			return
+		}
+		bw := &bufferWriter{ResponseWriter: w, status: w.Status(), size: noWritten}
+		c.Writer = bw
+		defer func() {
+			c.Writer = w
+		}()
+		c.Next()
+		if bw.streaming {
+			return
+		}
+		if bw.status < 200 || bw.status >= 300 {
+			bw.writeTo(w)
+			return
+		}
+
+		header := w.Header()
+		tag := header.Get("ETag")
+		if tag == "" {
+			h := conf.hash()
+			h.Write(bw.buf.Bytes()) //nolint: errcheck
+			tag = `"` + hex.EncodeToString(h.Sum(nil)) + `"`
+			if conf.weak {
+				tag = "W/" + tag
+			}
+			header.Set("ETag", tag)
+		}
+		if !etagMatch(c.requestHeader("If-None-Match"), tag) {
+			bw.writeTo(w)
+			return
+		}
+		header.Del("Content-Type")
+		header.Del("Content-Length")
+		w.WriteHeader(http.StatusNotModified)
+		w.WriteHeaderNow()
+	}
+}
+
+// etagMatch reports whether the If-None-Match header value matches tag, comparing
+// the tags weakly as RFC 9110 requires for If-None-Match.
+func etagMatch(ifNoneMatch, tag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	tag = strings.TrimPrefix(tag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"crypto/md5"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestETagNotModified(t *testing.T) {
+	router := New()
+	router.Use(ETag())
+	users := func(c *Context) {
+		c.JSON(http.StatusOK, H{"name": "gin"})
+	}
+	router.GET("/users", users)
+	router.HEAD("/users", users)
+
+	w := PerformRequest(router, http.MethodGet, "/users")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"name":"gin"}`, w.Body.String())
+	tag := w.Header().Get("ETag")
+	assert.Equal(t, `"b6582ce9483030e8349851ece77931d99035ef1e6510985d4e09e89837a8967c"`, tag)
+
+	w = PerformRequest(router, http.MethodGet, "/users", header{Key: "If-None-Match", Value: tag})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, tag, w.Header().Get("ETag"))
+	assert.Empty(t, w.Header().Get("Content-Type"))
+
+	for _, ifNoneMatch := range []string{`"other", ` + tag, "W/" + tag, "*"} {
+		w = PerformRequest(router, http.MethodHead, "/users", header{Key: "If-None-Match", Value: ifNoneMatch})
+		assert.Equal(t, http.StatusNotModified, w.Code, ifNoneMatch)
+	}
+
+	w = PerformRequest(router, http.MethodGet, "/users", header{Key: "If-None-Match", Value: `"other"`})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"name":"gin"}`, w.Body.String())
+}
+
+func TestETagSkipped(t *testing.T) {
+	router := New()
+	router.Use(ETag())
+	router.POST("/users", func(c *Context) {
+		c.JSON(http.StatusOK, H{"name": "gin"})
+	})
+	router.GET("/missing", func(c *Context) {
+		c.String(http.StatusNotFound, "missing")
+	})
+	router.GET("/tagged", func(c *Context) {
+		c.Header("ETag", `"v1"`)
+		c.String(http.StatusOK, "tagged")
+	})
+	anyTag := header{Key: "If-None-Match", Value: "*"}
+
+	w := PerformRequest(router, http.MethodPost, "/users", anyTag)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("ETag"))
+
+	w = PerformRequest(router, http.MethodGet, "/missing", anyTag)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "missing", w.Body.String())
+	assert.Empty(t, w.Header().Get("ETag"))
+
+	// a tag set by the handler is kept and compared
+	w = PerformRequest(router, http.MethodGet, "/tagged", header{Key: "If-None-Match", Value: `"v1"`})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, `"v1"`, w.Header().Get("ETag"))
+}
+
+func TestETagOptions(t *testing.T) {
+	router := New()
+	router.Use(ETag(ETagHash(md5.New), ETagWeak()))
+	router.GET("/users", func(c *Context) {
+		c.JSON(http.StatusOK, H{"name": "gin"})
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/users")
+	tag := w.Header().Get("ETag")
+	assert.Regexp(t, `^W/"[0-9a-f]{32}"$`, tag)
+
+	w = PerformRequest(router, http.MethodGet, "/users", header{Key: "If-None-Match", Value: tag})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+}
@@ -5,15 +5,20 @@
 package gin
 
 import (
+	"context"
 	"fmt"
 	"html/template"
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"path"
 	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin/internal/bytesconv"
 	"github.com/gin-gonic/gin/render"
@@ -47,6 +52,16 @@ var regRemoveRepeatedChar = regexp.MustCompile("/{2,}")
 // HandlerFunc defines the handler used by gin middleware as return value.
 type HandlerFunc func(*Context)
 
+// BindErrorHandlerFunc defines the function handling a binding error of Context.MustBindJSON.
+type BindErrorHandlerFunc func(c *Context, err error)
+
+// ResponseWrapperFunc defines the function of Engine.ResponseWrapper, it returns the object
+// to render in place of obj.
+type ResponseWrapperFunc func(c *Context, obj any) any
+
+// RouteRegisterFunc defines the hook called by Engine.OnRouteRegister for each new route.
+type RouteRegisterFunc func(method, path string, handlers *[]HandlerFunc)
+
 // HandlersChain defines a HandlerFunc slice.
 type HandlersChain []HandlerFunc
 
@@ -64,11 +79,32 @@ type RouteInfo struct {
 	Path        string
 	Handler     string
 	HandlerFunc HandlerFunc
+	// Handlers are the fully-qualified names of the whole handler chain, middleware first;
+	// the last one is Handler.
+	Handlers []string
+	// Host is the pattern of the Engine.Host group the route belongs to, empty for any host.
+	Host string
 }
 
 // RoutesInfo defines a RouteInfo slice.
 type RoutesInfo []RouteInfo
 
+// RouteNodeInfo is a snapshot of one node of a method's route tree.
+type RouteNodeInfo struct {
+	// Path is the path segment stored in the node.
+	Path string `json:"path"`
+	// FullPath is the pattern from the root up to and including the node.
+	FullPath string `json:"fullPath"`
+	// Params are the names of the parameters in FullPath, without ':' or '*'.
+	Params []string `json:"params,omitempty"`
+	// Priority is the number of handlers registered in the node's subtree.
+	Priority uint32 `json:"priority"`
+	// Handlers is the length of the handler chain, 0 when no route ends at the node.
+	Handlers int `json:"handlers"`
+	// HandlerNames are the names of the functions of the handler chain, middleware first.
+	HandlerNames []string `json:"handlerNames,omitempty"`
+}
+
 // Trusted platforms
 const (
 	// PlatformGoogleAppEngine when running on Google App Engine. Trust X-Appengine-Remote-Addr
@@ -88,9 +124,15 @@ type Engine struct {
 	// handler for the path with (without) the trailing slash exists.
 	// For example if /foo/ is requested but a route only exists for /foo, the
 	// client is redirected to /foo with http status code 301 for GET requests
-	// and 307 for all other request methods.
+	// and 307 for all other request methods, see TrailingSlashRedirectStatus.
 	RedirectTrailingSlash bool
 
+	// TrailingSlashRedirectStatus is the status code of the redirections of RedirectTrailingSlash
+	// for request methods other than GET, e.g. http.StatusPermanentRedirect (308) to keep the
+	// method and the body with a permanent redirection. It is 307 when zero. GET requests are
+	// always redirected with 301.
+	TrailingSlashRedirectStatus int
+
 	// RedirectFixedPath if enabled, the router tries to fix the current request path, if no
 	// handle is registered for it.
 	// First superfluous path elements like ../ or // are removed.
@@ -110,6 +152,12 @@ type Engine struct {
 	// handler.
 	HandleMethodNotAllowed bool
 
+	// HandleOptionsAutomatically if enabled, an OPTIONS request for a path that has routes
+	// but no OPTIONS handler is answered with 204 No Content and an 'Allow' header listing
+	// the registered methods. The global middleware still runs, e.g. to add CORS headers.
+	// An explicitly registered OPTIONS route always takes precedence.
+	HandleOptionsAutomatically bool
+
 	// ForwardedByClientIP if enabled, client IP will be parsed from the request's headers that
 	// match those stored at `(*gin.Engine).RemoteIPHeaders`. If no IP was
 	// fetched, it falls back to the IP obtained from
@@ -138,6 +186,13 @@ type Engine struct {
 	// `(*gin.Engine).ForwardedByClientIP` is `true` and
 	// `(*gin.Context).Request.RemoteAddr` is matched by at least one of the
 	// network origins of list defined by `(*gin.Engine).SetTrustedProxies()`.
+	// The headers are tried in order and the first valid IP wins, so other proxy
+	// headers can be added, e.g. to prefer Cloudflare's header:
+	//
+	//	router.RemoteIPHeaders = []string{gin.PlatformCloudflare, "X-Forwarded-For", "X-Real-IP"}
+	//
+	// Unlike `(*gin.Engine).TrustedPlatform`, these headers are ignored when the
+	// direct peer is not a trusted proxy.
 	RemoteIPHeaders []string
 
 	// TrustedPlatform if set to a constant of value gin.Platform*, trusts the headers set by
@@ -152,8 +207,45 @@ type Engine struct {
 	UseH2C bool
 
 	// ContextWithFallback enable fallback Context.Deadline(), Context.Done(), Context.Err() and Context.Value() when Context.Request.Context() is not nil.
+	// It is enabled by New, so that a *Context passed as a context.Context is cancelled with the request.
 	ContextWithFallback bool
 
+	// DisallowUnknownFields if enabled, JSON binding through the Context rejects request
+	// bodies with object keys that do not match a field of the destination, using
+	// binding.StrictJSON instead of binding.JSON. Other JSON decoding is unaffected.
+	DisallowUnknownFields bool
+
+	// FormQueryFirst if enabled, form binding through the Context gives the query string
+	// precedence over the request body when a parameter is in both, using
+	// binding.FormQueryFirst instead of binding.Form.
+	FormQueryFirst bool
+
+	// ValidateUTF8Body if enabled, JSON binding through the Context buffers the request body
+	// and rejects it with ErrInvalidUTF8Body before decoding if it is not valid UTF-8, so
+	// MustBindWith and the Bind* shortcuts answer 400.
+	ValidateUTF8Body bool
+
+	// QueryArrayBrackets if enabled, Context.QueryArray and Context.GetQueryArray also
+	// return the values sent as "key[]", the way PHP and Rails clients encode arrays.
+	// The values of "key" come first, followed by those of "key[]".
+	QueryArrayBrackets bool
+
+	// RequestIDKey is the Context key under which a middleware such as RequestID stores
+	// the request id, see Context.Fail. Without a value there, the X-Request-Id header is used.
+	RequestIDKey string
+
+	// BindErrorHandler writes the response when Context.MustBindJSON fails to bind.
+	// If nil, DefaultBindErrorHandler is used.
+	BindErrorHandler BindErrorHandlerFunc
+
+	// ResponseWrapper if set, replaces the object rendered by Context.JSON and Context.PureJSON
+	// with what it returns, e.g. to put every response in an envelope.
+	ResponseWrapper ResponseWrapperFunc
+
+	// ResponseWrapperSkipErrors if enabled, responses with a status code of 400 or above are
+	// rendered as is, without calling ResponseWrapper.
+	ResponseWrapperSkipErrors bool
+
 	delims           render.Delims
 	secureJSONPrefix string
 	HTMLRender       render.HTMLRender
@@ -163,11 +255,22 @@ type Engine struct {
 	noRoute          HandlersChain
 	noMethod         HandlersChain
 	pool             sync.Pool
-	trees            methodTrees
-	maxParams        uint16
-	maxSections      uint16
-	trustedProxies   []string
-	trustedCIDRs     []*net.IPNet
+	routeTable
+	// routesMu guards the trees and static routes of routeTable, and maxParams and maxSections,
+	// against routes added or removed while requests are served
+	routesMu sync.RWMutex
+	// routesServed is set once routes were handed out to requests, after which the trees
+	// are copied before adding a route instead of modified
+	routesServed   int32
+	hosts          []*hostRoutes
+	groupNoRoutes  []groupNoRoute
+	useOnMatch     bool
+	maxParams      uint16
+	maxSections    uint16
+	trustedProxies []string
+	routeHooks     []RouteRegisterFunc
+	versions       *VersionRouter
+	trustedCIDRs   []*net.IPNet
 }
 
 var _ IRouter = (*Engine)(nil)
@@ -180,6 +283,8 @@ var _ IRouter = (*Engine)(nil)
 // - ForwardedByClientIP:    true
 // - UseRawPath:             false
 // - UnescapePathValues:     true
+// - ContextWithFallback:    true
+// - RequestIDKey:           DefaultRequestIDKey
 func New() *Engine {
 	debugPrintWARNINGNew()
 	engine := &Engine{
@@ -199,7 +304,9 @@ func New() *Engine {
 		RemoveExtraSlash:       false,
 		UnescapePathValues:     true,
 		MaxMultipartMemory:     defaultMultipartMemory,
-		trees:                  make(methodTrees, 0, 9),
+		ContextWithFallback:    true,
+		RequestIDKey:           DefaultRequestIDKey,
+		routeTable:             routeTable{trees: make(methodTrees, 0, 9)},
 		delims:                 render.Delims{Left: "{{", Right: "}}"},
 		secureJSONPrefix:       "while(1);",
 		trustedProxies:         []string{"0.0.0.0/0", "::/0"},
@@ -207,6 +314,8 @@ func New() *Engine {
 	}
 	engine.RouterGroup.engine = engine
 	engine.pool.New = func() any {
+		engine.routesMu.RLock()
+		defer engine.routesMu.RUnlock()
 		return engine.allocateContext(engine.maxParams)
 	}
 	return engine
@@ -295,6 +404,41 @@ func (engine *Engine) NoRoute(handlers ...HandlerFunc) {
 	engine.rebuild404Handlers()
 }
 
+// groupNoRoute holds the NoRoute handlers of a RouterGroup.
+type groupNoRoute struct {
+	prefix   string
+	handlers HandlersChain
+}
+
+// addGroupNoRoute stores the NoRoute handlers of prefix, keeping the longest prefixes first.
+func (engine *Engine) addGroupNoRoute(prefix string, handlers HandlersChain) {
+	for i, nr := range engine.groupNoRoutes {
+		if nr.prefix == prefix {
+			engine.groupNoRoutes[i].handlers = handlers
+			return
+		}
+	}
+	i := 0
+	for i < len(engine.groupNoRoutes) && len(engine.groupNoRoutes[i].prefix) >= len(prefix) {
+		i++
+	}
+	engine.groupNoRoutes = append(engine.groupNoRoutes, groupNoRoute{})
+	copy(engine.groupNoRoutes[i+1:], engine.groupNoRoutes[i:])
+	engine.groupNoRoutes[i] = groupNoRoute{prefix: prefix, handlers: handlers}
+}
+
+// noRouteHandlers returns the NoRoute handlers of the group with the longest prefix of
+// rPath, or the engine's.
+func (engine *Engine) noRouteHandlers(rPath string) HandlersChain {
+	for _, nr := range engine.groupNoRoutes {
+		if strings.HasPrefix(rPath, nr.prefix) &&
+			(len(rPath) == len(nr.prefix) || rPath[len(nr.prefix)] == '/') {
+			return nr.handlers
+		}
+	}
+	return engine.allNoRoute
+}
+
 // NoMethod sets the handlers called when Engine.HandleMethodNotAllowed = true.
 func (engine *Engine) NoMethod(handlers ...HandlerFunc) {
 	engine.noMethod = handlers
@@ -311,29 +455,101 @@ func (engine *Engine) Use(middleware ...HandlerFunc) IRoutes {
 	return engine
 }
 
+// UseOnMatch attaches a global middleware that, unlike the middleware of Use, only runs for
+// requests matching a route: it is left out of the 404 and 405 handlers, including the NoRoute
+// handlers of groups, and of automatic OPTIONS responses. Like Use, it is added to the routes
+// and groups registered afterwards, after the middleware attached before it.
+func (engine *Engine) UseOnMatch(middleware ...HandlerFunc) IRoutes {
+	engine.useOnMatch = true
+	engine.Handlers = append(engine.Handlers, middleware...)
+	return engine
+}
+
+// OnRouteRegister adds a hook called whenever a route is registered on the engine, including
+// routes of groups and of Mount, with the method, the absolute path pattern and the full handlers
+// chain, group middleware included. The hook may replace or modify the chain, e.g. to prepend
+// instrumentation named after the path, before the route is stored. Hooks run in the order they
+// were added and only see routes registered after them.
+func (engine *Engine) OnRouteRegister(hook RouteRegisterFunc) {
+	engine.routeHooks = append(engine.routeHooks, hook)
+}
+
 func (engine *Engine) rebuild404Handlers() {
-	engine.allNoRoute = engine.combineHandlers(engine.noRoute)
+	engine.allNoRoute = engine.combineNoMatchHandlers(engine.noRoute)
 }
 
 func (engine *Engine) rebuild405Handlers() {
-	engine.allNoMethod = engine.combineHandlers(engine.noMethod)
+	engine.allNoMethod = engine.combineNoMatchHandlers(engine.noMethod)
+}
+
+// routeTable holds the routes of the engine, or of one of its hosts, see Engine.Host.
+type routeTable struct {
+	trees methodTrees
+	// routes without params are also kept by method and path, so handleHTTPRequest
+	// can serve them without walking the tree
+	staticRoutes map[string]map[string]HandlersChain
 }
 
 func (engine *Engine) addRoute(method, path string, handlers HandlersChain) {
+	engine.addRouteTo(&engine.routeTable, method, path, handlers)
+}
+
+func (engine *Engine) addRouteTo(table *routeTable, method, path string, handlers HandlersChain) {
 	assert1(path[0] == '/', "path must begin with '/'")
 	assert1(method != "", "HTTP method can not be empty")
+	for _, hook := range engine.routeHooks {
+		hook(method, path, (*[]HandlerFunc)(&handlers))
+	}
 	assert1(len(handlers) > 0, "there must be at least one handler")
+	assert1(len(handlers) < int(abortIndex), "too many handlers")
 
 	debugPrintRoute(method, path, handlers)
 
-	root := engine.trees.get(method)
+	engine.routesMu.Lock()
+	defer engine.routesMu.Unlock()
+	// once requests may be walking the trees, the route is added to a copy swapped in
+	// afterwards, as RemoveRoute does
+	served := atomic.LoadInt32(&engine.routesServed) != 0
+
+	root := table.trees.get(method)
 	if root == nil {
 		root = new(node)
 		root.fullPath = "/"
-		engine.trees = append(engine.trees, methodTree{method: method, root: root})
+		trees := make(methodTrees, len(table.trees), len(table.trees)+1)
+		copy(trees, table.trees)
+		table.trees = append(trees, methodTree{method: method, root: root})
+	} else if served {
+		root = root.clone()
+		trees := make(methodTrees, len(table.trees))
+		for i, tree := range table.trees {
+			if tree.method == method {
+				tree.root = root
+			}
+			trees[i] = tree
+		}
+		table.trees = trees
 	}
 	root.addRoute(path, handlers)
 
+	if !strings.ContainsAny(path, ":*") {
+		staticRoutes, routes := table.staticRoutes, table.staticRoutes[method]
+		if staticRoutes == nil || served {
+			staticRoutes = make(map[string]map[string]HandlersChain, len(table.staticRoutes)+1)
+			for m, routes := range table.staticRoutes {
+				staticRoutes[m] = routes
+			}
+		}
+		if routes == nil || served {
+			routes = make(map[string]HandlersChain, len(table.staticRoutes[method])+1)
+			for p, handlers := range table.staticRoutes[method] {
+				routes[p] = handlers
+			}
+			staticRoutes[method] = routes
+		}
+		routes[path] = handlers
+		table.staticRoutes = staticRoutes
+	}
+
 	if paramsCount := countParams(path); paramsCount > engine.maxParams {
 		engine.maxParams = paramsCount
 	}
@@ -344,23 +560,106 @@ func (engine *Engine) addRoute(method, path string, handlers HandlersChain) {
 }
 
 // Routes returns a slice of registered routes, including some useful information, such as:
-// the http method, path and the handler name.
+// the http method, path and the handler name. The routes of Engine.Host groups come last.
 func (engine *Engine) Routes() (routes RoutesInfo) {
+	engine.routesMu.RLock()
+	defer engine.routesMu.RUnlock()
 	for _, tree := range engine.trees {
 		routes = iterate("", tree.method, routes, tree.root)
 	}
+	for _, host := range engine.hosts {
+		n := len(routes)
+		for _, tree := range host.trees {
+			routes = iterate("", tree.method, routes, tree.root)
+		}
+		for i := n; i < len(routes); i++ {
+			routes[i].Host = host.pattern
+		}
+	}
 	return routes
 }
 
+// RemoveRoute removes the route registered for method and path, as passed when registering
+// it, e.g. "/users/:id", and reports whether there was one. Requests matching it afterwards
+// get 404, or 405 when HandleMethodNotAllowed is set and another method has the path.
+// The tree of method is rebuilt without the route and swapped in, so RemoveRoute may be
+// called while requests are served, including from a handler; requests already routed
+// finish with the removed handlers. Routes of Engine.Host groups are not removed.
+// Routes may likewise be added while requests are served, but not new Engine.Host groups.
+func (engine *Engine) RemoveRoute(method, path string) bool {
+	engine.routesMu.Lock()
+	defer engine.routesMu.Unlock()
+
+	old := engine.trees.get(method)
+	if old == nil {
+		return false
+	}
+	root := new(node)
+	root.fullPath = "/"
+	if !rebuildTree(root, "", path, old) {
+		return false
+	}
+
+	trees := make(methodTrees, 0, cap(engine.trees))
+	for _, tree := range engine.trees {
+		if tree.method != method {
+			trees = append(trees, tree)
+		} else if len(root.handlers) > 0 || len(root.children) > 0 {
+			trees = append(trees, methodTree{method: method, root: root})
+		}
+	}
+	engine.trees = trees
+
+	if _, ok := engine.staticRoutes[method][path]; ok {
+		staticRoutes := make(map[string]map[string]HandlersChain, len(engine.staticRoutes))
+		for m, routes := range engine.staticRoutes {
+			staticRoutes[m] = routes
+		}
+		routes := make(map[string]HandlersChain, len(staticRoutes[method]))
+		for p, handlers := range staticRoutes[method] {
+			if p != path {
+				routes[p] = handlers
+			}
+		}
+		staticRoutes[method] = routes
+		engine.staticRoutes = staticRoutes
+	}
+	return true
+}
+
+// rebuildTree adds the routes of old to root, except the one registered for removed,
+// and reports whether it was found. old is left untouched for the requests walking it.
+func rebuildTree(root *node, path, removed string, old *node) (found bool) {
+	path += old.path
+	if len(old.handlers) > 0 {
+		if path == removed {
+			found = true
+		} else {
+			root.addRoute(path, old.handlers)
+		}
+	}
+	for _, child := range old.children {
+		if rebuildTree(root, path, removed, child) {
+			found = true
+		}
+	}
+	return found
+}
+
 func iterate(path, method string, routes RoutesInfo, root *node) RoutesInfo {
 	path += root.path
 	if len(root.handlers) > 0 {
 		handlerFunc := root.handlers.Last()
+		handlers := make([]string, len(root.handlers))
+		for i, h := range root.handlers {
+			handlers[i] = nameOfFunction(h)
+		}
 		routes = append(routes, RouteInfo{
 			Method:      method,
 			Path:        path,
-			Handler:     nameOfFunction(handlerFunc),
+			Handler:     handlers[len(handlers)-1],
 			HandlerFunc: handlerFunc,
+			Handlers:    handlers,
 		})
 	}
 	for _, child := range root.children {
@@ -369,6 +668,45 @@ func iterate(path, method string, routes RoutesInfo, root *node) RoutesInfo {
 	return routes
 }
 
+// RouteTree returns a snapshot of the route tree of the given HTTP method, one RouteNodeInfo
+// per node in depth-first order, or nil when no route is registered for method.
+// Modifying the result does not affect the engine.
+func (engine *Engine) RouteTree(method string) []RouteNodeInfo {
+	engine.routesMu.RLock()
+	engine.serveRoutes()
+	root := engine.trees.get(method)
+	engine.routesMu.RUnlock()
+	if root == nil {
+		return nil
+	}
+	return routeTree("", nil, root, nil)
+}
+
+func routeTree(path string, params []string, root *node, nodes []RouteNodeInfo) []RouteNodeInfo {
+	path += root.path
+	// param nodes are ":name", the catch-all node holding the name is "/*name"
+	if root.nType == param {
+		params = append(params[:len(params):len(params)], root.paramKey())
+	} else if i := strings.IndexByte(root.path, '*'); i >= 0 && root.nType == catchAll {
+		params = append(params[:len(params):len(params)], root.path[i+1:])
+	}
+	info := RouteNodeInfo{
+		Path:     root.path,
+		FullPath: path,
+		Params:   params,
+		Priority: root.priority,
+		Handlers: len(root.handlers),
+	}
+	for _, h := range root.handlers {
+		info.HandlerNames = append(info.HandlerNames, nameOfFunction(h))
+	}
+	nodes = append(nodes, info)
+	for _, child := range root.children {
+		nodes = routeTree(path, params, child, nodes)
+	}
+	return nodes
+}
+
 // Run attaches the router to a http.Server and starts listening and serving HTTP requests.
 // It is a shortcut for http.ListenAndServe(addr, router)
 // Note: this method will block the calling goroutine indefinitely unless an error happens.
@@ -386,35 +724,115 @@ func (engine *Engine) Run(addr ...string) (err error) {
 	return
 }
 
+// RunGraceful attaches the router to a http.Server listening on addr and serves requests
+// until one of signals is received, SIGINT and SIGTERM if none are given. It then stops
+// accepting connections and waits up to timeout for in-flight requests to complete.
+// It returns nil once they are drained, context.DeadlineExceeded if the timeout elapsed
+// first, or the error that stopped the server.
+func (engine *Engine) RunGraceful(addr string, timeout time.Duration, signals ...os.Signal) error {
+	return engine.RunServerGraceful(&http.Server{Addr: addr}, timeout, signals...)
+}
+
+// RunServerGraceful works like RunGraceful with a pre-built server. The router is used as
+// srv.Handler unless one is set.
+func (engine *Engine) RunServerGraceful(srv *http.Server, timeout time.Duration, signals ...os.Signal) (err error) {
+	defer func() { debugPrintError(err) }()
+
+	if engine.isUnsafeTrustedProxies() {
+		debugPrint("[WARNING] You trusted all proxies, this is NOT safe. We recommend you to set a value.\n" +
+			"Please check https://pkg.go.dev/github.com/gin-gonic/gin#readme-don-t-trust-all-proxies for details.")
+	}
+	if srv.Handler == nil {
+		srv.Handler = engine.Handler()
+	}
+	if len(signals) == 0 {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), signals...)
+	defer stop()
+
+	debugPrint("Listening and serving HTTP on %s\n", srv.Addr)
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err = <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	debugPrint("Shutting down, draining in-flight requests for up to %s\n", timeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err = srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err = <-serveErr; err == http.ErrServerClosed {
+		err = nil
+	}
+	return err
+}
+
+// TrustedProxiesError is returned by SetTrustedProxies when some entries are neither an
+// IP address nor a CIDR. Errs holds the parse error of each of them, in the same order.
+type TrustedProxiesError struct {
+	Proxies []string
+	Errs    []error
+}
+
+func (e *TrustedProxiesError) Error() string {
+	return "invalid trusted proxies: " + strings.Join(e.Proxies, ", ")
+}
+
+// Unwrap returns the parse error of the first invalid entry.
+func (e *TrustedProxiesError) Unwrap() error {
+	return e.Errs[0]
+}
+
 func (engine *Engine) prepareTrustedCIDRs() ([]*net.IPNet, error) {
 	if engine.trustedProxies == nil {
 		return nil, nil
 	}
 
 	cidr := make([]*net.IPNet, 0, len(engine.trustedProxies))
+	var invalid []string
+	var errs []error
 	for _, trustedProxy := range engine.trustedProxies {
-		if !strings.Contains(trustedProxy, "/") {
-			ip := parseIP(trustedProxy)
-			if ip == nil {
-				return cidr, &net.ParseError{Type: "IP address", Text: trustedProxy}
-			}
-
-			switch len(ip) {
-			case net.IPv4len:
-				trustedProxy += "/32"
-			case net.IPv6len:
-				trustedProxy += "/128"
-			}
-		}
-		_, cidrNet, err := net.ParseCIDR(trustedProxy)
+		cidrNet, err := parseTrustedProxy(strings.TrimSpace(trustedProxy))
 		if err != nil {
-			return cidr, err
+			invalid = append(invalid, trustedProxy)
+			errs = append(errs, err)
+			continue
 		}
 		cidr = append(cidr, cidrNet)
 	}
+	if len(invalid) > 0 {
+		return nil, &TrustedProxiesError{Proxies: invalid, Errs: errs}
+	}
 	return cidr, nil
 }
 
+// parseTrustedProxy parses an IP address or a CIDR, an address gets a full mask.
+func parseTrustedProxy(trustedProxy string) (*net.IPNet, error) {
+	if !strings.Contains(trustedProxy, "/") {
+		ip := parseIP(trustedProxy)
+		if ip == nil {
+			return nil, &net.ParseError{Type: "IP address", Text: trustedProxy}
+		}
+
+		switch len(ip) {
+		case net.IPv4len:
+			trustedProxy += "/32"
+		case net.IPv6len:
+			trustedProxy += "/128"
+		}
+	}
+	_, cidrNet, err := net.ParseCIDR(trustedProxy)
+	return cidrNet, err
+}
+
 // SetTrustedProxies set a list of network origins (IPv4 addresses,
 // IPv4 CIDRs, IPv6 addresses or IPv6 CIDRs) from which to trust
 // request's headers that contain alternative client IP when
@@ -423,6 +841,8 @@ func (engine *Engine) prepareTrustedCIDRs() ([]*net.IPNet, error) {
 // by default. If you want to disable this feature, use
 // Engine.SetTrustedProxies(nil), then Context.ClientIP() will
 // return the remote address directly.
+// Surrounding spaces of the entries are ignored. If any entry is invalid,
+// a *TrustedProxiesError listing all of them is returned and no proxy is trusted.
 func (engine *Engine) SetTrustedProxies(trustedProxies []string) error {
 	engine.trustedProxies = trustedProxies
 	return engine.parseTrustedProxies()
@@ -509,6 +929,17 @@ func (engine *Engine) RunTLS(addr, certFile, keyFile string) (err error) {
 // through the specified unix socket (i.e. a file).
 // Note: this method will block the calling goroutine indefinitely unless an error happens.
 func (engine *Engine) RunUnix(file string) (err error) {
+	return engine.runUnix(file, nil)
+}
+
+// RunUnixMode works like RunUnix, setting the permissions of the socket file to mode before
+// serving, e.g. 0o660 to let a proxy running in the group of the file connect. The socket
+// file is removed when serving stops.
+func (engine *Engine) RunUnixMode(file string, mode os.FileMode) error {
+	return engine.runUnix(file, &mode)
+}
+
+func (engine *Engine) runUnix(file string, mode *os.FileMode) (err error) {
 	debugPrint("Listening and serving HTTP on unix:/%s", file)
 	defer func() { debugPrintError(err) }()
 
@@ -524,6 +955,11 @@ func (engine *Engine) RunUnix(file string) (err error) {
 	defer listener.Close()
 	defer os.Remove(file)
 
+	if mode != nil {
+		if err = os.Chmod(file, *mode); err != nil {
+			return
+		}
+	}
 	err = http.Serve(listener, engine.Handler())
 	return
 }
@@ -600,9 +1036,22 @@ func (engine *Engine) handleHTTPRequest(c *Context) {
 	if engine.RemoveExtraSlash {
 		rPath = cleanPath(rPath)
 	}
+	if engine.versions != nil {
+		rPath = engine.versions.resolve(c, rPath)
+	}
+	table := engine.requestRoutes(c)
+
+	// Fast path for routes without params
+	if handlers, ok := table.staticRoutes[httpMethod][rPath]; ok {
+		c.handlers = handlers
+		c.fullPath = rPath
+		c.Next()
+		c.writermem.WriteHeaderNow()
+		return
+	}
 
 	// Find root of the tree for the given HTTP method
-	t := engine.trees
+	t := table.trees
 	for i, tl := 0, len(t); i < tl; i++ {
 		if t[i].method != httpMethod {
 			continue
@@ -622,7 +1071,7 @@ func (engine *Engine) handleHTTPRequest(c *Context) {
 		}
 		if httpMethod != http.MethodConnect && rPath != "/" {
 			if value.tsr && engine.RedirectTrailingSlash {
-				redirectTrailingSlash(c)
+				redirectTrailingSlash(c, engine.TrailingSlashRedirectStatus)
 				return
 			}
 			if engine.RedirectFixedPath && redirectFixedPath(c, root, engine.RedirectFixedPath) {
@@ -632,22 +1081,82 @@ func (engine *Engine) handleHTTPRequest(c *Context) {
 		break
 	}
 
-	if engine.HandleMethodNotAllowed {
-		for _, tree := range engine.trees {
-			if tree.method == httpMethod {
-				continue
+	if httpMethod == http.MethodOptions && engine.HandleOptionsAutomatically {
+		if allowed := table.allowedMethods(rPath, c.skippedNodes, unescape); len(allowed) > 0 {
+			c.writermem.Header().Set("Allow", strings.Join(append(allowed, http.MethodOptions), ", "))
+			c.handlers = engine.Handlers
+			if engine.useOnMatch {
+				c.handlers = engine.noMatchHandlers
 			}
-			if value := tree.root.getValue(rPath, nil, c.skippedNodes, unescape); value.handlers != nil {
-				c.handlers = engine.allNoMethod
-				serveError(c, http.StatusMethodNotAllowed, default405Body)
-				return
+			c.writermem.status = http.StatusNoContent
+			c.Next()
+			c.writermem.WriteHeaderNow()
+			return
+		}
+	}
+
+	if engine.HandleMethodNotAllowed {
+		if allowed := table.allowedMethods(rPath, c.skippedNodes, unescape); len(allowed) > 0 {
+			if engine.HandleOptionsAutomatically && !hasMethod(allowed, http.MethodOptions) {
+				allowed = append(allowed, http.MethodOptions)
 			}
+			c.allowedMethods = allowed
+			c.writermem.Header().Set("Allow", strings.Join(allowed, ", "))
+			c.handlers = engine.allNoMethod
+			serveError(c, http.StatusMethodNotAllowed, default405Body)
+			return
 		}
 	}
-	c.handlers = engine.allNoRoute
+	c.handlers = engine.noRouteHandlers(rPath)
 	serveError(c, http.StatusNotFound, default404Body)
 }
 
+// requestRoutes returns the routes to match the request of c against. They are copied
+// under routesMu, routes added or removed afterwards swap the trees instead of modifying them.
+func (engine *Engine) requestRoutes(c *Context) routeTable {
+	table := &engine.routeTable
+	if len(engine.hosts) > 0 {
+		table = engine.hostTable(c.Request.Host)
+	}
+	engine.routesMu.RLock()
+	defer engine.routesMu.RUnlock()
+	engine.serveRoutes()
+	// a route added since c was allocated may have more sections to backtrack over
+	if cap(*c.skippedNodes) < int(engine.maxSections) {
+		skippedNodes := make([]skippedNode, 0, engine.maxSections)
+		c.skippedNodes = &skippedNodes
+	}
+	return *table
+}
+
+// serveRoutes records that the trees are handed out to be read without routesMu,
+// which must be held for reading.
+func (engine *Engine) serveRoutes() {
+	if atomic.LoadInt32(&engine.routesServed) == 0 {
+		atomic.StoreInt32(&engine.routesServed, 1)
+	}
+}
+
+// allowedMethods returns the methods that have a route matching rPath, in registration order.
+func (table *routeTable) allowedMethods(rPath string, skippedNodes *[]skippedNode, unescape bool) []string {
+	var allowed []string
+	for _, tree := range table.trees {
+		if value := tree.root.getValue(rPath, nil, skippedNodes, unescape); value.handlers != nil {
+			allowed = append(allowed, tree.method)
+		}
+	}
+	return allowed
+}
+
+func hasMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
 var mimePlain = []string{MIMEPlain}
 
 func serveError(c *Context, code int, defaultMessage []byte) {
@@ -667,7 +1176,7 @@ func serveError(c *Context, code int, defaultMessage []byte) {
 	c.writermem.WriteHeaderNow()
 }
 
-func redirectTrailingSlash(c *Context) {
+func redirectTrailingSlash(c *Context, code int) {
 	req := c.Request
 	p := req.URL.Path
 	if prefix := path.Clean(c.Request.Header.Get("X-Forwarded-Prefix")); prefix != "." {
@@ -680,7 +1189,7 @@ func redirectTrailingSlash(c *Context) {
 	if length := len(p); length > 1 && p[length-1] == '/' {
 		req.URL.Path = p[:length-1]
 	}
-	redirectRequest(c)
+	redirectRequest(c, code)
 }
 
 func redirectFixedPath(c *Context, root *node, trailingSlash bool) bool {
@@ -689,19 +1198,22 @@ func redirectFixedPath(c *Context, root *node, trailingSlash bool) bool {
 
 	if fixedPath, ok := root.findCaseInsensitivePath(cleanPath(rPath), trailingSlash); ok {
 		req.URL.Path = bytesconv.BytesToString(fixedPath)
-		redirectRequest(c)
+		redirectRequest(c, 0)
 		return true
 	}
 	return false
 }
 
-func redirectRequest(c *Context) {
+// redirectRequest redirects to c.Request.URL with 301 for GET requests, and with code,
+// or 307 if zero, for the other methods.
+func redirectRequest(c *Context, code int) {
 	req := c.Request
 	rPath := req.URL.Path
 	rURL := req.URL.String()
 
-	code := http.StatusMovedPermanently // Permanent redirect, request with GET method
-	if req.Method != http.MethodGet {
+	if req.Method == http.MethodGet {
+		code = http.StatusMovedPermanently // Permanent redirect, request with GET method
+	} else if code == 0 {
 		code = http.StatusTemporaryRedirect
 	}
 	debugPrint("redirecting request %d: %s --> %s", code, rPath, rURL)
@@ -6,6 +6,8 @@ package gin
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"crypto/tls"
 	"fmt"
 	"html/template"
@@ -17,10 +19,14 @@ import (
 	"path/filepath"
 	"runtime"
 	"sync"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
 )
 
 // params[0]=url example:http://127.0.0.1:8080/index (cannot be empty)
@@ -77,6 +83,91 @@ func TestRunEmpty(t *testing.T) {
 	testRequest(t, "http://localhost:8080/example")
 }
 
+// startGraceful runs router.RunServerGraceful on a free port and returns its address
+// once the server accepts connections.
+func startGraceful(t *testing.T, router *Engine, timeout time.Duration) (string, chan error) {
+	if runtime.GOOS == "windows" {
+		t.Skip("can not send signals to the current process on windows")
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	addr := l.Addr().String()
+	l.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- router.RunServerGraceful(&http.Server{Addr: addr}, timeout, syscall.SIGHUP)
+	}()
+	for i := 0; i < 100; i++ {
+		if c, err := net.Dial("tcp", addr); err == nil {
+			c.Close()
+			return addr, done
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("server did not start")
+	return "", nil
+}
+
+func sendSignal(t *testing.T, sig os.Signal) {
+	p, err := os.FindProcess(os.Getpid())
+	assert.NoError(t, err)
+	assert.NoError(t, p.Signal(sig))
+}
+
+func TestRunGraceful(t *testing.T) {
+	started := make(chan struct{})
+	router := New()
+	router.GET("/slow", func(c *Context) {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		c.String(http.StatusOK, "it worked")
+	})
+	addr, done := startGraceful(t, router, time.Second)
+
+	resp := make(chan string, 1)
+	go func() {
+		res, err := http.Get("http://" + addr + "/slow")
+		if !assert.NoError(t, err) {
+			resp <- ""
+			return
+		}
+		defer res.Body.Close()
+		body, _ := io.ReadAll(res.Body)
+		resp <- string(body)
+	}()
+	<-started
+	sendSignal(t, syscall.SIGHUP)
+
+	assert.NoError(t, <-done)
+	assert.Equal(t, "it worked", <-resp)
+	_, err := net.Dial("tcp", addr)
+	assert.Error(t, err)
+}
+
+func TestRunGracefulTimeout(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	router := New()
+	router.GET("/blocked", func(c *Context) {
+		close(started)
+		<-release
+	})
+	addr, done := startGraceful(t, router, 50*time.Millisecond)
+
+	go http.Get("http://" + addr + "/blocked") //nolint: errcheck
+	<-started
+	sendSignal(t, syscall.SIGHUP)
+
+	assert.ErrorIs(t, <-done, context.DeadlineExceeded)
+}
+
+func TestRunGracefulListenError(t *testing.T) {
+	router := New()
+	assert.Error(t, router.RunGraceful("bad address", time.Second))
+}
+
 func TestBadTrustedCIDRs(t *testing.T) {
 	router := New()
 	assert.Error(t, router.SetTrustedProxies([]string{"hello/world"}))
@@ -205,6 +296,86 @@ func TestPusher(t *testing.T) {
 	testRequest(t, "https://localhost:8449/pusher")
 }
 
+func TestContextPushHTTP2(t *testing.T) {
+	pushErr := make(chan error, 1)
+	router := New()
+	router.GET("/page", func(c *Context) {
+		pushErr <- c.Push("/assets/app.css", nil)
+		c.String(http.StatusOK, "page")
+	})
+	router.GET("/assets/app.css", func(c *Context) {
+		c.String(http.StatusOK, "body{}")
+	})
+	ts := httptest.NewUnstartedServer(router)
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	// net/http clients never accept pushes, so speak HTTP/2 by hand with push enabled.
+	conn, err := tls.Dial("tcp", ts.Listener.Addr().String(), &tls.Config{
+		InsecureSkipVerify: true,
+		NextProtos:         []string{http2.NextProtoTLS},
+	})
+	require.NoError(t, err)
+	defer conn.Close()
+	require.Equal(t, http2.NextProtoTLS, conn.ConnectionState().NegotiatedProtocol)
+	_, err = io.WriteString(conn, http2.ClientPreface)
+	require.NoError(t, err)
+
+	framer := http2.NewFramer(conn, conn)
+	require.NoError(t, framer.WriteSettings(http2.Setting{ID: http2.SettingEnablePush, Val: 1}))
+	var block bytes.Buffer
+	enc := hpack.NewEncoder(&block)
+	for _, f := range []hpack.HeaderField{
+		{Name: ":method", Value: http.MethodGet},
+		{Name: ":scheme", Value: "https"},
+		{Name: ":authority", Value: ts.Listener.Addr().String()},
+		{Name: ":path", Value: "/page"},
+	} {
+		require.NoError(t, enc.WriteField(f))
+	}
+	require.NoError(t, framer.WriteHeaders(http2.HeadersFrameParam{
+		StreamID:      1,
+		BlockFragment: block.Bytes(),
+		EndStream:     true,
+		EndHeaders:    true,
+	}))
+
+	var promised []string
+	dec := hpack.NewDecoder(4096, func(f hpack.HeaderField) {
+		if f.Name == ":path" {
+			promised = append(promised, f.Value)
+		}
+	})
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	for {
+		frame, err := framer.ReadFrame()
+		require.NoError(t, err)
+		if f, ok := frame.(*http2.PushPromiseFrame); ok {
+			_, err = dec.Write(f.HeaderBlockFragment())
+			require.NoError(t, err)
+			break
+		}
+		if f, ok := frame.(*http2.SettingsFrame); ok && !f.IsAck() {
+			require.NoError(t, framer.WriteSettingsAck())
+		}
+	}
+	assert.NoError(t, <-pushErr)
+	assert.Equal(t, []string{"/assets/app.css"}, promised)
+}
+
+func TestContextPushHTTP1(t *testing.T) {
+	router := New()
+	router.GET("/page", func(c *Context) {
+		assert.ErrorIs(t, c.Push("/assets/app.css", nil), ErrPushNotSupported)
+		c.String(http.StatusOK, "page")
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	testRequest(t, ts.URL+"/page", "", "page")
+}
+
 func TestRunEmptyWithEnv(t *testing.T) {
 	os.Setenv("PORT", "3123")
 	router := New()
@@ -274,6 +445,49 @@ func TestBadUnixSocket(t *testing.T) {
 	assert.Error(t, router.RunUnix("#/tmp/unix_unit_test"))
 }
 
+func TestUnixSocketMode(t *testing.T) {
+	if isWindows() {
+		t.Skip("unix socket permissions are not supported on windows")
+	}
+	router := New()
+	router.GET("/example", func(c *Context) { c.String(http.StatusOK, "it worked") })
+	unixTestSocket := filepath.Join(t.TempDir(), "unix_mode_test")
+
+	done := make(chan error, 1)
+	go func() {
+		done <- router.RunUnixMode(unixTestSocket, 0o660)
+	}()
+	// wait for the socket to be created and chmod-ed
+	var (
+		info os.FileInfo
+		err  error
+	)
+	for i := 0; i < 100; i++ {
+		if info, err = os.Stat(unixTestSocket); err == nil && info.Mode().Perm() == 0o660 {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if assert.NoError(t, err) {
+		assert.Equal(t, os.ModeSocket, info.Mode().Type())
+		assert.Equal(t, os.FileMode(0o660), info.Mode().Perm())
+	}
+
+	c, err := net.Dial("unix", unixTestSocket)
+	assert.NoError(t, err)
+	fmt.Fprint(c, "GET /example HTTP/1.0\r\n\r\n")
+	response, err := io.ReadAll(c)
+	assert.NoError(t, err)
+	assert.Contains(t, string(response), "it worked")
+
+	assert.Error(t, router.RunUnixMode(filepath.Join(t.TempDir(), "missing", "socket"), 0o660))
+	select {
+	case err := <-done:
+		t.Fatalf("server stopped: %v", err)
+	default:
+	}
+}
+
 func TestFileDescriptor(t *testing.T) {
 	router := New()
 
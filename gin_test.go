@@ -6,6 +6,7 @@ package gin
 
 import (
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"io"
@@ -353,6 +354,54 @@ func TestAddRouteFails(t *testing.T) {
 	})
 }
 
+func TestOnRouteRegister(t *testing.T) {
+	var registered []string
+	router := New()
+	router.Use(func(c *Context) { c.Header("X-Order", "use") })
+	router.OnRouteRegister(func(method, path string, handlers *[]HandlerFunc) {
+		registered = append(registered, method+" "+path)
+		route := path
+		*handlers = append([]HandlerFunc{func(c *Context) {
+			c.Header("X-Span", route)
+		}}, *handlers...)
+	})
+
+	router.GET("/ping", func(c *Context) { c.String(http.StatusOK, c.Writer.Header().Get("X-Span")) })
+	v1 := router.Group("/v1", func(c *Context) { c.Header("X-Group", "v1") })
+	v1.POST("/users/:id", func(c *Context) {})
+
+	sub := New()
+	sub.GET("/health", func(c *Context) {})
+	router.Mount("/sub", sub)
+
+	assert.Equal(t, []string{"GET /ping", "POST /v1/users/:id", "GET /sub/health"}, registered)
+
+	w := PerformRequest(router, http.MethodGet, "/ping")
+	assert.Equal(t, "/ping", w.Body.String())
+	assert.Equal(t, "use", w.Header().Get("X-Order"))
+
+	w = PerformRequest(router, http.MethodPost, "/v1/users/1")
+	assert.Equal(t, "/v1/users/:id", w.Header().Get("X-Span"))
+	assert.Equal(t, "v1", w.Header().Get("X-Group"))
+
+	w = PerformRequest(router, http.MethodGet, "/sub/health")
+	assert.Equal(t, "/sub/health", w.Header().Get("X-Span"))
+	// hooks do not change what the mounted engine registered
+	w = PerformRequest(sub, http.MethodGet, "/health")
+	assert.Empty(t, w.Header().Get("X-Span"))
+}
+
+func TestOnRouteRegisterFails(t *testing.T) {
+	router := New()
+	router.OnRouteRegister(func(method, path string, handlers *[]HandlerFunc) {
+		if path == "/empty" {
+			*handlers = nil
+		}
+	})
+	assert.Panics(t, func() { router.GET("/empty", func(c *Context) {}) })
+	assert.NotPanics(t, func() { router.GET("/", func(c *Context) {}) })
+}
+
 func TestCreateDefaultRouter(t *testing.T) {
 	router := Default()
 	assert.Len(t, router.Handlers, 2)
@@ -517,6 +566,182 @@ func TestListOfRoutes(t *testing.T) {
 	})
 }
 
+func TestListOfRoutesHandlers(t *testing.T) {
+	router := New()
+	router.Use(handlerTest2)
+	router.GET("/public", handlerTest1)
+	admin := router.Group("/admin", handlerTest1)
+	admin.POST("/users", handlerTest2)
+
+	for _, route := range router.Routes() {
+		assert.Equal(t, route.Handler, route.Handlers[len(route.Handlers)-1])
+		switch route.Path {
+		case "/public":
+			assert.Equal(t, []string{
+				"github.com/gin-gonic/gin.handlerTest2",
+				"github.com/gin-gonic/gin.handlerTest1",
+			}, route.Handlers)
+		case "/admin/users":
+			assert.Equal(t, []string{
+				"github.com/gin-gonic/gin.handlerTest2",
+				"github.com/gin-gonic/gin.handlerTest1",
+				"github.com/gin-gonic/gin.handlerTest2",
+			}, route.Handlers)
+		default:
+			t.Errorf("unexpected route %s", route.Path)
+		}
+	}
+}
+
+func TestRemoveRoute(t *testing.T) {
+	router := New()
+	router.GET("/plugins/stats", func(c *Context) { c.String(http.StatusOK, "stats") })
+	router.GET("/plugins/:name", func(c *Context) { c.String(http.StatusOK, "plugin "+c.Param("name")) })
+	router.GET("/files/*path", func(c *Context) { c.String(http.StatusOK, "file "+c.Param("path")) })
+	router.POST("/plugins/stats", func(c *Context) { c.String(http.StatusOK, "posted") })
+
+	w := PerformRequest(router, http.MethodGet, "/plugins/stats")
+	assert.Equal(t, "stats", w.Body.String())
+
+	assert.True(t, router.RemoveRoute(http.MethodGet, "/plugins/stats"))
+	assert.False(t, router.RemoveRoute(http.MethodGet, "/plugins/stats"))
+	// the param route now matches the path
+	w = PerformRequest(router, http.MethodGet, "/plugins/stats")
+	assert.Equal(t, "plugin stats", w.Body.String())
+
+	assert.True(t, router.RemoveRoute(http.MethodGet, "/plugins/:name"))
+	w = PerformRequest(router, http.MethodGet, "/plugins/stats")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = PerformRequest(router, http.MethodGet, "/files/a/b")
+	assert.Equal(t, "file /a/b", w.Body.String())
+
+	router.HandleMethodNotAllowed = true
+	w = PerformRequest(router, http.MethodGet, "/plugins/stats")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.True(t, router.RemoveRoute(http.MethodPost, "/plugins/stats"))
+	w = PerformRequest(router, http.MethodGet, "/plugins/stats")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Nil(t, router.trees.get(http.MethodPost))
+
+	assert.False(t, router.RemoveRoute(http.MethodPut, "/files/*path"))
+	assert.False(t, router.RemoveRoute(http.MethodGet, "/files/:path"))
+	assert.True(t, router.RemoveRoute(http.MethodGet, "/files/*path"))
+	assert.Empty(t, router.Routes())
+
+	// routes can be added back
+	router.GET("/plugins/:name", func(c *Context) { c.String(http.StatusOK, "again") })
+	w = PerformRequest(router, http.MethodGet, "/plugins/stats")
+	assert.Equal(t, "again", w.Body.String())
+}
+
+func TestRemoveRouteWhileServing(t *testing.T) {
+	router := New()
+	router.GET("/keep", func(c *Context) { c.String(http.StatusOK, "kept") })
+	for i := 0; i < 50; i++ {
+		router.GET("/plugins/"+strconv.Itoa(i), func(c *Context) {})
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			router.RemoveRoute(http.MethodGet, "/plugins/"+strconv.Itoa(i))
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		w := PerformRequest(router, http.MethodGet, "/keep")
+		assert.Equal(t, "kept", w.Body.String())
+		PerformRequest(router, http.MethodGet, "/plugins/"+strconv.Itoa(i%50))
+	}
+	<-done
+	assert.Len(t, router.Routes(), 1)
+}
+
+func TestAddRouteWhileServing(t *testing.T) {
+	router := New()
+	router.GET("/keep", func(c *Context) { c.String(http.StatusOK, "kept") })
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			router.GET("/plugins/"+strconv.Itoa(i), func(c *Context) {})
+			router.GET("/plugins/"+strconv.Itoa(i)+"/:a/x/:b/y", func(c *Context) {
+				c.String(http.StatusOK, c.Param("a")+c.Param("b"))
+			})
+			router.RemoveRoute(http.MethodGet, "/plugins/"+strconv.Itoa(i))
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		w := PerformRequest(router, http.MethodGet, "/keep")
+		assert.Equal(t, "kept", w.Body.String())
+		PerformRequest(router, http.MethodGet, "/plugins/"+strconv.Itoa(i%50))
+		PerformRequest(router, http.MethodGet, "/plugins/"+strconv.Itoa(i%50)+"/1/x/2/y")
+	}
+	<-done
+
+	assert.Len(t, router.Routes(), 51)
+	w := PerformRequest(router, http.MethodGet, "/plugins/7/1/x/2/y")
+	assert.Equal(t, "12", w.Body.String())
+	w = PerformRequest(router, http.MethodGet, "/plugins/7")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRouteTree(t *testing.T) {
+	router := New()
+	router.Use(handlerTest2)
+	router.GET("/users/:id", handlerTest1)
+	router.GET("/users/:id/files/*path", handlerTest1)
+	router.GET("/uploads", handlerTest1)
+
+	assert.Nil(t, router.RouteTree(http.MethodPost))
+
+	tree := router.RouteTree(http.MethodGet)
+	var leaves []RouteNodeInfo
+	for _, n := range tree {
+		if n.Handlers > 0 {
+			leaves = append(leaves, n)
+		}
+	}
+	assert.Len(t, leaves, 3)
+	assert.Equal(t, "/u", tree[0].Path)
+	assert.Equal(t, uint32(3), tree[0].Priority)
+	for _, n := range leaves {
+		assert.Equal(t, 2, n.Handlers)
+		assert.Equal(t, []string{"github.com/gin-gonic/gin.handlerTest2", "github.com/gin-gonic/gin.handlerTest1"}, n.HandlerNames)
+		switch n.FullPath {
+		case "/users/:id":
+			assert.Equal(t, []string{"id"}, n.Params)
+		case "/users/:id/files/*path":
+			assert.Equal(t, []string{"id", "path"}, n.Params)
+		case "/uploads":
+			assert.Empty(t, n.Params)
+		default:
+			t.Errorf("unexpected route %q", n.FullPath)
+		}
+	}
+
+	// the tree is a snapshot
+	tree[0].Path = "/x"
+	leaves[0].Params[0] = "x"
+	again := router.RouteTree(http.MethodGet)
+	assert.Equal(t, "/u", again[0].Path)
+	w := PerformRequest(router, http.MethodGet, "/users/7")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+// The tree of a method can be dumped as JSON, e.g. for an admin page.
+func TestRouteTreeJSON(t *testing.T) {
+	router := New()
+	router.GET("/users/:id", handlerTest1)
+
+	b, err := json.Marshal(router.RouteTree(http.MethodGet))
+
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"path":"/users/","fullPath":"/users/","priority":1,"handlers":0},`+
+		`{"path":":id","fullPath":"/users/:id","params":["id"],"priority":1,"handlers":1,"handlerNames":["github.com/gin-gonic/gin.handlerTest1"]}]`, string(b))
+}
+
 func TestEngineHandleContext(t *testing.T) {
 	r := New()
 	r.GET("/", func(c *Context) {
@@ -676,6 +901,38 @@ func TestPrepareTrustedCIRDsWith(t *testing.T) {
 	}
 }
 
+func TestSetTrustedProxiesInvalidEntries(t *testing.T) {
+	r := New()
+	r.ForwardedByClientIP = true
+
+	err := r.SetTrustedProxies([]string{" 10.0.0.0/8 ", "192.168.1.256", "172.16.0.1", "10.0.0.0/33"})
+	var proxiesErr *TrustedProxiesError
+	if !assert.ErrorAs(t, err, &proxiesErr) {
+		return
+	}
+	assert.Equal(t, []string{"192.168.1.256", "10.0.0.0/33"}, proxiesErr.Proxies)
+	assert.Equal(t, "invalid trusted proxies: 192.168.1.256, 10.0.0.0/33", err.Error())
+	if assert.Len(t, proxiesErr.Errs, 2) {
+		var parseErr *net.ParseError
+		assert.ErrorAs(t, err, &parseErr)
+		assert.Equal(t, proxiesErr.Errs[0], parseErr)
+		assert.ErrorAs(t, proxiesErr.Errs[1], &parseErr)
+		assert.Equal(t, "10.0.0.0/33", parseErr.Text)
+	}
+	assert.Empty(t, r.trustedCIDRs)
+
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.engine = r
+	c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+	c.Request.RemoteAddr = "10.1.2.3:1234"
+	c.Request.Header.Set("X-Forwarded-For", "20.20.20.20")
+	assert.Equal(t, "10.1.2.3", c.ClientIP())
+
+	assert.NoError(t, r.SetTrustedProxies([]string{" 10.0.0.0/8 "}))
+	assert.Equal(t, []*net.IPNet{parseCIDR("10.0.0.0/8")}, r.trustedCIDRs)
+	assert.Equal(t, "20.20.20.20", c.ClientIP())
+}
+
 func parseCIDR(cidr string) *net.IPNet {
 	_, parsedCIDR, err := net.ParseCIDR(cidr)
 	if err != nil {
@@ -696,3 +953,55 @@ func assertRoutePresent(t *testing.T, gotRoutes RoutesInfo, wantRoute RouteInfo)
 
 func handlerTest1(c *Context) {}
 func handlerTest2(c *Context) {}
+
+func TestUseOnMatch(t *testing.T) {
+	var calls []string
+	record := func(name string) HandlerFunc {
+		return func(c *Context) {
+			calls = append(calls, name)
+		}
+	}
+	router := New()
+	router.HandleMethodNotAllowed = true
+	router.HandleOptionsAutomatically = true
+	router.Use(record("logger"))
+	router.UseOnMatch(record("auth"))
+	router.Use(record("metrics"))
+	router.NoRoute(record("404"))
+	router.NoMethod(record("405"))
+	router.GET("/users", record("users"))
+	v1 := router.Group("/v1", record("v1"))
+	v1.GET("/items", record("items"))
+	v1.NoRoute(record("v1 404"))
+
+	testCases := []struct {
+		method string
+		path   string
+		calls  []string
+	}{
+		{http.MethodGet, "/users", []string{"logger", "auth", "metrics", "users"}},
+		{http.MethodGet, "/v1/items", []string{"logger", "auth", "metrics", "v1", "items"}},
+		{http.MethodGet, "/unknown", []string{"logger", "metrics", "404"}},
+		{http.MethodGet, "/v1/unknown", []string{"logger", "metrics", "v1", "v1 404"}},
+		{http.MethodPost, "/users", []string{"logger", "metrics", "405"}},
+		{http.MethodOptions, "/users", []string{"logger", "metrics"}},
+	}
+	for _, tc := range testCases {
+		calls = nil
+		PerformRequest(router, tc.method, tc.path)
+		assert.Equal(t, tc.calls, calls, tc.method+" "+tc.path)
+	}
+}
+
+func TestUseOnMatchOnlyLaterRoutes(t *testing.T) {
+	ran := false
+	router := New()
+	router.GET("/before", func(c *Context) {})
+	router.UseOnMatch(func(c *Context) { ran = true })
+	router.GET("/after", func(c *Context) {})
+
+	PerformRequest(router, http.MethodGet, "/before")
+	assert.False(t, ran)
+	PerformRequest(router, http.MethodGet, "/after")
+	assert.True(t, ran)
+}
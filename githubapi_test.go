@@ -346,6 +346,33 @@ func TestBindUriError(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w1.Code)
 }
 
+func TestBindUriHexString(t *testing.T) {
+	router := New()
+
+	type Item struct {
+		ID     int64  `uri:"id,hexstring"`
+		Parent *int64 `uri:"parent,hexstring"`
+		Seq    int64  `uri:"seq"`
+	}
+	router.GET("/items/:id/:parent/:seq", func(c *Context) {
+		var item Item
+		if c.BindUri(&item) != nil {
+			return
+		}
+		c.String(http.StatusOK, "%d %d %d", item.ID, *item.Parent, item.Seq)
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/items/00000000000003e8/ff/1000")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1000 255 1000", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/items/zz/ff/1000")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = PerformRequest(router, http.MethodGet, "/items/ff/ff/3e8")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func TestRaceContextCopy(t *testing.T) {
 	DefaultWriter = os.Stdout
 	router := Default()
@@ -0,0 +1,299 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultGzipMinLength is the size a response body must reach to be compressed by Gzip,
+// unless GzipMinLength is given.
+const DefaultGzipMinLength = 1024
+
+// defaultGzipExcludedContentTypes are content types that are already compressed.
+// An entry ending with '/' matches the whole type.
+var defaultGzipExcludedContentTypes = []string{
+	"image/png", "image/jpeg", "image/gif", "image/webp", "image/avif",
+	"video/", "audio/",
+	"font/woff", "font/woff2",
+	"application/zip", "application/gzip", "application/x-gzip", "application/zstd",
+	"application/x-7z-compressed", "application/x-rar-compressed", "application/pdf",
+}
+
+// GzipOption configures the Gzip middleware.
+type GzipOption func(*gzipConfig)
+
+type gzipConfig struct {
+	minLength        int
+	excludedTypes    []string
+	excludedPrefixes []string
+}
+
+// GzipMinLength sets the size in bytes a response body must reach to be compressed;
+// smaller ones are sent as is.
+func GzipMinLength(n int) GzipOption {
+	return func(conf *gzipConfig) {
+		conf.minLength = n
+	}
+}
+
+// GzipExcludedContentTypes adds content types that are never compressed, besides the
+// image, video, audio, font and archive types Gzip skips by default. An entry ending
+// with '/', e.g. "image/", matches the whole type.
+func GzipExcludedContentTypes(types ...string) GzipOption {
+	return func(conf *gzipConfig) {
+		conf.excludedTypes = append(conf.excludedTypes, types...)
+	}
+}
+
+// GzipExcludedPaths sets request path prefixes whose responses are never compressed.
+func GzipExcludedPaths(prefixes ...string) GzipOption {
+	return func(conf *gzipConfig) {
+		conf.excludedPrefixes = append(conf.excludedPrefixes, prefixes...)
+	}
+}
+
+// Gzip returns a middleware that compresses the responses of the following handlers with
+// the given compression level, e.g. gzip.DefaultCompression, when the request accepts gzip.
+// A response is compressed once its body reaches GzipMinLength, DefaultGzipMinLength by
+// default, unless it already has a Content-Encoding or its Content-Type is excluded, see
+// GzipExcludedContentTypes. Compressed responses get 'Content-Encoding: gzip', lose their
+// Content-Length and a strong ETag becomes weak. 'Vary: Accept-Encoding' is always added.
+// Flushing the writer decides at once, so streaming responses are compressed as they go.
+// It panics if level is not a valid gzip compression level.
+func Gzip(level int, opts ...GzipOption) HandlerFunc {
+	if _, err := gzip.NewWriterLevel(io.Discard, level); err != nil {
+		panic(err)
+	}
+	conf := gzipConfig{
+		minLength:     DefaultGzipMinLength,
+		excludedTypes: defaultGzipExcludedContentTypes,
+	}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	pool := sync.Pool{New: func() any {
+		gz, _ := gzip.NewWriterLevel(io.Discard, level)
+		return gz
+	}}
+
+	return func(c *Context) {
+		for _, prefix := range conf.excludedPrefixes {
+			if strings.HasPrefix(c.Request.URL.Path, prefix) {
+				c.Next()
+				return
+			}
+		}
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(c.requestHeader("Accept-Encoding")) || c.Writer.Written() {
+			c.Next()
+			return
+		}
+
+		w := c.Writer
+		gw := &gzipWriter{ResponseWriter: w, conf: &conf, pool: &pool, status: w.Status(), size: noWritten}
+		c.Writer = gw
+		defer func() {
+			c.Writer = w
+			gw.release()
+		}()
+		c.Next()
+		gw.close()
+	}
+}
+
+// acceptsGzip reports whether the Accept-Encoding header value allows gzip.
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.TrimSpace(coding)
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		if params = strings.TrimSpace(params); strings.HasPrefix(params, "q=") {
+			weight, err := strconv.ParseFloat(params[len("q="):], 64)
+			return err == nil && weight > 0
+		}
+		return true
+	}
+	return false
+}
+
+// gzipWriter buffers the beginning of the response until it knows whether to compress it.
+type gzipWriter struct {
+	ResponseWriter
+
+	conf   *gzipConfig
+	pool   *sync.Pool
+	gz     *gzip.Writer
+	buf    bytes.Buffer
+	status int
+	size   int
+
+	// decided is set once the response is compressed (gz != nil) or sent as is.
+	decided bool
+}
+
+var _ ResponseWriter = (*gzipWriter)(nil)
+
+func (gw *gzipWriter) WriteHeader(code int) {
+	if gw.decided && gw.gz == nil {
+		gw.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if code > 0 && gw.status != code {
+		if gw.Written() {
+			debugPrint("[WARNING] Headers were already written. Wanted to override status code %d with %d", gw.status, code)
+			return
+		}
+		gw.status = code
+	}
+}
+
+func (gw *gzipWriter) WriteHeaderNow() {
+	if gw.decided && gw.gz == nil {
+		gw.ResponseWriter.WriteHeaderNow()
+		return
+	}
+	if !gw.Written() {
+		gw.size = 0
+	}
+}
+
+func (gw *gzipWriter) Write(data []byte) (int, error) {
+	if gw.decided && gw.gz == nil {
+		return gw.ResponseWriter.Write(data)
+	}
+	gw.WriteHeaderNow()
+	gw.size += len(data)
+	if gw.gz != nil {
+		return gw.gz.Write(data)
+	}
+	gw.buf.Write(data)
+	if gw.buf.Len() >= gw.conf.minLength {
+		if err := gw.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (gw *gzipWriter) WriteString(s string) (int, error) {
+	return gw.Write([]byte(s))
+}
+
+func (gw *gzipWriter) Status() int {
+	if gw.decided && gw.gz == nil {
+		return gw.ResponseWriter.Status()
+	}
+	return gw.status
+}
+
+// Size returns the number of bytes written by the handlers, before compression.
+func (gw *gzipWriter) Size() int {
+	if gw.decided && gw.gz == nil {
+		return gw.ResponseWriter.Size()
+	}
+	return gw.size
+}
+
+func (gw *gzipWriter) Written() bool {
+	if gw.decided && gw.gz == nil {
+		return gw.ResponseWriter.Written()
+	}
+	return gw.size != noWritten
+}
+
+// Flush decides whether to compress at once and flushes what was written so far.
+func (gw *gzipWriter) Flush() {
+	if !gw.decided {
+		gw.decide(true) //nolint: errcheck
+	}
+	if gw.gz != nil {
+		gw.gz.Flush() //nolint: errcheck
+	}
+	gw.ResponseWriter.Flush()
+}
+
+// Hijack sends the response as is from now on.
+func (gw *gzipWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	gw.decided = true
+	return gw.ResponseWriter.Hijack()
+}
+
+// decide sends the headers and the buffered body, compressing them if allowed and
+// the response is eligible.
+func (gw *gzipWriter) decide(allowed bool) error {
+	gw.decided = true
+	header := gw.ResponseWriter.Header()
+	if gw.buf.Len() > 0 && header.Get("Content-Type") == "" {
+		header.Set("Content-Type", http.DetectContentType(gw.buf.Bytes()))
+	}
+	if allowed && gw.eligible(header) {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			header.Set("ETag", "W/"+etag)
+		}
+		gw.gz = gw.pool.Get().(*gzip.Writer)
+		gw.gz.Reset(gw.ResponseWriter)
+	}
+
+	gw.ResponseWriter.WriteHeader(gw.status)
+	if gw.size == noWritten {
+		return nil
+	}
+	gw.ResponseWriter.WriteHeaderNow()
+	if gw.buf.Len() == 0 {
+		return nil
+	}
+	var err error
+	if gw.gz != nil {
+		_, err = gw.gz.Write(gw.buf.Bytes())
+	} else {
+		_, err = gw.ResponseWriter.Write(gw.buf.Bytes())
+	}
+	gw.buf.Reset()
+	return err
+}
+
+func (gw *gzipWriter) eligible(header http.Header) bool {
+	if header.Get("Content-Encoding") != "" || !bodyAllowedForStatus(gw.status) {
+		return false
+	}
+	contentType := filterFlags(header.Get("Content-Type"))
+	for _, excluded := range gw.conf.excludedTypes {
+		if contentType == excluded || strings.HasSuffix(excluded, "/") && strings.HasPrefix(contentType, excluded) {
+			return false
+		}
+	}
+	return true
+}
+
+// close sends a response that stayed under the threshold as is, and ends the gzip stream.
+func (gw *gzipWriter) close() {
+	if !gw.decided {
+		gw.decide(false) //nolint: errcheck
+	}
+	if gw.gz != nil {
+		gw.gz.Close() //nolint: errcheck
+	}
+}
+
+func (gw *gzipWriter) release() {
+	if gw.gz != nil {
+		gw.gz.Reset(io.Discard)
+		gw.pool.Put(gw.gz)
+		gw.gz = nil
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func gunzip(t *testing.T, body io.Reader) string {
+	zr, err := gzip.NewReader(body)
+	if !assert.NoError(t, err) {
+		return ""
+	}
+	data, err := io.ReadAll(zr)
+	assert.NoError(t, err)
+	return string(data)
+}
+
+func TestGzipThreshold(t *testing.T) {
+	router := New()
+	router.Use(Gzip(gzip.DefaultCompression))
+	router.GET("/large", func(c *Context) {
+		c.Header("ETag", `"v1"`)
+		c.String(http.StatusOK, strings.Repeat("gin", 400))
+	})
+	router.GET("/small", func(c *Context) {
+		c.String(http.StatusOK, "gin")
+	})
+	gz := header{Key: "Accept-Encoding", Value: "deflate, gzip;q=0.8"}
+
+	w := PerformRequest(router, http.MethodGet, "/large", gz)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `W/"v1"`, w.Header().Get("ETag"))
+	assert.Less(t, w.Body.Len(), 1200)
+	assert.Equal(t, strings.Repeat("gin", 400), gunzip(t, w.Body))
+
+	w = PerformRequest(router, http.MethodGet, "/small", gz)
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	assert.Equal(t, "gin", w.Body.String())
+
+	router = New()
+	router.Use(Gzip(gzip.DefaultCompression, GzipMinLength(2)))
+	router.GET("/small", func(c *Context) {
+		c.String(http.StatusOK, "gin")
+	})
+	w = PerformRequest(router, http.MethodGet, "/small", gz)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "gin", gunzip(t, w.Body))
+}
+
+func TestGzipSkipped(t *testing.T) {
+	router := New()
+	router.Use(Gzip(gzip.DefaultCompression, GzipExcludedContentTypes("text/"), GzipExcludedPaths("/empty")))
+	router.GET("/large", func(c *Context) {
+		c.Header("ETag", `"v1"`)
+		c.String(http.StatusOK, strings.Repeat("gin", 400))
+	})
+	router.GET("/image", func(c *Context) {
+		c.Data(http.StatusOK, "image/png", []byte(strings.Repeat("x", 2048)))
+	})
+	router.GET("/encoded", func(c *Context) {
+		c.Header("Content-Encoding", "br")
+		c.Data(http.StatusOK, "text/plain", []byte(strings.Repeat("x", 2048)))
+	})
+	router.GET("/empty", func(c *Context) {
+		c.Status(http.StatusNoContent)
+	})
+	gz := header{Key: "Accept-Encoding", Value: "gzip"}
+
+	w := PerformRequest(router, http.MethodGet, "/large")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	assert.Equal(t, strings.Repeat("gin", 400), w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/large", header{Key: "Accept-Encoding", Value: "gzip;q=0"})
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+
+	w = PerformRequest(router, http.MethodGet, "/large", gz)
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, `"v1"`, w.Header().Get("ETag"))
+
+	w = PerformRequest(router, http.MethodGet, "/image", gz)
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, 2048, w.Body.Len())
+
+	w = PerformRequest(router, http.MethodGet, "/encoded", gz)
+	assert.Equal(t, "br", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, 2048, w.Body.Len())
+
+	w = PerformRequest(router, http.MethodGet, "/empty", gz)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Header().Get("Vary"))
+}
+
+func TestGzipStatusOnly(t *testing.T) {
+	router := New()
+	router.Use(Gzip(gzip.DefaultCompression))
+	router.GET("/empty", func(c *Context) {
+		c.Status(http.StatusNoContent)
+	})
+	w := PerformRequest(router, http.MethodGet, "/empty", header{Key: "Accept-Encoding", Value: "gzip"})
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Empty(t, w.Body.String())
+}
+
+func TestGzipStream(t *testing.T) {
+	router := New()
+	router.Use(Gzip(gzip.BestSpeed))
+	router.GET("/", func(c *Context) {
+		n := 0
+		c.Stream(func(w io.Writer) bool {
+			n++
+			_, err := w.Write([]byte("event"))
+			assert.NoError(t, err)
+			return n < 3
+		})
+	})
+
+	w := CreateTestResponseRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	router.ServeHTTP(w, req)
+
+	assert.True(t, w.Flushed)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "eventeventevent", gunzip(t, w.Body))
+}
+
+func TestGzipInvalidLevel(t *testing.T) {
+	assert.Panics(t, func() {
+		Gzip(42)
+	})
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	for value, accepted := range map[string]bool{
+		"":                     false,
+		"gzip":                 true,
+		"deflate, gzip":        true,
+		"gzip;q=0.5":           true,
+		"gzip; q=0":            false,
+		"gzip;q=0.000":         false,
+		"*":                    true,
+		"br, deflate":          false,
+		"x-gzip":               false,
+		"identity, gzip;q=bad": false,
+	} {
+		assert.Equal(t, accepted, acceptsGzip(value), value)
+	}
+}
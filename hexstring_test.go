@@ -0,0 +1,155 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+//go:build !jsoniter && !go_json && !(sonic && avx && (linux || windows || darwin) && amd64)
+
+package gin
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Tests that hexstring fields follow the ids parameter of the Accept header
+func TestContextNegotiateJSON(t *testing.T) {
+	obj := struct {
+		ID int64 `json:"id,hexstring"`
+	}{255}
+	tests := []struct {
+		accept string
+		body   string
+	}{
+		{"", `{"id":"00000000000000ff"}`},
+		{"application/json", `{"id":"00000000000000ff"}`},
+		{"application/json; ids=hex", `{"id":"00000000000000ff"}`},
+		{"application/json; ids=dec", `{"id":255}`},
+		{"text/html, application/json;IDS=DEC;q=0.9", `{"id":255}`},
+		{"application/json; ids=oct", `{"id":"00000000000000ff"}`},
+		{"text/plain; ids=dec", `{"id":"00000000000000ff"}`},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+		c.Request.Header.Set("Accept", tt.accept)
+
+		c.NegotiateJSON(http.StatusOK, obj)
+
+		assert.Equal(t, tt.body, w.Body.String(), tt.accept)
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	}
+}
+
+func TestContextMustBindJSON(t *testing.T) {
+	var obj struct {
+		Foo string `json:"foo" binding:"required"`
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/", bytes.NewBufferString(`{"foo":"bar"}`))
+	assert.True(t, c.MustBindJSON(&obj))
+	assert.Equal(t, "bar", obj.Foo)
+	assert.Equal(t, 0, w.Body.Len())
+
+	w = httptest.NewRecorder()
+	c, _ = CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/", bytes.NewBufferString(`{"foo":1}`))
+	assert.False(t, c.MustBindJSON(&obj))
+	assert.True(t, c.IsAborted())
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `{"error":"Foo: ReadString`)
+	assert.Equal(t, ErrorTypeBind, c.Errors.Last().Type)
+}
+
+func TestContextShouldBindBodyWithJSONHexstring(t *testing.T) {
+	type user struct {
+		Kind string `json:"kind"`
+		ID   int64  `json:"id,hexstring"`
+	}
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("POST", "/", bytes.NewBufferString(`{"kind":"user","id":"ff"}`))
+
+	var u user
+	assert.NoError(t, c.ShouldBindBodyWithJSON(&u))
+	assert.Equal(t, user{Kind: "user", ID: 0xff}, u)
+
+	// the cached body is decoded again with the same hexstring rules
+	var again user
+	assert.NoError(t, c.ShouldBindBodyWithJSON(&again))
+	assert.Equal(t, u, again)
+}
+
+func TestLoggerJSONHexstring(t *testing.T) {
+	buffer := new(strings.Builder)
+	router := New()
+	router.Use(LoggerJSON(buffer, func(c *Context, entry *JSONLogEntry) {
+		entry.Fields = map[string]any{
+			"user": struct {
+				ID int64 `json:"id,hexstring"`
+			}{255},
+		}
+	}))
+	router.GET("/example", func(c *Context) {})
+
+	PerformRequest(router, "GET", "/example")
+	assert.Contains(t, buffer.String(), `"fields":{"user":{"id":"00000000000000ff"}}`)
+}
+
+func TestJSONHandlerHexstring(t *testing.T) {
+	type userReq struct {
+		ID int64 `json:"id,hexstring" binding:"required"`
+	}
+	type userRes struct {
+		ID   int64  `json:"id,hexstring"`
+		Name string `json:"name"`
+	}
+	router := New()
+	router.POST("/users", JSON(func(c *Context, req userReq) (userRes, error) {
+		return userRes{ID: req.ID, Name: "gin"}, nil
+	}))
+
+	req, _ := http.NewRequest(http.MethodPost, "/users", bytes.NewBufferString(`{"id":"ff"}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"id":"00000000000000ff","name":"gin"}`, w.Body.String())
+}
+
+func TestContextJSONBytesHexstring(t *testing.T) {
+	obj := struct {
+		ID int64 `json:"id,hexstring"`
+	}{255}
+
+	c, _ := CreateTestContext(httptest.NewRecorder())
+	body, err := c.JSONBytes(obj)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":"00000000000000ff"}`, string(body))
+}
+
+func TestContextNegotiateJSONResponseWrapper(t *testing.T) {
+	obj := struct {
+		ID int64 `json:"id,hexstring"`
+	}{255}
+	for accept, body := range map[string]string{
+		"application/json":          `{"data":{"id":"00000000000000ff"}}`,
+		"application/json; ids=dec": `{"data":{"id":255}}`,
+	} {
+		w := httptest.NewRecorder()
+		c, router := CreateTestContext(w)
+		router.ResponseWrapper = func(c *Context, obj any) any {
+			return H{"data": obj}
+		}
+		c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+		c.Request.Header.Set("Accept", accept)
+
+		c.NegotiateJSON(http.StatusOK, obj)
+		assert.Equal(t, body, w.Body.String(), accept)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"sort"
+	"strings"
+)
+
+// hostRoutes holds the routes of the groups returned by Engine.Host for one pattern.
+type hostRoutes struct {
+	// pattern is lower case, e.g. "api.example.com" or "*.example.com".
+	pattern string
+	routeTable
+}
+
+// Host returns a group whose routes only match requests for the given host, e.g.
+// "api.example.com", or for any subdomain of a domain with a "*." prefix, e.g.
+// "*.example.com" matches "a.example.com" and "a.b.example.com" but not "example.com".
+// Patterns are compared to the Host of the request without its port, ignoring case.
+//
+// The host is resolved before the path: a request whose host matches a pattern is routed
+// among the routes of that pattern only, other requests among the routes registered
+// without Host. An exact pattern has precedence over wildcard ones, and a wildcard pattern
+// over the shorter ones it overlaps, e.g. "*.eu.example.com" over "*.example.com".
+// Calling Host again with the same pattern adds to the same routes. The group has the
+// middleware of the engine at the time of the call, like Engine.Group.
+func (engine *Engine) Host(pattern string) *RouterGroup {
+	pattern = strings.ToLower(pattern)
+	assert1(pattern != "" && pattern != "*." && !strings.Contains(strings.TrimPrefix(pattern, "*."), "*"),
+		"host pattern must be a host name, with an optional \"*.\" prefix: "+pattern)
+
+	var host *hostRoutes
+	for _, h := range engine.hosts {
+		if h.pattern == pattern {
+			host = h
+			break
+		}
+	}
+	if host == nil {
+		host = &hostRoutes{pattern: pattern}
+		engine.hosts = append(engine.hosts, host)
+		sort.SliceStable(engine.hosts, func(i, j int) bool {
+			return engine.hosts[i].precedes(engine.hosts[j])
+		})
+	}
+	group := engine.Group("/")
+	group.host = host
+	return group
+}
+
+// precedes reports whether h is tried before other: exact patterns first, then
+// wildcard ones from the longest.
+func (h *hostRoutes) precedes(other *hostRoutes) bool {
+	wildcard, otherWildcard := strings.HasPrefix(h.pattern, "*."), strings.HasPrefix(other.pattern, "*.")
+	if wildcard != otherWildcard {
+		return !wildcard
+	}
+	return wildcard && len(h.pattern) > len(other.pattern)
+}
+
+func (h *hostRoutes) match(host string) bool {
+	if strings.HasPrefix(h.pattern, "*") {
+		suffix := h.pattern[1:]
+		return len(host) > len(suffix) && strings.HasSuffix(host, suffix)
+	}
+	return host == h.pattern
+}
+
+// hostTable returns the routes to match a request for host against.
+func (engine *Engine) hostTable(host string) *routeTable {
+	host = strings.ToLower(stripHostPort(host))
+	for _, h := range engine.hosts {
+		if h.match(host) {
+			return &h.routeTable
+		}
+	}
+	return &engine.routeTable
+}
+
+// stripHostPort returns host without its port, if any.
+func stripHostPort(host string) string {
+	i := strings.LastIndexByte(host, ':')
+	if i < 0 || strings.IndexByte(host[i:], ']') >= 0 {
+		return host
+	}
+	return host[:i]
+}
@@ -0,0 +1,109 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func performHostRequest(r http.Handler, method, host, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.Host = host
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestHostRouting(t *testing.T) {
+	router := New()
+	dashboard := func(name string) HandlerFunc {
+		return func(c *Context) {
+			c.String(http.StatusOK, name)
+		}
+	}
+	router.GET("/dashboard", dashboard("default"))
+	router.Host("api.example.com").GET("/dashboard", dashboard("api"))
+	router.Host("*.example.com").GET("/dashboard", dashboard("tenant"))
+	router.Host("*.eu.example.com").GET("/dashboard", dashboard("eu tenant"))
+	admin := router.Host("Admin.Example.com").Group("/", func(c *Context) {
+		c.Header("X-Admin", "1")
+	})
+	admin.GET("/dashboard", dashboard("admin"))
+	admin.GET("/users/:id", func(c *Context) {
+		c.String(http.StatusOK, "admin user "+c.Param("id"))
+	})
+
+	for host, body := range map[string]string{
+		"api.example.com":      "api",
+		"API.example.com:8080": "api",
+		"admin.example.com":    "admin",
+		"acme.example.com":     "tenant",
+		"a.b.example.com":      "tenant",
+		"acme.eu.example.com":  "eu tenant",
+		"example.com":          "default",
+		"localhost:8080":       "default",
+		"[::1]:8080":           "default",
+	} {
+		w := performHostRequest(router, http.MethodGet, host, "/dashboard")
+		assert.Equal(t, http.StatusOK, w.Code, host)
+		assert.Equal(t, body, w.Body.String(), host)
+	}
+
+	w := performHostRequest(router, http.MethodGet, "admin.example.com", "/users/7")
+	assert.Equal(t, "admin user 7", w.Body.String())
+	assert.Equal(t, "1", w.Header().Get("X-Admin"))
+
+	// hosts only see their own routes
+	w = performHostRequest(router, http.MethodGet, "api.example.com", "/users/7")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = performHostRequest(router, http.MethodGet, "example.com", "/users/7")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestHostMethodNotAllowed(t *testing.T) {
+	router := New()
+	router.HandleMethodNotAllowed = true
+	router.GET("/dashboard", func(c *Context) {})
+	router.Host("admin.example.com").GET("/dashboard", func(c *Context) {})
+
+	w := performHostRequest(router, http.MethodPost, "admin.example.com", "/dashboard")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET", w.Header().Get("Allow"))
+}
+
+func TestHostSamePattern(t *testing.T) {
+	router := New()
+	router.Host("api.example.com").GET("/a", func(c *Context) {})
+	router.Host("API.example.com").GET("/b", func(c *Context) {})
+	assert.Len(t, router.hosts, 1)
+
+	routes := router.Routes()
+	assert.Len(t, routes, 2)
+	for _, route := range routes {
+		assert.Equal(t, "api.example.com", route.Host)
+	}
+
+	assert.Panics(t, func() { router.Host("") })
+	assert.Panics(t, func() { router.Host("*.") })
+	assert.Panics(t, func() { router.Host("api.*.com") })
+}
+
+func TestHostPrecedence(t *testing.T) {
+	router := New()
+	router.Host("*.example.com")
+	router.Host("a.example.com")
+	router.Host("*.b.example.com")
+	router.Host("c.example.com")
+
+	patterns := make([]string, len(router.hosts))
+	for i, h := range router.hosts {
+		patterns[i] = h.pattern
+	}
+	assert.Equal(t, []string{"a.example.com", "c.example.com", "*.b.example.com", "*.example.com"}, patterns)
+}
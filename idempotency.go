@@ -0,0 +1,201 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// IdempotencyKeyHeader is the request header carrying the key Idempotency replays by.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// DefaultIdempotencyTTL is how long Idempotency keeps a response, unless IdempotencyTTL is given.
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// ErrIdempotencyConflict is returned with 409 Conflict when a request runs while another one
+// with the same idempotency key is still being handled.
+var ErrIdempotencyConflict = errors.New("a request with the same idempotency key is in progress")
+
+// IdempotentResponse is a response saved by Idempotency.
+type IdempotentResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// IdempotencyStore saves the responses of Idempotency. Implementations must be safe
+// for concurrent use; a shared store, e.g. backed by Redis, makes keys hold across instances.
+type IdempotencyStore interface {
+	// Get returns the response saved under key, ok is false if there is none or it expired.
+	Get(key string) (resp *IdempotentResponse, ok bool)
+	// Set saves resp under key for ttl.
+	Set(key string, resp *IdempotentResponse, ttl time.Duration)
+}
+
+// IdempotencyOption configures the Idempotency middleware.
+type IdempotencyOption func(*idempotencyConfig)
+
+type idempotencyConfig struct {
+	ttl time.Duration
+}
+
+// IdempotencyTTL sets how long a response is replayed.
+func IdempotencyTTL(ttl time.Duration) IdempotencyOption {
+	return func(conf *idempotencyConfig) {
+		conf.ttl = ttl
+	}
+}
+
+// Idempotency returns a middleware that replays the first response to the requests sending
+// the same Idempotency-Key header. The first request runs the rest of the chain with its
+// response buffered, and a 2xx response is saved in store with the headers the chain set,
+// keyed by the method, the path and the key. Headers set before, e.g. by RequestID, are
+// left to the middleware setting them on the following requests. The following requests get the saved response with an
+// 'Idempotent-Replayed: true' header, without running the chain. A request arriving while
+// one with the same key is in progress in this process is aborted with 409 Conflict and
+// a {"error": "..."} JSON body. Requests without the header, error responses and streamed
+// responses, see BufferResponse, are not saved.
+func Idempotency(store IdempotencyStore, opts ...IdempotencyOption) HandlerFunc {
+	conf := idempotencyConfig{ttl: DefaultIdempotencyTTL}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	var (
+		mu       sync.Mutex
+		inFlight = make(map[string]struct{})
+	)
+
+	return func(c *Context) {
+		key := c.requestHeader(IdempotencyKeyHeader)
+		if key == "" || c.Writer.Written() {
+			c.Next()
+			return
+		}
+		key = c.Request.Method + " " + c.Request.URL.Path + " " + key
+
+		mu.Lock()
+		_, busy := inFlight[key]
+		if !busy {
+			inFlight[key] = struct{}{}
+		}
+		mu.Unlock()
+		if busy {
+			c.Error(ErrIdempotencyConflict) //nolint: errcheck
+			c.AbortWithStatusJSON(http.StatusConflict, H{"error": ErrIdempotencyConflict.Error()})
+			return
+		}
+		defer func() {
+			mu.Lock()
+			delete(inFlight, key)
+			mu.Unlock()
+		}()
+
+		if resp, ok := store.Get(key); ok {
+			replayResponse(c, resp)
+			return
+		}
+
+		w := c.Writer
+		before := w.Header().Clone()
+		bw := &bufferWriter{ResponseWriter: w, status: w.Status(), size: noWritten}
+		c.Writer = bw
+		defer func() {
+			c.Writer = w
+		}()
+		c.Next()
+		if bw.streaming {
+			return
+		}
+		if bw.status >= 200 && bw.status < 300 {
+			store.Set(key, &IdempotentResponse{
+				Status: bw.status,
+				Header: headerSetSince(before, w.Header()),
+				Body:   append([]byte(nil), bw.buf.Bytes()...),
+			}, conf.ttl)
+		}
+		bw.writeTo(w)
+	}
+}
+
+// headerSetSince returns the entries of header added or changed since it was before.
+func headerSetSince(before, header http.Header) http.Header {
+	set := make(http.Header, len(header))
+	for k, v := range header {
+		if old, ok := before[k]; ok && len(old) == len(v) {
+			same := true
+			for i := range v {
+				same = same && old[i] == v[i]
+			}
+			if same {
+				continue
+			}
+		}
+		set[k] = append([]string(nil), v...)
+	}
+	return set
+}
+
+func replayResponse(c *Context, resp *IdempotentResponse) {
+	header := c.Writer.Header()
+	for k, v := range resp.Header {
+		header[k] = append([]string(nil), v...)
+	}
+	header.Set("Idempotent-Replayed", "true")
+	c.Abort()
+	c.Writer.WriteHeader(resp.Status)
+	c.Writer.WriteHeaderNow()
+	c.Writer.Write(resp.Body) //nolint: errcheck
+}
+
+// MemoryIdempotencyStore is an IdempotencyStore keeping the responses in memory.
+// Expired responses are dropped when a response is saved, at most once a minute.
+type MemoryIdempotencyStore struct {
+	mu        sync.Mutex
+	entries   map[string]memoryIdempotencyEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type memoryIdempotencyEntry struct {
+	resp    *IdempotentResponse
+	expires time.Time
+}
+
+var _ IdempotencyStore = (*MemoryIdempotencyStore)(nil)
+
+// NewMemoryIdempotencyStore returns an empty MemoryIdempotencyStore.
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{entries: make(map[string]memoryIdempotencyEntry), now: time.Now}
+}
+
+// Get implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Get(key string) (*IdempotentResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[key]
+	if !ok || !s.now().Before(entry.expires) {
+		return nil, false
+	}
+	return entry.resp, true
+}
+
+// Set implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Set(key string, resp *IdempotentResponse, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if now.Sub(s.lastSweep) >= time.Minute {
+		for k, entry := range s.entries {
+			if !now.Before(entry.expires) {
+				delete(s.entries, k)
+			}
+		}
+		s.lastSweep = now
+	}
+	s.entries[key] = memoryIdempotencyEntry{resp: resp, expires: now.Add(ttl)}
+}
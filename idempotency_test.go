@@ -0,0 +1,135 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyReplay(t *testing.T) {
+	calls := 0
+	router := New()
+	router.Use(Idempotency(NewMemoryIdempotencyStore()))
+	router.POST("/orders", func(c *Context) {
+		calls++
+		c.Header("Location", "/orders/"+strconv.Itoa(calls))
+		c.JSON(http.StatusCreated, H{"id": calls})
+	})
+	router.POST("/fail", func(c *Context) {
+		calls++
+		c.String(http.StatusInternalServerError, "try again")
+	})
+	key := header{Key: IdempotencyKeyHeader, Value: "k1"}
+
+	w := PerformRequest(router, http.MethodPost, "/orders", key)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `{"id":1}`, w.Body.String())
+	assert.Empty(t, w.Header().Get("Idempotent-Replayed"))
+
+	w = PerformRequest(router, http.MethodPost, "/orders", key)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `{"id":1}`, w.Body.String())
+	assert.Equal(t, "/orders/1", w.Header().Get("Location"))
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, 1, calls)
+
+	// another key, no key or another path run the handler
+	w = PerformRequest(router, http.MethodPost, "/orders", header{Key: IdempotencyKeyHeader, Value: "k2"})
+	assert.Equal(t, `{"id":2}`, w.Body.String())
+	w = PerformRequest(router, http.MethodPost, "/orders")
+	assert.Equal(t, `{"id":3}`, w.Body.String())
+
+	// error responses are not saved
+	w = PerformRequest(router, http.MethodPost, "/fail", key)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	PerformRequest(router, http.MethodPost, "/fail", key)
+	assert.Equal(t, 5, calls)
+}
+
+func TestIdempotencyConflict(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	router := New()
+	router.Use(Idempotency(NewMemoryIdempotencyStore()))
+	router.POST("/", func(c *Context) {
+		close(started)
+		<-release
+		c.String(http.StatusOK, "done")
+	})
+	key := header{Key: IdempotencyKeyHeader, Value: "k"}
+
+	done := make(chan string)
+	go func() {
+		done <- PerformRequest(router, http.MethodPost, "/", key).Body.String()
+	}()
+	<-started
+
+	w := PerformRequest(router, http.MethodPost, "/", key)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, `{"error":"a request with the same idempotency key is in progress"}`, w.Body.String())
+
+	close(release)
+	assert.Equal(t, "done", <-done)
+
+	w = PerformRequest(router, http.MethodPost, "/", key)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "done", w.Body.String())
+	assert.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+}
+
+func TestIdempotencyReplayRequestHeaders(t *testing.T) {
+	ids := []string{"req-1", "req-2"}
+	router := New()
+	router.Use(RequestID(RequestIDGenerator(func() string {
+		id := ids[0]
+		ids = ids[1:]
+		return id
+	})), Idempotency(NewMemoryIdempotencyStore()))
+	router.POST("/orders", func(c *Context) {
+		c.Header("X-Order", "1")
+		c.String(http.StatusCreated, "created")
+	})
+	key := header{Key: IdempotencyKeyHeader, Value: "k1"}
+
+	w := PerformRequest(router, http.MethodPost, "/orders", key)
+	assert.Equal(t, "req-1", w.Header().Get(RequestIDHeader))
+
+	// the replay keeps the id of its own request
+	w = PerformRequest(router, http.MethodPost, "/orders", key)
+	assert.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, "req-2", w.Header().Get(RequestIDHeader))
+	assert.Equal(t, []string{"req-2"}, w.Header().Values(RequestIDHeader))
+	assert.Equal(t, "1", w.Header().Get("X-Order"))
+}
+
+func TestMemoryIdempotencyStoreTTL(t *testing.T) {
+	now := time.Now()
+	store := NewMemoryIdempotencyStore()
+	store.now = func() time.Time { return now }
+	resp := &IdempotentResponse{Status: http.StatusOK, Body: []byte("ok")}
+
+	store.Set("a", resp, time.Hour)
+	store.Set("b", resp, time.Second)
+	got, ok := store.Get("a")
+	assert.True(t, ok)
+	assert.Same(t, resp, got)
+
+	now = now.Add(2 * time.Second)
+	_, ok = store.Get("b")
+	assert.False(t, ok)
+
+	// expired responses are dropped at most once a minute
+	store.Set("c", resp, time.Hour)
+	assert.Len(t, store.entries, 3)
+	now = now.Add(time.Minute)
+	store.Set("d", resp, time.Hour)
+	assert.Len(t, store.entries, 3)
+	assert.NotContains(t, store.entries, "b")
+}
@@ -6,7 +6,13 @@
 
 package json
 
-import json "github.com/goccy/go-json"
+import (
+	"bytes"
+	"io"
+	"sync/atomic"
+
+	json "github.com/goccy/go-json"
+)
 
 var (
 	// Marshal is exported by gin/json package.
@@ -19,4 +25,32 @@ var (
 	NewDecoder = json.NewDecoder
 	// NewEncoder is exported by gin/json package.
 	NewEncoder = json.NewEncoder
+	// MarshalDecimal is the same as Marshal, hexstring tags only apply to the default build.
+	MarshalDecimal = json.Marshal
+	// Valid is exported by gin/json package.
+	Valid = json.Valid
+	// Compact is exported by gin/json package.
+	Compact = json.Compact
 )
+
+// SetHexDecodeMode sets the mode CurrentHexDecodeMode returns, hexstring tags only apply to the default build.
+func SetHexDecodeMode(mode HexDecodeMode) {
+	atomic.StoreInt32(&hexDecodeMode, int32(mode))
+}
+
+// Decode reads the next JSON value from r into v, honoring the decode options.
+func Decode(r io.Reader, v any, opts DecodeOptions) error {
+	decoder := NewDecoder(r)
+	if opts.UseNumber {
+		decoder.UseNumber()
+	}
+	if opts.DisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	return decoder.Decode(v)
+}
+
+// DecodeBytes is the same as Decode, reading from data.
+func DecodeBytes(data []byte, v any, opts DecodeOptions) error {
+	return Decode(bytes.NewReader(data), v, opts)
+}
@@ -0,0 +1,163 @@
+// Copyright 2017 Bo-Yi Wu. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package json
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+func containsAF(s string) bool {
+	for _, char := range s {
+		if char >= 'a' && char <= 'f' {
+			return true
+		}
+	}
+	return false
+}
+
+// HexDecodeMode 十六进制字符串的解码方式
+type HexDecodeMode int
+
+const (
+	// HexDecodeAuto 兼容旧逻辑：0x前缀、不超过16位或含a-f按16进制，否则按10进制
+	HexDecodeAuto HexDecodeMode = iota
+	// HexDecodePrefix 只有0x前缀按16进制，否则一律按10进制；hexstring 字段编码时带0x前缀
+	HexDecodePrefix
+)
+
+// hexDecodeMode 包级的解码方式，见 SetHexDecodeMode
+var hexDecodeMode int32
+
+// CurrentHexDecodeMode 返回 SetHexDecodeMode 设置的解码方式，默认为 HexDecodeAuto
+func CurrentHexDecodeMode() HexDecodeMode {
+	return HexDecodeMode(atomic.LoadInt32(&hexDecodeMode))
+}
+
+// hexStringBase 按解码方式确定进制，返回去掉0x前缀后的数字部分；
+// 负号在判断进制前剥离，返回时重新加上
+func hexStringBase(str string, mode HexDecodeMode) (string, int) {
+	sign := ""
+	if len(str) > 0 && str[0] == '-' {
+		sign, str = "-", str[1:]
+	}
+	if len(str) > 2 && str[0] == '0' && (str[1] == 'x' || str[1] == 'X') {
+		return sign + str[2:], 16
+	}
+	if mode == HexDecodePrefix || (len(str) > maxHexWidth && !containsAF(str)) {
+		return sign + str, 10
+	}
+	return sign + str, 16
+}
+
+// ParseHexString 按解码方式把字符串解析为 int64
+func ParseHexString(str string, mode HexDecodeMode) (int64, error) {
+	digits, base := hexStringBase(str, mode)
+	return strconv.ParseInt(digits, base, 64)
+}
+
+// ParseHexUint 按解码方式把字符串解析为 uint64
+func ParseHexUint(str string, mode HexDecodeMode) (uint64, error) {
+	digits, base := hexStringBase(str, mode)
+	return strconv.ParseUint(digits, base, 64)
+}
+
+// maxHexWidth int64 十六进制表示的最大位数
+const maxHexWidth = 16
+
+// appendHex 把 value 按最小位数以十六进制追加到 dst，输出与 fmt.Sprintf("%0*x", width, value) 一致：
+// 负数为 "-" 加绝对值(负号计入位数)，如 -255 为 "-ff"
+func appendHex(dst []byte, value int64, width int) []byte {
+	if value < 0 {
+		dst = append(dst, '-')
+		// math.MinInt64 取反溢出后转 uint64 仍是正确的绝对值
+		return appendHexUint(dst, uint64(-value), width-1)
+	}
+	return appendHexUint(dst, uint64(value), width)
+}
+
+// appendHexUint 同 appendHex，用于 uint64
+func appendHexUint(dst []byte, value uint64, width int) []byte {
+	digits := (bits.Len64(value) + 3) / 4
+	if digits == 0 {
+		digits = 1
+	}
+	for ; digits < width; digits++ {
+		dst = append(dst, '0')
+	}
+	return strconv.AppendUint(dst, value, 16)
+}
+
+// appendHexMode 同 appendHex，mode 为 HexDecodePrefix 时数字前加0x，如 -255 为 "-0xff"，
+// 使输出能按同一方式解码
+func appendHexMode(dst []byte, value int64, width int, mode HexDecodeMode) []byte {
+	if mode != HexDecodePrefix {
+		return appendHex(dst, value, width)
+	}
+	if value < 0 {
+		return appendHexUint(append(dst, '-', '0', 'x'), uint64(-value), width-1)
+	}
+	return appendHexUint(append(dst, '0', 'x'), uint64(value), width)
+}
+
+// appendHexUintMode 同 appendHexMode，用于 uint64
+func appendHexUintMode(dst []byte, value uint64, width int, mode HexDecodeMode) []byte {
+	if mode == HexDecodePrefix {
+		dst = append(dst, '0', 'x')
+	}
+	return appendHexUint(dst, value, width)
+}
+
+// formatHex 按最小位数格式化十六进制字符串，见 appendHex
+func formatHex(value int64, width int) string {
+	return string(appendHex(make([]byte, 0, maxHexWidth+1), value, width))
+}
+
+// HexDecodeError 描述一个无法解码的 hexstring 字段
+type HexDecodeError struct {
+	// Field 字段的 json 名称，数组元素为 name[i]
+	Field string
+	// Raw 原始字符串
+	Raw string
+	// Err 解析错误
+	Err error
+}
+
+func (e *HexDecodeError) Error() string {
+	return fmt.Sprintf("json: cannot decode hexstring field %q from %q: %v", e.Field, e.Raw, e.Err)
+}
+
+func (e *HexDecodeError) Unwrap() error {
+	return e.Err
+}
+
+// HexDecodeErrors 一次解码中所有失败的 hexstring 字段
+type HexDecodeErrors []*HexDecodeError
+
+func (errs HexDecodeErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Fields 返回所有失败字段的名称
+func (errs HexDecodeErrors) Fields() []string {
+	fields := make([]string, len(errs))
+	for i, err := range errs {
+		fields[i] = err.Field
+	}
+	return fields
+}
+
+// DecodeOptions 对应 Decoder 的 UseNumber、DisallowUnknownFields
+type DecodeOptions struct {
+	UseNumber             bool
+	DisallowUnknownFields bool
+}
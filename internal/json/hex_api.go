@@ -0,0 +1,87 @@
+// Copyright 2017 Bo-Yi Wu. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package json
+
+import (
+	"encoding"
+	stdjson "encoding/json"
+	"reflect"
+	"unsafe"
+
+	"github.com/gin-gonic/gin/internal/bytesconv"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+)
+
+// writeHex 借助栈上缓冲写入十六进制字符串，避免 fmt.Sprintf 的分配，前缀见 appendHexMode
+func writeHex(stream *jsoniter.Stream, value int64, width int, mode HexDecodeMode) {
+	var buf [2 * maxHexWidth]byte
+	stream.WriteString(bytesconv.BytesToString(appendHexMode(buf[:0], value, width, mode)))
+}
+
+// writeHexUint 同 writeHex，用于 uint64
+func writeHexUint(stream *jsoniter.Stream, value uint64, width int, mode HexDecodeMode) {
+	var buf [2 * maxHexWidth]byte
+	stream.WriteString(bytesconv.BytesToString(appendHexUintMode(buf[:0], value, width, mode)))
+}
+
+// hexAllInt64Encoder 不看 tag，把 int64 固定编码为16位十六进制字符串
+type hexAllInt64Encoder struct{}
+
+func (e *hexAllInt64Encoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	writeHex(stream, *(*int64)(ptr), maxHexWidth, HexDecodeAuto)
+}
+
+func (e *hexAllInt64Encoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return *(*int64)(ptr) == 0
+}
+
+// hexAllExtension 把所有 int64(包括切片元素、map值)替换为 hexAllInt64Encoder，
+// 用于无法加 tag 的第三方结构体
+type hexAllExtension struct {
+	jsoniter.DummyExtension
+}
+
+var (
+	hexAllInt64Type     = reflect.TypeOf(int64(0))
+	hexAllMarshalerType = reflect.TypeOf((*stdjson.Marshaler)(nil)).Elem()
+	hexAllTextType      = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// int64Encoder 按数字输出，用于 time.Duration 等自定义 int64 类型
+type int64Encoder struct{}
+
+func (e *int64Encoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	stream.WriteInt64(*(*int64)(ptr))
+}
+
+func (e *int64Encoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return *(*int64)(ptr) == 0
+}
+
+func (extension *hexAllExtension) DecorateEncoder(typ reflect2.Type, encoder jsoniter.ValEncoder) jsoniter.ValEncoder {
+	if typ.Kind() != reflect.Int64 {
+		return encoder
+	}
+	if typ.Type1() == hexAllInt64Type {
+		return &hexAllInt64Encoder{}
+	}
+	// 自定义 int64 类型保持数字，实现了 Marshaler 的沿用原编码器
+	if typ.Type1().Implements(hexAllMarshalerType) || typ.Type1().Implements(hexAllTextType) {
+		return encoder
+	}
+	return &int64Encoder{}
+}
+
+var hexAllInstance = func() jsoniter.API {
+	api := jsoniter.Config{}.Froze()
+	api.RegisterExtension(&hexAllExtension{})
+	return api
+}()
+
+// MarshalHex 与 Marshal 相同，但所有 int64 都编码为16位十六进制字符串
+func MarshalHex(v any) ([]byte, error) {
+	return hexAllInstance.Marshal(v)
+}
@@ -0,0 +1,43 @@
+// Copyright 2017 Bo-Yi Wu. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package json
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var hexTestValues = []int64{0, 1, -1, 15, 16, 255, -255, 0x123456789abcdef, math.MaxInt64, math.MinInt64}
+
+func TestFormatHexMatchesSprintf(t *testing.T) {
+	for _, v := range hexTestValues {
+		for _, width := range []int{0, 1, 8, 16, 17} {
+			assert.Equal(t, fmt.Sprintf("%0*x", width, v), formatHex(v, width), "%d width %d", v, width)
+			assert.Equal(t, fmt.Sprintf("%0*x", width, uint64(v)), string(appendHexUint(nil, uint64(v), width)), "%d width %d", uint64(v), width)
+		}
+	}
+}
+
+func BenchmarkFormatHexSprintf(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, v := range hexTestValues {
+			_ = fmt.Sprintf("%0*x", 16, v)
+		}
+	}
+}
+
+func BenchmarkFormatHexAppend(b *testing.B) {
+	var buf [2 * maxHexWidth]byte
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, v := range hexTestValues {
+			_ = appendHex(buf[:0], v, 16)
+		}
+	}
+}
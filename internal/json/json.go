@@ -7,40 +7,57 @@
 package json
 
 import (
+	"bytes"
+	stdjson "encoding/json"
 	"fmt"
+	"io"
+	"math/big"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 	"unsafe"
 
 	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
 )
 
-func containsAF(s string) bool {
-	for _, char := range s {
-		if char >= 'a' && char <= 'f' {
-			return true
+// HexStringEncoder 自定义编码器将 int64 类型编码为十六进制字符串或者把16进制转为int64
+type HexStringEncoder struct {
+	// Width 输出的最小位数，不足时左侧补0；为0时不补位，tag 未指定时为16
+	Width int
+	// Mode 解码方式
+	Mode HexDecodeMode
+	// Field 字段的 json 名称，用于错误信息
+	Field string
+}
+
+// hexStringWidth 解析 tag 中的 hexstring=N，未指定或非法值回退为默认的16位
+func hexStringWidth(tag string) int {
+	opts := strings.Split(tag, ",")
+	for _, opt := range opts[1:] {
+		if !strings.HasPrefix(opt, "hexstring=") {
+			continue
+		}
+		width, err := strconv.Atoi(strings.TrimPrefix(opt, "hexstring="))
+		if err != nil || width < 0 || width > maxHexWidth {
+			return maxHexWidth
 		}
+		return width
 	}
-	return false
+	return maxHexWidth
 }
 
-// HexStringEncoder 自定义编码器将 int64 类型编码为十六进制字符串或者把16进制转为int64
-type HexStringEncoder struct{}
-
 // Encode 实现 jsoniter.ValEncoder 接口
 func (e *HexStringEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
 	if ptr == nil {
 		stream.WriteNil()
 		return
 	}
-	// Convert int64 value to a 16-byte hexadecimal string
-	value := *(*int64)(ptr)
-	if value == 0 {
-		stream.WriteString("0") //0值特殊处理
-	} else {
-		stream.WriteString(fmt.Sprintf("%x", value))
-	}
+	// Convert int64 value to a hexadecimal string, padded to Width
+	writeHex(stream, *(*int64)(ptr), e.Width, e.Mode)
 }
 
 func (e *HexStringEncoder) IsEmpty(ptr unsafe.Pointer) bool {
@@ -51,69 +68,119 @@ func (codec *HexStringEncoder) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterato
 	valueType := iter.WhatIsNext()
 	if valueType == jsoniter.StringValue {
 		str := iter.ReadString()
-		var i int64
-		var err error
-		if len(str) < 17 || containsAF(str) {
-			i, err = strconv.ParseInt(str, 16, 64)
-			if err != nil {
-				i = 0
-			}
-		} else {
-			i, err = strconv.ParseInt(str, 10, 64)
-			if err != nil {
-				i = 0
-			}
+		if str == "" {
+			*((*int64)(ptr)) = 0 //空字符串视为0
+			return
+		}
+		i, err := ParseHexString(str, codec.Mode)
+		if err != nil {
+			reportHexError(iter, "HexStringEncoder.Decode", codec.Field, str, err)
+			return
 		}
-
 		*((*int64)(ptr)) = i
 	} else if valueType == jsoniter.NumberValue {
 		*((*int64)(ptr)) = iter.ReadInt64()
 	} else {
+		iter.Skip()
 		*((*int64)(ptr)) = 0
 	}
 }
 
-// EmptyObjectEncoder 实现一个编码器，当字段值为nil时，写入空对象{}
-type EmptyObjectEncoder struct {
-	encoder jsoniter.ValEncoder
+// HexUint64Encoder 将 uint64 编码为十六进制字符串，支持最高位为1的ID
+type HexUint64Encoder struct {
+	// Width 输出的最小位数，不足时左侧补0；为0时不补位，tag 未指定时为16
+	Width int
+	// Mode 解码方式
+	Mode HexDecodeMode
+	// Field 字段的 json 名称，用于错误信息
+	Field string
 }
 
-func (encoder *EmptyObjectEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
-	// If the pointer points to nil, write an empty object.
-	if *(*uintptr)(ptr) == 0 {
-		stream.WriteRaw("{}")
+func (e *HexUint64Encoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	if ptr == nil {
+		stream.WriteNil()
 		return
 	}
-	// Fallback to default encoding.
-	encoder.encoder.Encode(ptr, stream)
+	writeHexUint(stream, *(*uint64)(ptr), e.Width, e.Mode)
 }
 
-func (encoder *EmptyObjectEncoder) IsEmpty(ptr unsafe.Pointer) bool {
-	return encoder.encoder.IsEmpty(ptr)
+func (e *HexUint64Encoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return ptr == nil || *(*uint64)(ptr) == 0
 }
 
-type EmptyArrayEncoder struct {
+func (codec *HexUint64Encoder) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	valueType := iter.WhatIsNext()
+	if valueType == jsoniter.StringValue {
+		str := iter.ReadString()
+		if str == "" {
+			*((*uint64)(ptr)) = 0 //空字符串视为0
+			return
+		}
+		i, err := ParseHexUint(str, codec.Mode)
+		if err != nil {
+			reportHexError(iter, "HexUint64Encoder.Decode", codec.Field, str, err)
+			return
+		}
+		*((*uint64)(ptr)) = i
+	} else if valueType == jsoniter.NumberValue {
+		*((*uint64)(ptr)) = iter.ReadUint64()
+	} else {
+		iter.Skip()
+		*((*uint64)(ptr)) = 0
+	}
+}
+
+// reportHexError 记录 hexstring 解码错误。iter.Attachment 为 *HexDecodeErrors 时汇总并返回 true，
+// 调用方可以继续解析；否则通过 ReportError 中断解析并返回 false
+func reportHexError(iter *jsoniter.Iterator, operation, field, raw string, err error) bool {
+	if errs, ok := iter.Attachment.(*HexDecodeErrors); ok {
+		*errs = append(*errs, &HexDecodeError{Field: field, Raw: raw, Err: err})
+		return true
+	}
+	iter.ReportError(operation, err.Error())
+	return false
+}
+
+// EmptyRawEncoder 实现一个编码器，当指针、接口、map或切片字段为nil时，写入配置的 JSON 字面量，
+// 如 emptyobject 写入{}，emptyarray 写入[]，emptyraw=<json> 写入任意字面量
+type EmptyRawEncoder struct {
 	encoder jsoniter.ValEncoder
+	raw     string
 }
 
-func (encoder *EmptyArrayEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
-	// If the pointer points to nil, write an empty object.
+func (encoder *EmptyRawEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	// If the pointer points to nil, write the raw literal.
 	if *(*uintptr)(ptr) == 0 {
-		stream.WriteRaw("[]")
+		stream.WriteRaw(encoder.raw)
 		return
 	}
 	// Fallback to default encoding.
 	encoder.encoder.Encode(ptr, stream)
 }
 
-func (encoder *EmptyArrayEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+func (encoder *EmptyRawEncoder) IsEmpty(ptr unsafe.Pointer) bool {
 	return encoder.encoder.IsEmpty(ptr)
 }
 
+// emptyRawLiteral 解析 tag 中的 emptyraw=<json>，字面量不能包含逗号；不是合法 JSON 时忽略
+func emptyRawLiteral(tag string) (string, bool) {
+	opts := strings.Split(tag, ",")
+	for _, opt := range opts[1:] {
+		if !strings.HasPrefix(opt, "emptyraw=") {
+			continue
+		}
+		raw := strings.TrimPrefix(opt, "emptyraw=")
+		return raw, stdjson.Valid([]byte(raw))
+	}
+	return "", false
+}
+
 // 空数组64位数组
 type EmptyArrayInt64Encoder struct {
 	encoder jsoniter.ValEncoder
 	decoder jsoniter.ValDecoder
+	mode    HexDecodeMode
+	field   string
 }
 
 func (encoder *EmptyArrayInt64Encoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
@@ -123,49 +190,41 @@ func (encoder *EmptyArrayInt64Encoder) Encode(ptr unsafe.Pointer, stream *jsonit
 		return
 	}
 
-	//循环数组
-	slice := (*[]int64)(ptr)
-	strSlice := make([]string, len(*slice))
-	for i, v := range *slice {
-		if v == 0 {
-			strSlice[i] = "0"
-		} else {
-			strSlice[i] = fmt.Sprintf("%x", v)
+	//循环数组，直接写入 stream 避免中间分配
+	stream.WriteArrayStart()
+	for i, v := range *(*[]int64)(ptr) {
+		if i > 0 {
+			stream.WriteMore()
 		}
+		writeHex(stream, v, 0, encoder.mode)
 	}
-
-	jsonData, err := jsonInstance.Marshal(strSlice)
-	if err != nil {
-		encoder.encoder.Encode(ptr, stream)
-		return
-	}
-	stream.WriteRaw(string(jsonData))
+	stream.WriteArrayEnd()
 }
 
 func (codec *EmptyArrayInt64Encoder) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
 	//str := iter.ReadString()
 	valueList := []int64{}
-	for iter.ReadArray() {
-		val := iter.Read()
-		if iter.Error != nil {
-			break
-		}
-
-		if str, ok := val.(string); ok {
-			//含有a-f或者正好16位使用16进制解析
-			if len(str) < 17 || containsAF(str) {
-				intVal, err := strconv.ParseInt(str, 16, 64)
-				if err != nil {
-					continue
-				}
-				valueList = append(valueList, intVal)
-			} else {
-				intVal, err := strconv.ParseInt(str, 10, 64)
-				if err != nil {
-					continue
+	for i := 0; iter.ReadArray(); i++ {
+		switch iter.WhatIsNext() {
+		case jsoniter.StringValue:
+			str := iter.ReadString()
+			intVal, err := ParseHexString(str, codec.mode)
+			if err != nil {
+				if !reportHexError(iter, "EmptyArrayInt64Encoder.Decode", fmt.Sprintf("%s[%d]", codec.field, i), str, err) {
+					return
 				}
-				valueList = append(valueList, intVal)
+				continue
 			}
+			valueList = append(valueList, intVal)
+		case jsoniter.NumberValue:
+			valueList = append(valueList, iter.ReadInt64())
+		default:
+			// 其他类型不能静默丢弃，否则元素会错位
+			iter.ReportError("EmptyArrayInt64Encoder.Decode", fmt.Sprintf("%s[%d]: expect hex string or number", codec.field, i))
+			return
+		}
+		if iter.Error != nil {
+			return
 		}
 	}
 
@@ -179,6 +238,112 @@ func (encoder *EmptyArrayInt64Encoder) IsEmpty(ptr unsafe.Pointer) bool {
 	return encoder.encoder.IsEmpty(ptr)
 }
 
+// UnixMilliEncoder 将 time.Time 编码为毫秒时间戳，零值编码为 0；
+// Hex 为 true 时按 hexstring 输出十六进制字符串
+type UnixMilliEncoder struct {
+	Hex bool
+	// Width 十六进制输出的最小位数
+	Width int
+	// Mode 十六进制解码方式
+	Mode HexDecodeMode
+	// Field 字段的 json 名称，用于错误信息
+	Field string
+}
+
+func (e *UnixMilliEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	t := *(*time.Time)(ptr)
+	var ms int64
+	if !t.IsZero() {
+		ms = t.UnixMilli()
+	}
+	if e.Hex {
+		writeHex(stream, ms, e.Width, e.Mode)
+	} else {
+		stream.WriteInt64(ms)
+	}
+}
+
+func (e *UnixMilliEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return (*(*time.Time)(ptr)).IsZero()
+}
+
+func (codec *UnixMilliEncoder) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	var ms int64
+	switch iter.WhatIsNext() {
+	case jsoniter.NumberValue:
+		ms = iter.ReadInt64()
+	case jsoniter.StringValue:
+		str := iter.ReadString()
+		if str == "" {
+			break
+		}
+		var err error
+		if codec.Hex {
+			ms, err = ParseHexString(str, codec.Mode)
+			if err != nil {
+				reportHexError(iter, "UnixMilliEncoder.Decode", codec.Field, str, err)
+				return
+			}
+		} else if ms, err = strconv.ParseInt(str, 10, 64); err != nil {
+			iter.ReportError("UnixMilliEncoder.Decode", "invalid unix millis "+strconv.Quote(str))
+			return
+		}
+	default:
+		iter.Skip()
+	}
+	if ms == 0 {
+		*((*time.Time)(ptr)) = time.Time{} //0 视为零值
+		return
+	}
+	*((*time.Time)(ptr)) = time.UnixMilli(ms)
+}
+
+// BigStringEncoder 将 *big.Int 编码为十进制字符串，避免 JS 客户端丢失精度；
+// 解码兼容字符串和数字，null 或空字符串解码为 nil
+type BigStringEncoder struct {
+	// NilAsZero 为 true 时 nil 编码为 "0"，否则为 null，对应 tag bigstring=zero
+	NilAsZero bool
+}
+
+func (e *BigStringEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	b := *(**big.Int)(ptr)
+	if b == nil {
+		if e.NilAsZero {
+			stream.WriteString("0")
+		} else {
+			stream.WriteNil()
+		}
+		return
+	}
+	stream.WriteString(b.String())
+}
+
+func (e *BigStringEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return *(**big.Int)(ptr) == nil
+}
+
+func (codec *BigStringEncoder) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
+	var str string
+	switch iter.WhatIsNext() {
+	case jsoniter.StringValue:
+		str = iter.ReadString()
+	case jsoniter.NumberValue:
+		str = string(iter.ReadNumber())
+	default:
+		iter.Skip()
+	}
+	if str == "" {
+		*(**big.Int)(ptr) = nil
+		return
+	}
+	b, ok := new(big.Int).SetString(str, 10)
+	if !ok {
+		iter.ReportError("BigStringEncoder.Decode", "invalid big integer "+strconv.Quote(str))
+		return
+	}
+	*(**big.Int)(ptr) = b
+}
+
 type ToStringEncoder struct{}
 
 func (codec *ToStringEncoder) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
@@ -235,35 +400,156 @@ func (codec *ToBoolEncoder) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator)
 	}
 }
 
+// OmitValueEncoder 用于带 omitvalue=N 的 int64 字段，值等于 N 时省略该字段；
+// 同时带 omitempty 时零值也省略
+type OmitValueEncoder struct {
+	encoder   jsoniter.ValEncoder
+	value     int64
+	omitEmpty bool
+}
+
+func (e *OmitValueEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	e.encoder.Encode(ptr, stream)
+}
+
+func (e *OmitValueEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return *(*int64)(ptr) == e.value || e.omitEmpty && e.encoder.IsEmpty(ptr)
+}
+
+// omitValue 解析 tag 中的 omitvalue=N，N 为十进制 int64；非法值忽略
+func omitValue(tag string) (int64, bool) {
+	opts := strings.Split(tag, ",")
+	for _, opt := range opts[1:] {
+		if !strings.HasPrefix(opt, "omitvalue=") {
+			continue
+		}
+		value, err := strconv.ParseInt(strings.TrimPrefix(opt, "omitvalue="), 10, 64)
+		return value, err == nil
+	}
+	return 0, false
+}
+
+// omitEmptyField 让 jsoniter 按 omitempty 处理字段：jsoniter 只在 tag 带 omitempty 时
+// 调用 IsEmpty，所以 Tag 返回追加了 omitempty 的 tag，其余方法使用原字段
+type omitEmptyField struct {
+	reflect2.StructField
+	tag reflect.StructTag
+}
+
+func (f *omitEmptyField) Tag() reflect.StructTag {
+	return f.tag
+}
+
+// withOmitEmpty 返回 json tag 为 tag 加上 omitempty 的 field，其他 tag 保持不变
+func withOmitEmpty(field reflect2.StructField, tag string) reflect2.StructField {
+	old := `json:"` + tag + `"`
+	return &omitEmptyField{field, reflect.StructTag(strings.Replace(string(field.Tag()), old, old[:len(old)-1]+`,omitempty"`, 1))}
+}
+
+// hasTagOption 报告 tag 名称之后的选项中是否有 option
+func hasTagOption(tag, option string) bool {
+	opts := strings.Split(tag, ",")
+	for _, opt := range opts[1:] {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+var int64Type = reflect.TypeOf(int64(0))
+
+var timeType = reflect.TypeOf(time.Time{})
+
+var bigIntPtrType = reflect.TypeOf((*big.Int)(nil))
+
+// isInt64Slice 字段是否可以按 *[]int64 处理：必须是切片且元素正好为 int64，
+// 数组、[]MyInt 等类型内存布局或语义不同，使用默认编码器
+func isInt64Slice(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Slice && typ.Elem() == int64Type
+}
+
 // HexStringExtension 检查 struct 字段tags，为相应的 int64 字段应用 HexStringEncoder
 type ApipostExtension struct {
 	jsoniter.DummyExtension
+	// HexDecodeMode hexstring 字段及 int64 数组的解码方式
+	HexDecodeMode HexDecodeMode
+	// DecimalInt64 为 true 时 hexstring 字段按数字输出，解码仍兼容十六进制字符串
+	DecimalInt64 bool
 }
 
 // UpdateStructDescriptor 修改 struct 字段的编码/解码器
 func (extension *ApipostExtension) UpdateStructDescriptor(structDescriptor *jsoniter.StructDescriptor) {
 	for _, binding := range structDescriptor.Fields {
+		fieldName := binding.Field.Name()
+		if len(binding.FromNames) > 0 {
+			fieldName = binding.FromNames[0]
+		}
 		// 检查字段类型和 tag
 		if binding.Field.Type().Kind() == reflect.Int64 {
 			//处理64位转换
-			if strings.Contains(binding.Field.Tag().Get("json"), "hexstring") {
-				binding.Encoder = &HexStringEncoder{}
-				binding.Decoder = &HexStringEncoder{}
+			tagStr := binding.Field.Tag().Get("json")
+			if strings.Contains(tagStr, "hexstring") {
+				hexEncoder := &HexStringEncoder{Width: hexStringWidth(tagStr), Mode: extension.HexDecodeMode, Field: fieldName}
+				if !extension.DecimalInt64 {
+					binding.Encoder = hexEncoder
+				}
+				binding.Decoder = hexEncoder
+			}
+			if value, ok := omitValue(tagStr); ok {
+				omitEmpty := hasTagOption(tagStr, "omitempty")
+				binding.Encoder = &OmitValueEncoder{binding.Encoder, value, omitEmpty}
+				if !omitEmpty {
+					binding.Field = withOmitEmpty(binding.Field, tagStr)
+				}
 			}
-		} else if binding.Field.Type().Kind() == reflect.Ptr || binding.Field.Type().Kind() == reflect.Interface {
+		} else if binding.Field.Type().Kind() == reflect.Uint64 {
+			tagStr := binding.Field.Tag().Get("json")
+			if strings.Contains(tagStr, "hexstring") {
+				hexEncoder := &HexUint64Encoder{Width: hexStringWidth(tagStr), Mode: extension.HexDecodeMode, Field: fieldName}
+				if !extension.DecimalInt64 {
+					binding.Encoder = hexEncoder
+				}
+				binding.Decoder = hexEncoder
+			}
+		} else if binding.Field.Type().Kind() == reflect.Ptr || binding.Field.Type().Kind() == reflect.Interface ||
+			binding.Field.Type().Kind() == reflect.Map {
 			//处理空对象
-			if strings.Contains(binding.Field.Tag().Get("json"), "emptyobject") {
-				binding.Encoder = &EmptyObjectEncoder{binding.Encoder}
+			tagStr := binding.Field.Tag().Get("json")
+			if binding.Field.Type().Type1() == bigIntPtrType && strings.Contains(tagStr, "bigstring") {
+				//处理大整数
+				bigEncoder := &BigStringEncoder{NilAsZero: hasTagOption(tagStr, "bigstring=zero")}
+				binding.Encoder = bigEncoder
+				binding.Decoder = bigEncoder
+			} else if raw, ok := emptyRawLiteral(tagStr); ok {
+				binding.Encoder = &EmptyRawEncoder{binding.Encoder, raw}
+			} else if strings.Contains(tagStr, "emptyobject") {
+				binding.Encoder = &EmptyRawEncoder{binding.Encoder, "{}"}
 			}
 		} else if binding.Field.Type().Kind() == reflect.Slice || binding.Field.Type().Kind() == reflect.Array {
 			//处理空数组
-			if binding.Field.Type().Type1().Elem().String() == "int64" {
+			tagStr := binding.Field.Tag().Get("json")
+			if isInt64Slice(binding.Field.Type().Type1()) && strings.Contains(tagStr, "hexstring") {
 				//强制转64数组
-				int64SliceEncode := &EmptyArrayInt64Encoder{binding.Encoder, binding.Decoder}
-				binding.Encoder = int64SliceEncode
+				int64SliceEncode := &EmptyArrayInt64Encoder{binding.Encoder, binding.Decoder, extension.HexDecodeMode, fieldName}
+				if extension.DecimalInt64 {
+					binding.Encoder = &EmptyRawEncoder{binding.Encoder, "[]"}
+				} else {
+					binding.Encoder = int64SliceEncode
+				}
 				binding.Decoder = int64SliceEncode
-			} else if strings.Contains(binding.Field.Tag().Get("json"), "emptyarray") {
-				binding.Encoder = &EmptyArrayEncoder{binding.Encoder}
+			} else if raw, ok := emptyRawLiteral(tagStr); ok && binding.Field.Type().Kind() == reflect.Slice {
+				binding.Encoder = &EmptyRawEncoder{binding.Encoder, raw}
+			} else if strings.Contains(tagStr, "emptyarray") {
+				binding.Encoder = &EmptyRawEncoder{binding.Encoder, "[]"}
+			}
+		} else if binding.Field.Type().Type1() == timeType {
+			//处理毫秒时间戳
+			tagStr := binding.Field.Tag().Get("json")
+			if strings.Contains(tagStr, "unixmilli") {
+				hex := strings.Contains(tagStr, "hexstring")
+				binding.Encoder = &UnixMilliEncoder{hex && !extension.DecimalInt64, hexStringWidth(tagStr), extension.HexDecodeMode, fieldName}
+				binding.Decoder = &UnixMilliEncoder{hex, hexStringWidth(tagStr), extension.HexDecodeMode, fieldName}
 			}
 		} else if binding.Field.Type().Kind() == reflect.String {
 			if strings.Contains(binding.Field.Tag().Get("json"), "tostring") {
@@ -280,21 +566,177 @@ func (extension *ApipostExtension) UpdateStructDescriptor(structDescriptor *json
 	}
 }
 
-var jsonInstance jsoniter.API = jsoniter.Config{}.Froze()
+// NewAPI 按给定配置创建 jsoniter.API 并注册 ApipostExtension，解码方式为 CurrentHexDecodeMode，
+// 调用方可持有独立的实例而不影响包级 Marshal/Unmarshal
+func NewAPI(cfg jsoniter.Config) jsoniter.API {
+	return newAPI(cfg, &ApipostExtension{HexDecodeMode: CurrentHexDecodeMode()}, nil)
+}
+
+// instances 一组按同一配置生成的实例，整体替换，不会被修改
+type instances struct {
+	json    jsoniter.API
+	decimal jsoniter.API
+	decode  map[DecodeOptions]jsoniter.API
+}
+
+var (
+	// configMu 串行化 Configure
+	configMu sync.Mutex
+	// configurers Configure 注册过的全部配置函数，每次重建实例时依次重放
+	configurers []func(jsoniter.API)
+	// current 当前使用的 *instances
+	current atomic.Value
+)
 
 func init() {
-	jsonInstance.RegisterExtension(&ApipostExtension{})
+	current.Store(newInstances(nil))
+}
+
+// loadInstances 返回当前使用的实例
+func loadInstances() *instances {
+	return current.Load().(*instances)
+}
+
+// newAPI 生成新的实例并应用 configurers
+func newAPI(cfg jsoniter.Config, ext *ApipostExtension, configurers []func(jsoniter.API)) jsoniter.API {
+	api := cfg.Froze()
+	api.RegisterExtension(ext)
+	for _, fn := range configurers {
+		fn(api)
+	}
+	return api
+}
+
+func newInstances(configurers []func(jsoniter.API)) *instances {
+	mode := CurrentHexDecodeMode()
+	decode := make(map[DecodeOptions]jsoniter.API, 4)
+	for _, opts := range []DecodeOptions{{}, {UseNumber: true}, {DisallowUnknownFields: true}, {UseNumber: true, DisallowUnknownFields: true}} {
+		cfg := jsoniter.Config{UseNumber: opts.UseNumber, DisallowUnknownFields: opts.DisallowUnknownFields}
+		decode[opts] = newAPI(cfg, &ApipostExtension{HexDecodeMode: mode}, configurers)
+	}
+	return &instances{
+		json:    decode[DecodeOptions{}],
+		decimal: newAPI(jsoniter.Config{}, &ApipostExtension{HexDecodeMode: mode, DecimalInt64: true}, configurers),
+		decode:  decode,
+	}
+}
+
+// Configure 在运行时修改 json 实例，例如注册插件的扩展。
+// jsoniter.API 在使用中修改并不安全，所以 fn 作用在新生成的实例上，
+// 完成后整体原子替换；之前 Configure 过的 fn 也会重新应用。
+// 正在进行的 Marshal/Unmarshal 继续使用旧实例。
+func Configure(fn func(jsoniter.API)) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	next := append(configurers[:len(configurers):len(configurers)], fn)
+	current.Store(newInstances(next))
+	configurers = next
+}
+
+// SetHexDecodeMode 设置包级实例、NewAPI 及 ParseHexString 调用方使用的解码方式，
+// 与 Configure 一样重新生成实例后整体替换
+func SetHexDecodeMode(mode HexDecodeMode) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	atomic.StoreInt32(&hexDecodeMode, int32(mode))
+	current.Store(newInstances(configurers))
+}
+
+// Decode 从 r 读取一个 JSON 值到 v。hexstring 字段解码失败时不中断解析，
+// 所有失败的字段汇总为 HexDecodeErrors 返回
+func Decode(r io.Reader, v any, opts DecodeOptions) error {
+	return decodeIter(jsoniter.Parse(loadInstances().decode[opts], r, 512), v)
+}
+
+// DecodeBytes 与 Decode 相同，但直接解析 data，不再分块读取
+func DecodeBytes(data []byte, v any, opts DecodeOptions) error {
+	return decodeIter(jsoniter.ParseBytes(loadInstances().decode[opts], data), v)
+}
+
+func decodeIter(iter *jsoniter.Iterator, v any) error {
+	if iter.WhatIsNext() == jsoniter.InvalidValue && iter.Error == io.EOF {
+		return io.EOF
+	}
+	var hexErrs HexDecodeErrors
+	iter.Attachment = &hexErrs
+	iter.ReadVal(v)
+	if iter.Error != nil && iter.Error != io.EOF {
+		return iter.Error
+	}
+	if len(hexErrs) > 0 {
+		return hexErrs
+	}
+	return nil
 }
 
 var (
 	// Marshal is exported by gin/json package.
-	Marshal = jsonInstance.Marshal
+	Marshal = func(v any) ([]byte, error) {
+		return loadInstances().json.Marshal(v)
+	}
 	// Unmarshal is exported by gin/json package.
-	Unmarshal = jsonInstance.Unmarshal
+	Unmarshal = func(data []byte, v any) error {
+		return loadInstances().json.Unmarshal(data, v)
+	}
 	// MarshalIndent is exported by gin/json package.
-	MarshalIndent = jsonInstance.MarshalIndent
+	MarshalIndent = func(v any, prefix, indent string) ([]byte, error) {
+		return loadInstances().json.MarshalIndent(v, prefix, indent)
+	}
 	// NewDecoder is exported by gin/json package.
-	NewDecoder = jsonInstance.NewDecoder
+	NewDecoder = func(r io.Reader) *jsoniter.Decoder {
+		return loadInstances().json.NewDecoder(r)
+	}
 	// NewEncoder is exported by gin/json package.
-	NewEncoder = jsonInstance.NewEncoder
+	NewEncoder = func(w io.Writer) *jsoniter.Encoder {
+		return loadInstances().json.NewEncoder(w)
+	}
+	// MarshalDecimal 与 Marshal 相同，但 hexstring 字段按数字输出
+	MarshalDecimal = func(v any) ([]byte, error) {
+		return loadInstances().decimal.Marshal(v)
+	}
+	// Valid 报告 data 是否恰好是一个合法的 JSON 值，前后可以有空白
+	Valid = func(data []byte) bool {
+		return checkValid(data) == nil
+	}
+	// Compact 把 src 去掉字符串以外的空白后追加到 dst；src 不合法时返回错误，dst 不变
+	Compact = func(dst *bytes.Buffer, src []byte) error {
+		if err := checkValid(src); err != nil {
+			return err
+		}
+		dst.Grow(len(src))
+		inString, escaped := false, false
+		for _, c := range src {
+			switch {
+			case escaped:
+				escaped = false
+			case inString:
+				escaped = c == '\\'
+				inString = c != '"'
+			case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+				continue
+			case c == '"':
+				inString = true
+			}
+			dst.WriteByte(c)
+		}
+		return nil
+	}
 )
+
+// checkValid 用当前实例跳过一个 JSON 值，之后只允许空白
+func checkValid(data []byte) error {
+	iter := jsoniter.ParseBytes(loadInstances().json, data)
+	iter.Skip()
+	if iter.Error == io.EOF {
+		// 数字一直读到了末尾
+		return nil
+	}
+	if iter.Error != nil {
+		return iter.Error
+	}
+	if iter.WhatIsNext(); iter.Error != io.EOF {
+		iter.ReportError("Valid", "unexpected data after top-level value")
+		return iter.Error
+	}
+	return nil
+}
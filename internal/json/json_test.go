@@ -0,0 +1,759 @@
+// Copyright 2017 Bo-Yi Wu. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+//go:build !jsoniter && !go_json && !(sonic && avx && (linux || windows || darwin) && amd64)
+
+package json
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unsafe"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/modern-go/reflect2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHexStringEncode(t *testing.T) {
+	var s struct {
+		ID int64 `json:"id,hexstring"`
+	}
+
+	s.ID = 255
+	b, err := Marshal(s)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"00000000000000ff"}`, string(b))
+
+	s.ID = 0
+	b, err = Marshal(s)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"0000000000000000"}`, string(b))
+}
+
+func TestHexStringWidth(t *testing.T) {
+	var s struct {
+		ID      int64 `json:"id,hexstring=8"`
+		Full    int64 `json:"full,hexstring=16"`
+		Short   int64 `json:"short,hexstring=0"`
+		Default int64 `json:"default,hexstring"`
+		Empty   int64 `json:"empty,hexstring="`
+		Broken  int64 `json:"broken,hexstring=x"`
+		Large   int64 `json:"large,hexstring=99"`
+	}
+	s.ID, s.Full, s.Short, s.Default, s.Empty, s.Broken, s.Large = 255, 255, 255, 255, 255, 255, 255
+
+	// 未指定或非法的位数回退为16位
+	b, err := Marshal(s)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"000000ff","full":"00000000000000ff","short":"ff","default":"00000000000000ff",`+
+		`"empty":"00000000000000ff","broken":"00000000000000ff","large":"00000000000000ff"}`, string(b))
+
+	s.ID = 0x123456789a
+	b, err = Marshal(s)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"id":"123456789a"`)
+
+	var out struct {
+		ID int64 `json:"id,hexstring=8"`
+	}
+	require.NoError(t, Unmarshal([]byte(`{"id":"ff"}`), &out))
+	assert.Equal(t, int64(255), out.ID)
+	require.NoError(t, Unmarshal([]byte(`{"id":"00000000000000ff"}`), &out))
+	assert.Equal(t, int64(255), out.ID)
+}
+
+func TestHexStringWidthParse(t *testing.T) {
+	assert.Equal(t, 16, hexStringWidth("id,hexstring"))
+	assert.Equal(t, 8, hexStringWidth("id,hexstring=8"))
+	assert.Equal(t, 8, hexStringWidth("id,omitempty,hexstring=8"))
+	assert.Equal(t, 0, hexStringWidth("id,hexstring=0"))
+	assert.Equal(t, 16, hexStringWidth("id,hexstring="))
+	assert.Equal(t, 16, hexStringWidth("id,hexstring=x"))
+	assert.Equal(t, 16, hexStringWidth("id,hexstring=-1"))
+	assert.Equal(t, 16, hexStringWidth("id,hexstring=17"))
+	assert.Equal(t, 16, hexStringWidth("hexstring=8"))
+	assert.Equal(t, 16, hexStringWidth(""))
+}
+
+func newTestAPI(ext *ApipostExtension) jsoniter.API {
+	api := jsoniter.Config{}.Froze()
+	api.RegisterExtension(ext)
+	return api
+}
+
+func TestHexStringDecodeMode(t *testing.T) {
+	type idStruct struct {
+		ID  int64   `json:"id,hexstring"`
+		IDs []int64 `json:"ids,hexstring"`
+	}
+	auto := newTestAPI(&ApipostExtension{})
+	prefix := newTestAPI(&ApipostExtension{HexDecodeMode: HexDecodePrefix})
+
+	tests := []struct {
+		input  string
+		auto   int64
+		prefix int64
+	}{
+		// 15, 16, 17 位十进制
+		{"123456789012345", 0x123456789012345, 123456789012345},
+		{"1234567890123456", 0x1234567890123456, 1234567890123456},
+		{"12345678901234567", 12345678901234567, 12345678901234567},
+		// 15, 16, 17 位十六进制(含0x前缀时长度不含前缀)
+		{"0x123456789abcdef", 0x123456789abcdef, 0x123456789abcdef},
+		{"0x123456789abcdef0", 0x123456789abcdef0, 0x123456789abcdef0},
+		{"0x0123456789abcdef0", 0x123456789abcdef0, 0x123456789abcdef0},
+		{"0X0123456789ABCDEF0", 0x123456789abcdef0, 0x123456789abcdef0},
+	}
+	for _, tt := range tests {
+		body := []byte(`{"id":"` + tt.input + `","ids":["` + tt.input + `"]}`)
+
+		var a idStruct
+		require.NoError(t, auto.Unmarshal(body, &a), tt.input)
+		assert.Equal(t, tt.auto, a.ID, tt.input)
+		assert.Equal(t, []int64{tt.auto}, a.IDs, tt.input)
+
+		var p idStruct
+		require.NoError(t, prefix.Unmarshal(body, &p), tt.input)
+		assert.Equal(t, tt.prefix, p.ID, tt.input)
+		assert.Equal(t, []int64{tt.prefix}, p.IDs, tt.input)
+	}
+
+	// 不带前缀的十六进制在 prefix 模式下不是合法的十进制
+	var p idStruct
+	assert.Error(t, prefix.Unmarshal([]byte(`{"id":"ff"}`), &p))
+	assert.Error(t, prefix.Unmarshal([]byte(`{"ids":["10","ff"]}`), &p))
+
+	// prefix 模式编码带0x前缀，可以按同一方式解码
+	in := idStruct{ID: 255, IDs: []int64{16, -1}}
+	b, err := prefix.Marshal(in)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"0x00000000000000ff","ids":["0x10","-0x1"]}`, string(b))
+	p = idStruct{}
+	require.NoError(t, prefix.Unmarshal(b, &p))
+	assert.Equal(t, in, p)
+}
+
+func TestSetHexDecodeMode(t *testing.T) {
+	defer SetHexDecodeMode(HexDecodeAuto)
+	var s struct {
+		ID    int64  `json:"id,hexstring=4"`
+		Owner uint64 `json:"owner,hexstring=0"`
+	}
+	assert.Equal(t, HexDecodeAuto, CurrentHexDecodeMode())
+
+	SetHexDecodeMode(HexDecodePrefix)
+	assert.Equal(t, HexDecodePrefix, CurrentHexDecodeMode())
+	require.NoError(t, Unmarshal([]byte(`{"id":"10","owner":"0x10"}`), &s))
+	assert.Equal(t, int64(10), s.ID)
+	assert.Equal(t, uint64(16), s.Owner)
+
+	b, err := Marshal(s)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"0x000a","owner":"0x10"}`, string(b))
+	b, err = NewAPI(jsoniter.Config{}).Marshal(s)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"0x000a","owner":"0x10"}`, string(b))
+	b, err = MarshalDecimal(s)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":10,"owner":16}`, string(b))
+
+	SetHexDecodeMode(HexDecodeAuto)
+	require.NoError(t, Unmarshal([]byte(`{"id":"10"}`), &s))
+	assert.Equal(t, int64(16), s.ID)
+}
+
+func TestHexStringDecodeError(t *testing.T) {
+	var s struct {
+		ID  int64   `json:"id,hexstring"`
+		IDs []int64 `json:"ids,hexstring"`
+	}
+	err := Unmarshal([]byte(`{"id":"not-a-number"}`), &s)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "HexStringEncoder.Decode")
+
+	err = Unmarshal([]byte(`{"ids":["ff","zz"]}`), &s)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "EmptyArrayInt64Encoder.Decode")
+
+	// 超出 int64 范围
+	assert.Error(t, Unmarshal([]byte(`{"id":"99999999999999999999"}`), &s))
+
+	s.ID = 1
+	require.NoError(t, Unmarshal([]byte(`{"id":""}`), &s))
+	assert.Equal(t, int64(0), s.ID)
+}
+
+func TestHexStringDecodeNull(t *testing.T) {
+	var s struct {
+		ID   int64  `json:"id,hexstring"`
+		Name string `json:"name"`
+	}
+	s.ID = 1
+	require.NoError(t, Unmarshal([]byte(`{"id":null,"name":"gin"}`), &s))
+	assert.Equal(t, int64(0), s.ID)
+	assert.Equal(t, "gin", s.Name)
+
+	s.ID = 1
+	require.NoError(t, Unmarshal([]byte(`{"id":true,"name":"gin"}`), &s))
+	assert.Equal(t, int64(0), s.ID)
+}
+
+func TestEmptyArrayInt64DecodeMixed(t *testing.T) {
+	var s struct {
+		IDs  []int64 `json:"ids,hexstring"`
+		Name string  `json:"name"`
+	}
+	require.NoError(t, Unmarshal([]byte(`{"ids":[1,2,"ff",-3],"name":"gin"}`), &s))
+	assert.Equal(t, []int64{1, 2, 255, -3}, s.IDs)
+	assert.Equal(t, "gin", s.Name)
+
+	// 十进制输出可以解码回来
+	b, err := MarshalDecimal(s)
+	require.NoError(t, err)
+	s.IDs = nil
+	require.NoError(t, Unmarshal(b, &s))
+	assert.Equal(t, []int64{1, 2, 255, -3}, s.IDs)
+
+	for _, body := range []string{`{"ids":["ff",null]}`, `{"ids":[true]}`, `{"ids":[{}]}`} {
+		err := Unmarshal([]byte(body), &s)
+		require.Error(t, err, body)
+		assert.Contains(t, err.Error(), "EmptyArrayInt64Encoder.Decode", body)
+	}
+	assert.Error(t, Unmarshal([]byte(`{"ids":[1.5]}`), &s))
+}
+
+func TestHexUint64RoundTrip(t *testing.T) {
+	type idStruct struct {
+		ID    uint64 `json:"id,hexstring"`
+		Plain uint64 `json:"plain"`
+	}
+	in := idStruct{ID: 0xFFFFFFFFFFFFFFFF, Plain: 0xFFFFFFFFFFFFFFFF}
+	b, err := Marshal(in)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"ffffffffffffffff","plain":18446744073709551615}`, string(b))
+
+	var out idStruct
+	require.NoError(t, Unmarshal(b, &out))
+	assert.Equal(t, in, out)
+
+	require.NoError(t, Unmarshal([]byte(`{"id":"18446744073709551615"}`), &out))
+	assert.Equal(t, uint64(0xFFFFFFFFFFFFFFFF), out.ID)
+	require.NoError(t, Unmarshal([]byte(`{"id":"0x8000000000000000"}`), &out))
+	assert.Equal(t, uint64(1<<63), out.ID)
+	assert.Error(t, Unmarshal([]byte(`{"id":"-1"}`), &out))
+
+	var padded struct {
+		ID uint64 `json:"id,hexstring=16"`
+	}
+	padded.ID = 255
+	b, err = Marshal(padded)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"00000000000000ff"}`, string(b))
+}
+
+func TestEmptyObjectMap(t *testing.T) {
+	type mapStruct struct {
+		Attrs map[string]any `json:"attrs,emptyobject"`
+		Plain map[string]any `json:"plain"`
+	}
+
+	b, err := Marshal(mapStruct{})
+	require.NoError(t, err)
+	assert.Equal(t, `{"attrs":{},"plain":null}`, string(b))
+
+	b, err = Marshal(mapStruct{Attrs: map[string]any{}, Plain: map[string]any{}})
+	require.NoError(t, err)
+	assert.Equal(t, `{"attrs":{},"plain":{}}`, string(b))
+
+	b, err = Marshal(mapStruct{Attrs: map[string]any{"foo": "bar"}})
+	require.NoError(t, err)
+	assert.Equal(t, `{"attrs":{"foo":"bar"},"plain":null}`, string(b))
+}
+
+type IDList []int64
+
+type myInt64 int64
+
+func TestEmptyArrayInt64NamedTypes(t *testing.T) {
+	type listStruct struct {
+		IDs    IDList    `json:"ids,hexstring"`
+		Array  [2]int64  `json:"array"`
+		Custom []myInt64 `json:"custom"`
+	}
+	in := listStruct{
+		IDs:    IDList{255, 0},
+		Array:  [2]int64{255, 1},
+		Custom: []myInt64{255},
+	}
+	b, err := Marshal(in)
+	require.NoError(t, err)
+	assert.Equal(t, `{"ids":["ff","0"],"array":[255,1],"custom":[255]}`, string(b))
+
+	var out listStruct
+	require.NoError(t, Unmarshal(b, &out))
+	assert.Equal(t, in, out)
+
+	b, err = Marshal(listStruct{})
+	require.NoError(t, err)
+	assert.Equal(t, `{"ids":[],"array":[0,0],"custom":null}`, string(b))
+}
+
+func TestInt64SliceRequiresHexStringTag(t *testing.T) {
+	type listStruct struct {
+		Hex     []int64 `json:"hex,hexstring"`
+		Plain   []int64 `json:"plain"`
+		Empty   []int64 `json:"empty,emptyarray"`
+		Numbers []int64 `json:"numbers,emptyarray"`
+	}
+	b, err := Marshal(listStruct{Numbers: []int64{255}})
+	require.NoError(t, err)
+	assert.Equal(t, `{"hex":[],"plain":null,"empty":[],"numbers":[255]}`, string(b))
+
+	var out listStruct
+	require.NoError(t, Unmarshal([]byte(`{"hex":["ff"],"plain":[255],"numbers":[1,2]}`), &out))
+	assert.Equal(t, []int64{255}, out.Hex)
+	assert.Equal(t, []int64{255}, out.Plain)
+	assert.Equal(t, []int64{1, 2}, out.Numbers)
+}
+
+func TestNewAPI(t *testing.T) {
+	api := NewAPI(jsoniter.Config{EscapeHTML: true, SortMapKeys: true})
+
+	var s struct {
+		ID   int64          `json:"id,hexstring"`
+		Tags map[string]int `json:"tags"`
+		HTML string         `json:"html"`
+	}
+	s.ID = 255
+	s.Tags = map[string]int{"b": 2, "a": 1, "c": 3}
+	s.HTML = "<b>"
+
+	b, err := api.Marshal(s)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"00000000000000ff","tags":{"a":1,"b":2,"c":3},"html":"\u003cb\u003e"}`, string(b))
+
+	// 包级实例不受影响
+	b, err = Marshal(s)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"html":"<b>"`)
+}
+
+func TestEmptyArrayInt64Stream(t *testing.T) {
+	var s struct {
+		IDs []int64 `json:"ids,hexstring"`
+	}
+	s.IDs = []int64{}
+	b, err := Marshal(s)
+	require.NoError(t, err)
+	assert.Equal(t, `{"ids":[]}`, string(b))
+
+	s.IDs = []int64{0, 1, 255, 0x123456789abcdef}
+	b, err = Marshal(s)
+	require.NoError(t, err)
+	assert.Equal(t, `{"ids":["0","1","ff","123456789abcdef"]}`, string(b))
+
+	b, err = MarshalIndent(s, "", "  ")
+	require.NoError(t, err)
+	var out struct {
+		IDs []int64 `json:"ids,hexstring"`
+	}
+	require.NoError(t, Unmarshal(b, &out))
+	assert.Equal(t, s.IDs, out.IDs)
+}
+
+func benchmarkInt64Slice() []int64 {
+	ids := make([]int64, 1000)
+	for i := range ids {
+		ids[i] = int64(i) * 0x10001
+	}
+	return ids
+}
+
+func BenchmarkEmptyArrayInt64Stream(b *testing.B) {
+	var s struct {
+		IDs []int64 `json:"ids,hexstring"`
+	}
+	s.IDs = benchmarkInt64Slice()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Marshal(s); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkEmptyArrayInt64Intermediate 旧实现：先转成 []string 再整体 Marshal
+func BenchmarkEmptyArrayInt64Intermediate(b *testing.B) {
+	ids := benchmarkInt64Slice()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		strSlice := make([]string, len(ids))
+		for j, v := range ids {
+			strSlice[j] = formatHex(v, 0)
+		}
+		data, err := Marshal(strSlice)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err = Marshal(struct {
+			IDs jsoniter.RawMessage `json:"ids"`
+		}{data}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestHexStringNegative(t *testing.T) {
+	type idStruct struct {
+		ID     int64   `json:"id,hexstring"`
+		Padded int64   `json:"padded,hexstring=16"`
+		IDs    []int64 `json:"ids,hexstring"`
+	}
+	for _, v := range []int64{-1, -255, math.MinInt64} {
+		in := idStruct{ID: v, Padded: v, IDs: []int64{v}}
+		b, err := Marshal(in)
+		require.NoError(t, err)
+
+		var out idStruct
+		require.NoError(t, Unmarshal(b, &out), string(b))
+		assert.Equal(t, in, out, string(b))
+	}
+
+	b, err := Marshal(idStruct{ID: -255, Padded: -255})
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"-0000000000000ff","padded":"-0000000000000ff","ids":[]}`, string(b))
+
+	b, err = Marshal(idStruct{ID: math.MinInt64})
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"id":"-8000000000000000"`)
+
+	var out idStruct
+	require.NoError(t, Unmarshal([]byte(`{"id":"-0xff"}`), &out))
+	assert.Equal(t, int64(-255), out.ID)
+}
+
+func TestDecodeHexDecodeErrors(t *testing.T) {
+	type inner struct {
+		ID int64 `json:"inner_id,hexstring"`
+	}
+	var s struct {
+		ID    int64  `json:"id,hexstring"`
+		Inner inner  `json:"inner"`
+		Name  string `json:"name"`
+	}
+	err := Decode(strings.NewReader(`{"id":"zz","inner":{"inner_id":"0xqq"},"name":"foo"}`), &s, DecodeOptions{})
+	require.Error(t, err)
+
+	var hexErrs HexDecodeErrors
+	require.True(t, errors.As(err, &hexErrs))
+	assert.Equal(t, []string{"id", "inner_id"}, hexErrs.Fields())
+	assert.Equal(t, "0xqq", hexErrs[1].Raw)
+	assert.Equal(t, "foo", s.Name)
+
+	// 其它语法错误仍然中断解析
+	err = Decode(strings.NewReader(`{"id":`), &s, DecodeOptions{})
+	require.Error(t, err)
+	assert.False(t, errors.As(err, &hexErrs))
+
+	assert.Equal(t, io.EOF, Decode(strings.NewReader(""), &s, DecodeOptions{}))
+	assert.Error(t, Decode(strings.NewReader(`{"unknown":1}`), &s, DecodeOptions{DisallowUnknownFields: true}))
+
+	var m map[string]any
+	require.NoError(t, Decode(strings.NewReader(`{"n":1}`), &m, DecodeOptions{UseNumber: true}))
+	assert.Equal(t, "1", fmt.Sprint(m["n"]))
+	assert.IsType(t, stdjson.Number(""), m["n"])
+}
+
+func TestEmptyRaw(t *testing.T) {
+	type rawStruct struct {
+		Meta    *struct{}      `json:"meta,emptyraw={}"`
+		List    *[]string      `json:"list,emptyraw=[]"`
+		Null    map[string]any `json:"null,emptyraw=null"`
+		Zero    any            `json:"zero,emptyraw=0"`
+		Names   []string       `json:"names,emptyraw=[]"`
+		Invalid *struct{}      `json:"invalid,emptyraw={"`
+		Blank   *struct{}      `json:"blank,emptyraw="`
+	}
+	b, err := Marshal(rawStruct{})
+	require.NoError(t, err)
+	assert.Equal(t, `{"meta":{},"list":[],"null":null,"zero":0,"names":[],"invalid":null,"blank":null}`, string(b))
+
+	b, err = Marshal(rawStruct{Meta: &struct{}{}, Zero: "x", Names: []string{"a"}})
+	require.NoError(t, err)
+	assert.Equal(t, `{"meta":{},"list":[],"null":null,"zero":"x","names":["a"],"invalid":null,"blank":null}`, string(b))
+}
+
+func TestEmptyRawLiteral(t *testing.T) {
+	raw, ok := emptyRawLiteral("meta,emptyraw={}")
+	assert.True(t, ok)
+	assert.Equal(t, "{}", raw)
+	raw, ok = emptyRawLiteral(`meta,omitempty,emptyraw="n/a"`)
+	assert.True(t, ok)
+	assert.Equal(t, `"n/a"`, raw)
+	_, ok = emptyRawLiteral("meta,emptyraw=[")
+	assert.False(t, ok)
+	_, ok = emptyRawLiteral("meta,emptyobject")
+	assert.False(t, ok)
+}
+
+func TestMarshalDecimal(t *testing.T) {
+	type decimalStruct struct {
+		ID    int64   `json:"id,hexstring"`
+		Owner uint64  `json:"owner,hexstring=4"`
+		IDs   []int64 `json:"ids,hexstring"`
+		Empty []int64 `json:"empty,hexstring"`
+	}
+	v := decimalStruct{ID: 255, Owner: 16, IDs: []int64{10, -1}}
+	b, err := MarshalDecimal(v)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":255,"owner":16,"ids":[10,-1],"empty":[]}`, string(b))
+
+	var out decimalStruct
+	require.NoError(t, Unmarshal([]byte(`{"id":"ff","owner":"0010","ids":["a","-1"]}`), &out))
+	assert.Equal(t, v.ID, out.ID)
+	assert.Equal(t, v.Owner, out.Owner)
+	assert.Equal(t, v.IDs, out.IDs)
+}
+
+// upperExtension 把 upper 类型编码为大写字符串
+type upperExtension struct {
+	jsoniter.DummyExtension
+}
+
+type upper string
+
+type upperEncoder struct{}
+
+func (upperEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return *(*upper)(ptr) == ""
+}
+
+func (upperEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	stream.WriteString(strings.ToUpper(string(*(*upper)(ptr))))
+}
+
+func (*upperExtension) CreateEncoder(typ reflect2.Type) jsoniter.ValEncoder {
+	if typ != reflect2.TypeOf(upper("")) {
+		return nil
+	}
+	return upperEncoder{}
+}
+
+func TestConfigure(t *testing.T) {
+	t.Cleanup(func() {
+		configMu.Lock()
+		configurers = nil
+		current.Store(newInstances(nil))
+		configMu.Unlock()
+	})
+	type configureStruct struct {
+		ID   int64 `json:"id,hexstring"`
+		Name upper `json:"name"`
+	}
+	v := configureStruct{ID: 255, Name: "gin"}
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				b, err := Marshal(v)
+				assert.NoError(t, err)
+				assert.Contains(t, []string{`{"id":"00000000000000ff","name":"gin"}`, `{"id":"00000000000000ff","name":"GIN"}`}, string(b))
+				var out configureStruct
+				assert.NoError(t, Unmarshal(b, &out))
+				assert.Equal(t, int64(255), out.ID)
+			}
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		Configure(func(api jsoniter.API) {})
+	}
+	Configure(func(api jsoniter.API) {
+		api.RegisterExtension(&upperExtension{})
+	})
+	close(stop)
+	wg.Wait()
+
+	b, err := Marshal(v)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"00000000000000ff","name":"GIN"}`, string(b))
+
+	// 之后的 Configure 保留之前注册的扩展
+	Configure(func(api jsoniter.API) {})
+	b, err = MarshalDecimal(v)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":255,"name":"GIN"}`, string(b))
+}
+
+func TestUnixMilli(t *testing.T) {
+	type milliStruct struct {
+		Created time.Time `json:"created,unixmilli"`
+		Updated time.Time `json:"updated,unixmilli,hexstring"`
+		Deleted time.Time `json:"deleted,unixmilli"`
+		Plain   time.Time `json:"plain"`
+	}
+	utc := time.Date(2023, 7, 1, 8, 30, 0, 123e6, time.UTC)
+	ms := utc.UnixMilli()
+	for _, name := range []string{"UTC", "Asia/Shanghai", "America/New_York"} {
+		loc, err := time.LoadLocation(name)
+		require.NoError(t, err)
+		v := milliStruct{Created: utc.In(loc), Updated: utc.In(loc), Plain: utc}
+
+		b, err := Marshal(v)
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf(`{"created":%d,"updated":"%016x","deleted":0,"plain":"2023-07-01T08:30:00.123Z"}`, ms, ms), string(b), name)
+
+		var out milliStruct
+		require.NoError(t, Unmarshal(b, &out))
+		assert.True(t, out.Created.Equal(v.Created), name)
+		assert.True(t, out.Updated.Equal(v.Updated), name)
+		assert.True(t, out.Deleted.IsZero(), name)
+	}
+
+	var out milliStruct
+	require.NoError(t, Unmarshal([]byte(fmt.Sprintf(`{"created":"%d","updated":%d,"deleted":null}`, ms, ms)), &out))
+	assert.Equal(t, ms, out.Created.UnixMilli())
+	assert.Equal(t, ms, out.Updated.UnixMilli())
+	assert.True(t, out.Deleted.IsZero())
+
+	assert.Error(t, Unmarshal([]byte(`{"created":"yesterday"}`), &out))
+
+	b, err := MarshalDecimal(milliStruct{Updated: utc})
+	require.NoError(t, err)
+	assert.Contains(t, string(b), fmt.Sprintf(`"updated":%d`, ms))
+}
+
+func TestValid(t *testing.T) {
+	for _, data := range []string{`{}`, ` {"a":[1,2.5,"x",true,null]} `, `"str"`, `-1e3`, ` 7 `, "[\n\t{}\r\n]\n"} {
+		assert.True(t, Valid([]byte(data)), data)
+		assert.Equal(t, stdjson.Valid([]byte(data)), Valid([]byte(data)), data)
+	}
+	for _, data := range []string{``, ` `, `{"a":}`, `[1,2`, `"abc`, `tru`, `-`, `1.`, `1e`, `01`, `{"a":1} x`, `{"a":1}}`, `1 2`} {
+		assert.False(t, Valid([]byte(data)), data)
+		assert.Equal(t, stdjson.Valid([]byte(data)), Valid([]byte(data)), data)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	src := "{\n\t\"name\": \"a b\\\" \\\\\",\r\n\t\"ids\": [ 1, 2 ],\n\t\"nested\": { \"x\" : null }\n}\n"
+	var buf, std bytes.Buffer
+	buf.WriteString("prefix")
+	require.NoError(t, Compact(&buf, []byte(src)))
+	assert.Equal(t, `prefix{"name":"a b\" \\","ids":[1,2],"nested":{"x":null}}`, buf.String())
+	require.NoError(t, stdjson.Compact(&std, []byte(src)))
+	assert.Equal(t, std.String(), strings.TrimPrefix(buf.String(), "prefix"))
+
+	buf.Reset()
+	buf.WriteString("kept")
+	assert.Error(t, Compact(&buf, []byte(`{"a": 1,}`)))
+	assert.Error(t, Compact(&buf, []byte(`{"a": 1} {}`)))
+	assert.Equal(t, "kept", buf.String())
+}
+
+type omitValueStruct struct {
+	ID       int64 `json:"id"`
+	ParentID int64 `json:"parent_id,omitvalue=-1" form:"parent_id"`
+	OwnerID  int64 `json:"owner_id,hexstring,omitvalue=-1"`
+	Depth    int64 `json:"depth,omitempty,omitvalue=-1"`
+	Bad      int64 `json:"bad,omitvalue=x"`
+}
+
+func TestOmitValue(t *testing.T) {
+	b, err := Marshal(omitValueStruct{ID: 1, ParentID: -1, OwnerID: -1, Depth: -1, Bad: -1})
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":1,"bad":-1}`, string(b))
+
+	b, err = Marshal(omitValueStruct{ParentID: 0, OwnerID: 255, Depth: 0})
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":0,"parent_id":0,"owner_id":"00000000000000ff","bad":0}`, string(b))
+
+	b, err = Marshal(omitValueStruct{ParentID: 7, OwnerID: 0, Depth: 3})
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":0,"parent_id":7,"owner_id":"0000000000000000","depth":3,"bad":0}`, string(b))
+
+	b, err = MarshalDecimal(omitValueStruct{ParentID: -1, OwnerID: 255})
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":0,"owner_id":255,"bad":0}`, string(b))
+
+	var out omitValueStruct
+	require.NoError(t, Unmarshal([]byte(`{"parent_id":-1,"owner_id":"ff","depth":2}`), &out))
+	assert.Equal(t, omitValueStruct{ParentID: -1, OwnerID: 255, Depth: 2}, out)
+
+	// other tags of the field are kept
+	structType := reflect2.Type2(reflect.TypeOf(omitValueStruct{})).(reflect2.StructType)
+	field := withOmitEmpty(structType.FieldByName("ParentID"), "parent_id,omitvalue=-1")
+	assert.Equal(t, "parent_id,omitvalue=-1,omitempty", field.Tag().Get("json"))
+	assert.Equal(t, "parent_id", field.Tag().Get("form"))
+}
+
+func TestOmitValueParse(t *testing.T) {
+	for tag, want := range map[string]int64{"a,omitvalue=-1": -1, "a,omitempty,omitvalue=42": 42} {
+		value, ok := omitValue(tag)
+		assert.True(t, ok, tag)
+		assert.Equal(t, want, value, tag)
+	}
+	for _, tag := range []string{"a", "omitvalue=1", "a,omitvalue=", "a,omitvalue=0x10", "a,omitvalue=99999999999999999999"} {
+		_, ok := omitValue(tag)
+		assert.False(t, ok, tag)
+	}
+}
+
+type bigStringStruct struct {
+	Amount  *big.Int `json:"amount,bigstring"`
+	Balance *big.Int `json:"balance,bigstring=zero"`
+	Plain   *big.Int `json:"plain"`
+	Limit   *big.Int `json:"limit,omitempty,bigstring"`
+}
+
+func TestBigString(t *testing.T) {
+	digits := strings.Repeat("1234567890", 10)
+	amount, ok := new(big.Int).SetString(digits, 10)
+	require.True(t, ok)
+	negative := new(big.Int).Neg(amount)
+
+	b, err := Marshal(bigStringStruct{Amount: amount, Balance: negative, Plain: big.NewInt(5)})
+	require.NoError(t, err)
+	assert.Equal(t, `{"amount":"`+digits+`","balance":"-`+digits+`","plain":5}`, string(b))
+
+	var out bigStringStruct
+	require.NoError(t, Unmarshal(b, &out))
+	assert.Equal(t, 0, amount.Cmp(out.Amount))
+	assert.Equal(t, 0, negative.Cmp(out.Balance))
+	assert.Nil(t, out.Limit)
+
+	// numbers are accepted too
+	require.NoError(t, Unmarshal([]byte(`{"amount":`+digits+`,"balance":null,"limit":""}`), &out))
+	assert.Equal(t, 0, amount.Cmp(out.Amount))
+	assert.Nil(t, out.Balance)
+	assert.Nil(t, out.Limit)
+
+	b, err = Marshal(bigStringStruct{})
+	require.NoError(t, err)
+	assert.Equal(t, `{"amount":null,"balance":"0","plain":null}`, string(b))
+
+	assert.Error(t, Unmarshal([]byte(`{"amount":"12x"}`), &out))
+	assert.Error(t, Unmarshal([]byte(`{"amount":1.5}`), &out))
+}
@@ -6,7 +6,14 @@
 
 package json
 
-import jsoniter "github.com/json-iterator/go"
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"io"
+	"sync/atomic"
+
+	jsoniter "github.com/json-iterator/go"
+)
 
 var (
 	json = jsoniter.ConfigCompatibleWithStandardLibrary
@@ -20,4 +27,32 @@ var (
 	NewDecoder = json.NewDecoder
 	// NewEncoder is exported by gin/json package.
 	NewEncoder = json.NewEncoder
+	// MarshalDecimal is the same as Marshal, hexstring tags only apply to the default build.
+	MarshalDecimal = json.Marshal
+	// Valid is exported by gin/json package.
+	Valid = stdjson.Valid
+	// Compact is exported by gin/json package.
+	Compact = stdjson.Compact
 )
+
+// SetHexDecodeMode sets the mode CurrentHexDecodeMode returns, hexstring tags only apply to the default build.
+func SetHexDecodeMode(mode HexDecodeMode) {
+	atomic.StoreInt32(&hexDecodeMode, int32(mode))
+}
+
+// Decode reads the next JSON value from r into v, honoring the decode options.
+func Decode(r io.Reader, v any, opts DecodeOptions) error {
+	decoder := NewDecoder(r)
+	if opts.UseNumber {
+		decoder.UseNumber()
+	}
+	if opts.DisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	return decoder.Decode(v)
+}
+
+// DecodeBytes is the same as Decode, reading from data.
+func DecodeBytes(data []byte, v any, opts DecodeOptions) error {
+	return Decode(bytes.NewReader(data), v, opts)
+}
@@ -5,6 +5,7 @@
 package gin
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -434,3 +435,42 @@ func TestForceConsoleColor(t *testing.T) {
 	// reset console color mode.
 	consoleColorMode = autoColor
 }
+
+func TestLoggerJSON(t *testing.T) {
+	buffer := new(strings.Builder)
+	router := New()
+	router.Use(LoggerJSON(buffer, func(c *Context, entry *JSONLogEntry) {
+		entry.Fields = map[string]any{
+			"user": struct {
+				ID int64 `json:"id"`
+			}{255},
+		}
+	}))
+	router.GET("/example", func(c *Context) {
+		c.Header("X-Request-Id", "req-1")
+		c.String(http.StatusCreated, "hello")
+	})
+
+	PerformRequest(router, "GET", "/example?a=100")
+
+	line := buffer.String()
+	assert.True(t, strings.HasSuffix(line, "}\n"))
+	var entry map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(line), &entry))
+	assert.Equal(t, "GET", entry["method"])
+	assert.Equal(t, "/example?a=100", entry["path"])
+	assert.Equal(t, float64(http.StatusCreated), entry["status"])
+	assert.Equal(t, "req-1", entry["request_id"])
+	assert.Equal(t, float64(5), entry["bytes_out"])
+	assert.Contains(t, entry, "latency_ms")
+	assert.Contains(t, entry, "client_ip")
+	assert.Contains(t, line, `"fields":{"user":{"id":255}}`)
+	assert.NotContains(t, line, "\033[")
+
+	buffer.Reset()
+	router = New()
+	router.Use(LoggerJSON(buffer))
+	router.GET("/empty", func(c *Context) {})
+	PerformRequest(router, "GET", "/empty", header{Key: "X-Request-Id", Value: "req-2"})
+	assert.Contains(t, buffer.String(), `"request_id":"req-2","bytes_out":0}`)
+}
@@ -0,0 +1,162 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned with 429 Too Many Requests by RateLimit.
+var ErrRateLimited = errors.New("too many requests")
+
+// RateLimitConfig configures the RateLimit middleware.
+type RateLimitConfig struct {
+	// KeyFunc returns the key requests are counted by, e.g. an API key.
+	// Optional. Default value is Context.ClientIP. Requests with an empty key are not limited.
+	KeyFunc func(c *Context) string
+
+	// Rate is the number of requests per second a key is allowed on average.
+	Rate float64
+
+	// Burst is the number of requests a key can make at once, after being idle.
+	// Optional. Default value is 1.
+	Burst int
+
+	// Store keeps the token bucket of each key.
+	// Optional. Default value is a new MemoryRateLimitStore.
+	Store RateLimitStore
+}
+
+// RateLimitResult is the outcome of taking a token from a bucket of a RateLimitStore.
+type RateLimitResult struct {
+	// Allowed reports whether a token was taken.
+	Allowed bool
+	// Remaining is the number of tokens left.
+	Remaining int
+	// RetryAfter is the time until the next token, when the request is not allowed.
+	RetryAfter time.Duration
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+}
+
+// RateLimitStore keeps the token buckets of RateLimit. Take is called on every request and
+// must be safe for concurrent use: two requests must never take the same token.
+type RateLimitStore interface {
+	// Take takes a token from the bucket of key, which holds up to burst tokens and
+	// gains rate tokens per second, and starts full.
+	Take(key string, rate float64, burst int) RateLimitResult
+}
+
+// RateLimit returns a middleware limiting the requests of each key, the client IP by default,
+// with a token bucket: a key can make Burst requests at once and gets Rate more per second.
+// Responses get X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset headers, the
+// last one in seconds until the bucket is full. A request over the limit is aborted with
+// 429 Too Many Requests, a Retry-After header in seconds and a {"error": "..."} JSON body.
+// It panics if Rate is not positive.
+func RateLimit(conf RateLimitConfig) HandlerFunc {
+	assert1(conf.Rate > 0, "rate limit must be positive")
+	if conf.KeyFunc == nil {
+		conf.KeyFunc = func(c *Context) string {
+			return c.ClientIP()
+		}
+	}
+	if conf.Burst < 1 {
+		conf.Burst = 1
+	}
+	if conf.Store == nil {
+		conf.Store = NewMemoryRateLimitStore()
+	}
+	limit := strconv.Itoa(conf.Burst)
+
+	return func(c *Context) {
+		key := conf.KeyFunc(c)
+		if key == "" {
+			c.Next()
+			return
+		}
+		res := conf.Store.Take(key, conf.Rate, conf.Burst)
+		header := c.Writer.Header()
+		header.Set("X-RateLimit-Limit", limit)
+		header.Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		header.Set("X-RateLimit-Reset", ceilSeconds(res.Reset))
+		if !res.Allowed {
+			header.Set("Retry-After", ceilSeconds(res.RetryAfter))
+			c.Error(ErrRateLimited) //nolint: errcheck
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, H{"error": ErrRateLimited.Error()})
+			return
+		}
+		c.Next()
+	}
+}
+
+// ceilSeconds formats d as a whole number of seconds, rounded up.
+func ceilSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
+
+// MemoryRateLimitStore is a RateLimitStore keeping the buckets in memory.
+// Full buckets are dropped once a minute.
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time
+}
+
+var _ RateLimitStore = (*MemoryRateLimitStore)(nil)
+
+// NewMemoryRateLimitStore returns an empty MemoryRateLimitStore.
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{buckets: make(map[string]*tokenBucket), now: time.Now}
+}
+
+// Take implements RateLimitStore.
+func (s *MemoryRateLimitStore) Take(key string, rate float64, burst int) RateLimitResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if now.Sub(s.lastSweep) >= time.Minute {
+		for k, b := range s.buckets {
+			if !now.Before(b.full) {
+				delete(s.buckets, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	b := s.buckets[key]
+	if b == nil {
+		b = &tokenBucket{tokens: float64(burst), last: now}
+		s.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(burst), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	res := RateLimitResult{Allowed: b.tokens >= 1}
+	if res.Allowed {
+		b.tokens--
+	} else {
+		res.RetryAfter = secondsDuration((1 - b.tokens) / rate)
+	}
+	res.Remaining = int(b.tokens)
+	res.Reset = secondsDuration((float64(burst) - b.tokens) / rate)
+	b.full = now.Add(res.Reset)
+	return res
+}
+
+func secondsDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,97 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitBurst(t *testing.T) {
+	now := time.Now()
+	store := NewMemoryRateLimitStore()
+	store.now = func() time.Time { return now }
+	router := New()
+	router.Use(RateLimit(RateLimitConfig{
+		KeyFunc: func(c *Context) string { return c.GetHeader("X-API-Key") },
+		Rate:    0.5,
+		Burst:   2,
+		Store:   store,
+	}))
+	router.GET("/", func(c *Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	key := header{Key: "X-API-Key", Value: "k1"}
+
+	for _, remaining := range []string{"1", "0"} {
+		w := PerformRequest(router, http.MethodGet, "/", key)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+		assert.Equal(t, remaining, w.Header().Get("X-RateLimit-Remaining"))
+		assert.Empty(t, w.Header().Get("Retry-After"))
+	}
+
+	w := PerformRequest(router, http.MethodGet, "/", key)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, `{"error":"too many requests"}`, w.Body.String())
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "4", w.Header().Get("X-RateLimit-Reset"))
+
+	// other keys have their own bucket, requests without a key are not limited
+	w = PerformRequest(router, http.MethodGet, "/", header{Key: "X-API-Key", Value: "k2"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	for i := 0; i < 5; i++ {
+		w = PerformRequest(router, http.MethodGet, "/")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("X-RateLimit-Limit"))
+	}
+}
+
+func TestRateLimitRefill(t *testing.T) {
+	now := time.Now()
+	store := NewMemoryRateLimitStore()
+	store.now = func() time.Time { return now }
+	// requests are counted by client IP by default
+	router := New()
+	router.Use(RateLimit(RateLimitConfig{Rate: 0.5, Burst: 2, Store: store}))
+	router.GET("/", func(c *Context) {})
+
+	PerformRequest(router, http.MethodGet, "/")
+	PerformRequest(router, http.MethodGet, "/")
+	assert.Equal(t, http.StatusTooManyRequests, PerformRequest(router, http.MethodGet, "/").Code)
+
+	now = now.Add(time.Second)
+	w := PerformRequest(router, http.MethodGet, "/")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	now = now.Add(time.Second)
+	assert.Equal(t, http.StatusOK, PerformRequest(router, http.MethodGet, "/").Code)
+	assert.Equal(t, http.StatusTooManyRequests, PerformRequest(router, http.MethodGet, "/").Code)
+
+	// the bucket never holds more than the burst
+	now = now.Add(time.Hour)
+	for _, code := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		assert.Equal(t, code, PerformRequest(router, http.MethodGet, "/").Code)
+	}
+}
+
+func TestMemoryRateLimitStoreSweep(t *testing.T) {
+	now := time.Now()
+	store := NewMemoryRateLimitStore()
+	store.now = func() time.Time { return now }
+
+	store.Take("a", 1, 10)
+	now = now.Add(time.Minute)
+	store.Take("b", 1, 100)
+	assert.Len(t, store.buckets, 1)
+	assert.Contains(t, store.buckets, "b")
+
+	assert.Panics(t, func() { RateLimit(RateLimitConfig{}) })
+}
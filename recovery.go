@@ -56,46 +56,88 @@ func CustomRecoveryWithWriter(out io.Writer, handle RecoveryFunc) HandlerFunc {
 	return func(c *Context) {
 		defer func() {
 			if err := recover(); err != nil {
-				// Check for a broken connection, as it is not really a
-				// condition that warrants a panic stack trace.
-				var brokenPipe bool
-				if ne, ok := err.(*net.OpError); ok {
-					var se *os.SyscallError
-					if errors.As(ne, &se) {
-						seStr := strings.ToLower(se.Error())
-						if strings.Contains(seStr, "broken pipe") ||
-							strings.Contains(seStr, "connection reset by peer") {
-							brokenPipe = true
-						}
-					}
+				handlePanic(c, logger, handle, err)
+			}
+		}()
+		c.Next()
+	}
+}
+
+// handlePanic logs err, a recovered panic, to logger if not nil and calls handle, unless
+// the connection is broken. It must be called by the deferred function that recovered err.
+func handlePanic(c *Context, logger *log.Logger, handle RecoveryFunc, err any) {
+	// Check for a broken connection, as it is not really a
+	// condition that warrants a panic stack trace.
+	brokenPipe := isBrokenPipe(err)
+	if logger != nil {
+		stack := stack(4)
+		httpRequest, _ := httputil.DumpRequest(c.Request, false)
+		headers := strings.Split(string(httpRequest), "\r\n")
+		for idx, header := range headers {
+			current := strings.Split(header, ":")
+			if current[0] == "Authorization" {
+				headers[idx] = current[0] + ": *"
+			}
+		}
+		headersToStr := strings.Join(headers, "\r\n")
+		if brokenPipe {
+			logger.Printf("%s\n%s%s", err, headersToStr, reset)
+		} else if IsDebugging() {
+			logger.Printf("[Recovery] %s panic recovered:\n%s\n%s\n%s%s",
+				timeFormat(time.Now()), headersToStr, err, stack, reset)
+		} else {
+			logger.Printf("[Recovery] %s panic recovered:\n%s\n%s%s",
+				timeFormat(time.Now()), err, stack, reset)
+		}
+	}
+	if brokenPipe {
+		// If the connection is dead, we can't write a status to it.
+		c.Error(err.(error)) //nolint: errcheck
+		c.Abort()
+	} else {
+		handle(c, err)
+	}
+}
+
+// RecoveryMapperFunc translates a recovered panic into a response. handled is false for
+// the panics it does not know.
+type RecoveryMapperFunc func(recovered any) (status int, body any, handled bool)
+
+// RecoveryWithMapper returns a middleware that recovers from any panics and passes them to
+// mapper. A handled panic aborts with the status and, unless nil, the body rendered as JSON
+// with the package encoder; it is not logged, and is attached to the Context if it is an
+// error. Other panics are handled like Recovery does: the stack is written to
+// DefaultErrorWriter and the request aborted with 500.
+//
+//	router.Use(gin.RecoveryWithMapper(func(recovered any) (int, any, bool) {
+//		if err, ok := recovered.(validationPanic); ok {
+//			return http.StatusUnprocessableEntity, gin.H{"error": err.Error()}, true
+//		}
+//		return 0, nil, false
+//	}))
+func RecoveryWithMapper(mapper RecoveryMapperFunc) HandlerFunc {
+	var logger *log.Logger
+	if DefaultErrorWriter != nil {
+		logger = log.New(DefaultErrorWriter, "\n\n\x1b[31m", log.LstdFlags)
+	}
+	return func(c *Context) {
+		defer func() {
+			if err := recover(); err != nil {
+				status, body, handled := mapper(err)
+				if !handled {
+					handlePanic(c, logger, defaultHandleRecovery, err)
+					return
 				}
-				if logger != nil {
-					stack := stack(3)
-					httpRequest, _ := httputil.DumpRequest(c.Request, false)
-					headers := strings.Split(string(httpRequest), "\r\n")
-					for idx, header := range headers {
-						current := strings.Split(header, ":")
-						if current[0] == "Authorization" {
-							headers[idx] = current[0] + ": *"
-						}
-					}
-					headersToStr := strings.Join(headers, "\r\n")
-					if brokenPipe {
-						logger.Printf("%s\n%s%s", err, headersToStr, reset)
-					} else if IsDebugging() {
-						logger.Printf("[Recovery] %s panic recovered:\n%s\n%s\n%s%s",
-							timeFormat(time.Now()), headersToStr, err, stack, reset)
-					} else {
-						logger.Printf("[Recovery] %s panic recovered:\n%s\n%s%s",
-							timeFormat(time.Now()), err, stack, reset)
-					}
+				if e, ok := err.(error); ok {
+					c.Error(e) //nolint: errcheck
 				}
-				if brokenPipe {
-					// If the connection is dead, we can't write a status to it.
-					c.Error(err.(error)) //nolint: errcheck
+				switch {
+				case c.Writer.Written():
 					c.Abort()
-				} else {
-					handle(c, err)
+				case body == nil:
+					c.AbortWithStatus(status)
+				default:
+					c.AbortWithStatusJSON(status, body)
 				}
 			}
 		}()
@@ -103,6 +145,57 @@ func CustomRecoveryWithWriter(out io.Writer, handle RecoveryFunc) HandlerFunc {
 	}
 }
 
+// RecoveryLogFunc receives a recovered panic and its stack trace.
+type RecoveryLogFunc func(c *Context, err any, stack []byte)
+
+// RecoveryJSON returns a middleware that recovers from any panics and aborts with 500 and
+// a {"error":"internal","request_id":"..."} JSON body, rendered with the package JSON encoder.
+// The request id is read from the Context key Engine.RequestIDKey, then the X-Request-Id
+// response header, then request header.
+// The panic and its stack are passed to logger, or written to DefaultErrorWriter if logger
+// is nil; the stack is never sent to the client.
+func RecoveryJSON(logger RecoveryLogFunc) HandlerFunc {
+	if logger == nil {
+		logger = func(c *Context, err any, stack []byte) {
+			fmt.Fprintf(DefaultErrorWriter, "[Recovery] %s panic recovered:\n%s\n%s\n",
+				timeFormat(time.Now()), err, stack)
+		}
+	}
+	return func(c *Context) {
+		defer func() {
+			if err := recover(); err != nil {
+				logger(c, err, stack(3))
+				if isBrokenPipe(err) || c.Writer.Written() {
+					// If the connection is dead or the body started, we can't write JSON to it.
+					c.Abort()
+					return
+				}
+				body := H{"error": "internal"}
+				if id := requestID(c); id != "" {
+					body["request_id"] = id
+				}
+				c.AbortWithStatusJSON(http.StatusInternalServerError, body)
+			}
+		}()
+		c.Next()
+	}
+}
+
+// isBrokenPipe reports whether the recovered value is a broken connection error.
+func isBrokenPipe(err any) bool {
+	if ne, ok := err.(*net.OpError); ok {
+		var se *os.SyscallError
+		if errors.As(ne, &se) {
+			seStr := strings.ToLower(se.Error())
+			if strings.Contains(seStr, "broken pipe") ||
+				strings.Contains(seStr, "connection reset by peer") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func defaultHandleRecovery(c *Context, _ any) {
 	c.AbortWithStatus(http.StatusInternalServerError)
 }
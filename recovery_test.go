@@ -246,3 +246,110 @@ func TestRecoveryWithWriterWithCustomRecovery(t *testing.T) {
 
 	SetMode(TestMode)
 }
+
+func TestRecoveryJSON(t *testing.T) {
+	var (
+		logged      any
+		loggedStack []byte
+		after       bool
+	)
+	router := New()
+	router.Use(RecoveryJSON(func(c *Context, err any, stack []byte) {
+		logged = err
+		loggedStack = stack
+	}))
+	router.GET("/recovery", func(c *Context) {
+		panic("Oupps, Houston, we have a problem")
+	}, func(c *Context) {
+		after = true
+	})
+
+	w := PerformRequest(router, "GET", "/recovery", header{Key: "X-Request-Id", Value: "req-1"})
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"error":"internal","request_id":"req-1"}`, w.Body.String())
+	assert.Equal(t, "Oupps, Houston, we have a problem", logged)
+	assert.Contains(t, string(loggedStack), "recovery_test.go")
+	assert.False(t, after)
+
+	w = PerformRequest(router, "GET", "/recovery")
+	assert.Equal(t, `{"error":"internal"}`, w.Body.String())
+}
+
+func TestRecoveryJSONDefaultLogger(t *testing.T) {
+	buffer := new(strings.Builder)
+	defaultErrorWriter := DefaultErrorWriter
+	DefaultErrorWriter = buffer
+	defer func() { DefaultErrorWriter = defaultErrorWriter }()
+
+	router := New()
+	router.Use(RecoveryJSON(nil))
+	router.GET("/recovery", func(c *Context) {
+		c.String(http.StatusOK, "partial")
+		panic("late panic")
+	})
+
+	w := PerformRequest(router, "GET", "/recovery")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "partial", w.Body.String())
+	assert.Contains(t, buffer.String(), "panic recovered")
+	assert.Contains(t, buffer.String(), "late panic")
+}
+
+type validationPanic struct {
+	Field string
+}
+
+func (p validationPanic) Error() string {
+	return "invalid " + p.Field
+}
+
+func TestRecoveryWithMapper(t *testing.T) {
+	buffer := new(strings.Builder)
+	defaultErrorWriter := DefaultErrorWriter
+	DefaultErrorWriter = buffer
+	defer func() { DefaultErrorWriter = defaultErrorWriter }()
+
+	var errs []string
+	router := New()
+	router.Use(func(c *Context) {
+		c.Next()
+		errs = c.Errors.Errors()
+	})
+	router.Use(RecoveryWithMapper(func(recovered any) (int, any, bool) {
+		switch p := recovered.(type) {
+		case validationPanic:
+			return http.StatusUnprocessableEntity, H{"error": p.Error()}, true
+		case int:
+			return p, nil, true
+		}
+		return 0, nil, false
+	}))
+	router.GET("/mapped", func(c *Context) {
+		panic(validationPanic{Field: "name"})
+	})
+	router.GET("/status", func(c *Context) {
+		panic(http.StatusTeapot)
+	})
+	router.GET("/unmapped", func(c *Context) {
+		panic("oops")
+	})
+
+	w := PerformRequest(router, "GET", "/mapped")
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, `{"error":"invalid name"}`, w.Body.String())
+	assert.Equal(t, []string{"invalid name"}, errs)
+	assert.Empty(t, buffer.String())
+
+	w = PerformRequest(router, "GET", "/status")
+	assert.Equal(t, http.StatusTeapot, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	w = PerformRequest(router, "GET", "/unmapped")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, buffer.String(), "panic recovered")
+	assert.Contains(t, buffer.String(), "oops")
+	assert.Contains(t, buffer.String(), t.Name())
+}
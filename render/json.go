@@ -46,6 +46,27 @@ type PureJSON struct {
 	Data any
 }
 
+// HexJSON contains the given interface object, its int64 values are rendered as hex strings.
+type HexJSON struct {
+	Data any
+}
+
+// DecimalJSON contains the given interface object, its hexstring fields are rendered as numbers.
+type DecimalJSON struct {
+	Data any
+}
+
+// JSONMarshaler marshals a value to JSON, a jsoniter.API satisfies it.
+type JSONMarshaler interface {
+	Marshal(v any) ([]byte, error)
+}
+
+// CustomJSON contains the given interface object and the marshaler used to encode it.
+type CustomJSON struct {
+	Marshaler JSONMarshaler
+	Data      any
+}
+
 var (
 	jsonContentType      = []string{"application/json; charset=utf-8"}
 	jsonpContentType     = []string{"application/javascript; charset=utf-8"}
@@ -73,6 +94,56 @@ func WriteJSON(w http.ResponseWriter, obj any) error {
 	return err
 }
 
+// Render (HexJSON) marshals the given interface object with every int64 as a hex string
+// and writes it with custom ContentType.
+func (r HexJSON) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	jsonBytes, err := json.MarshalHex(r.Data)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(jsonBytes)
+	return err
+}
+
+// WriteContentType (HexJSON) writes JSON ContentType.
+func (r HexJSON) WriteContentType(w http.ResponseWriter) {
+	writeContentType(w, jsonContentType)
+}
+
+// Render (DecimalJSON) marshals the given interface object with hexstring fields as numbers
+// and writes it with custom ContentType.
+func (r DecimalJSON) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	jsonBytes, err := json.MarshalDecimal(r.Data)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(jsonBytes)
+	return err
+}
+
+// WriteContentType (DecimalJSON) writes JSON ContentType.
+func (r DecimalJSON) WriteContentType(w http.ResponseWriter) {
+	writeContentType(w, jsonContentType)
+}
+
+// Render (CustomJSON) marshals the given interface object with its marshaler and writes it with custom ContentType.
+func (r CustomJSON) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	jsonBytes, err := r.Marshaler.Marshal(r.Data)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(jsonBytes)
+	return err
+}
+
+// WriteContentType (CustomJSON) writes JSON ContentType.
+func (r CustomJSON) WriteContentType(w http.ResponseWriter) {
+	writeContentType(w, jsonContentType)
+}
+
 // Render (IndentedJSON) marshals the given interface object and writes it with custom ContentType.
 func (r IndentedJSON) Render(w http.ResponseWriter) error {
 	r.WriteContentType(w)
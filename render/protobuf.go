@@ -2,6 +2,8 @@
 // Use of this source code is governed by a MIT style
 // license that can be found in the LICENSE file.
 
+//go:build !noprotobuf
+
 package render
 
 import (
@@ -10,6 +12,9 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
+// Check interface implemented here to support go build tag noprotobuf.
+var _ Render = ProtoBuf{}
+
 // ProtoBuf contains the given interface object.
 type ProtoBuf struct {
 	Data any
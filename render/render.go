@@ -16,6 +16,10 @@ type Render interface {
 
 var (
 	_ Render     = JSON{}
+	_ Render     = CustomJSON{}
+	_ Render     = HexJSON{}
+	_ Render     = DecimalJSON{}
+	_ Render     = SSE{}
 	_ Render     = IndentedJSON{}
 	_ Render     = SecureJSON{}
 	_ Render     = JsonpJSON{}
@@ -29,7 +33,6 @@ var (
 	_ Render     = YAML{}
 	_ Render     = Reader{}
 	_ Render     = AsciiJSON{}
-	_ Render     = ProtoBuf{}
 	_ Render     = TOML{}
 )
 
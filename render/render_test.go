@@ -14,11 +14,11 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin/internal/json"
-	testdata "github.com/gin-gonic/gin/testdata/protoexample"
+	jsoniter "github.com/json-iterator/go"
 	"github.com/stretchr/testify/assert"
-	"google.golang.org/protobuf/proto"
 )
 
 // TODO unit tests
@@ -49,6 +49,93 @@ func TestRenderJSONError(t *testing.T) {
 	assert.Error(t, (JSON{data}).Render(w))
 }
 
+type nonFlushWriter struct {
+	http.ResponseWriter
+}
+
+func TestRenderSSE(t *testing.T) {
+	w := httptest.NewRecorder()
+	type update struct {
+		ID   int64  `json:"id"`
+		Text string `json:"text"`
+	}
+
+	assert.NoError(t, (SSE{"update", update{255, "a\nb"}}).Render(w))
+	assert.NoError(t, (SSE{"", "done"}).Render(w))
+
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+	assert.Equal(t, "keep-alive", w.Header().Get("Connection"))
+	assert.True(t, w.Flushed)
+	events := strings.Split(w.Body.String(), "\n\n")
+	assert.Equal(t, []string{`event:update` + "\n" + `data:{"id":255,"text":"a\nb"}`, `data:"done"`, ""}, events)
+
+	err := (SSE{"update", 1}).Render(nonFlushWriter{httptest.NewRecorder()})
+	assert.ErrorIs(t, err, ErrSSENotFlusher)
+
+	err = (SSE{"update", make(chan int)}).Render(httptest.NewRecorder())
+	assert.Error(t, err)
+}
+
+func TestRenderDecimalJSON(t *testing.T) {
+	w := httptest.NewRecorder()
+	data := struct {
+		ID int64 `json:"id,hexstring"`
+	}{255}
+
+	(DecimalJSON{data}).WriteContentType(w)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+	err := (DecimalJSON{data}).Render(w)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":255}`, w.Body.String())
+}
+
+func TestRenderHexJSON(t *testing.T) {
+	w := httptest.NewRecorder()
+	data := struct {
+		ID    int64         `json:"id"`
+		IDs   []int64       `json:"ids"`
+		Count int           `json:"count"`
+		Wait  time.Duration `json:"wait"`
+	}{ID: 255, IDs: []int64{1, -1}, Count: 2, Wait: 3}
+
+	(HexJSON{data}).WriteContentType(w)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+	err := (HexJSON{data}).Render(w)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":"00000000000000ff","ids":["0000000000000001","-000000000000001"],"count":2,"wait":3}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	err = (HexJSON{map[string]any{"id": int64(16)}}).Render(w)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":"0000000000000010"}`, w.Body.String())
+}
+
+type errorMarshaler struct{}
+
+func (errorMarshaler) Marshal(any) ([]byte, error) {
+	return nil, errors.New("marshal error")
+}
+
+func TestRenderCustomJSON(t *testing.T) {
+	w := httptest.NewRecorder()
+	data := []string{"<b>"}
+
+	err := (CustomJSON{Marshaler: jsoniter.Config{EscapeHTML: true}.Froze(), Data: data}).Render(w)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "[\"\\u003cb\\u003e\"]", w.Body.String())
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+	w = httptest.NewRecorder()
+	err = (CustomJSON{Marshaler: errorMarshaler{}, Data: data}).Render(w)
+	assert.Error(t, err)
+}
+
 func TestRenderIndentedJSON(t *testing.T) {
 	w := httptest.NewRecorder()
 	data := map[string]any{
@@ -322,35 +409,6 @@ func TestRenderTOMLFail(t *testing.T) {
 	assert.Error(t, err)
 }
 
-// test Protobuf rendering
-func TestRenderProtoBuf(t *testing.T) {
-	w := httptest.NewRecorder()
-	reps := []int64{int64(1), int64(2)}
-	label := "test"
-	data := &testdata.Test{
-		Label: &label,
-		Reps:  reps,
-	}
-
-	(ProtoBuf{data}).WriteContentType(w)
-	protoData, err := proto.Marshal(data)
-	assert.NoError(t, err)
-	assert.Equal(t, "application/x-protobuf", w.Header().Get("Content-Type"))
-
-	err = (ProtoBuf{data}).Render(w)
-
-	assert.NoError(t, err)
-	assert.Equal(t, string(protoData), w.Body.String())
-	assert.Equal(t, "application/x-protobuf", w.Header().Get("Content-Type"))
-}
-
-func TestRenderProtoBufFail(t *testing.T) {
-	w := httptest.NewRecorder()
-	data := &testdata.Test{}
-	err := (ProtoBuf{data}).Render(w)
-	assert.Error(t, err)
-}
-
 func TestRenderXML(t *testing.T) {
 	w := httptest.NewRecorder()
 	data := xmlmap{
@@ -0,0 +1,60 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package render
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin/internal/json"
+)
+
+// ErrSSENotFlusher is returned by SSE when the writer can not be flushed.
+var ErrSSENotFlusher = errors.New("render: SSE requires an http.Flusher")
+
+// SSE contains a Server-Sent Event whose data is always JSON, encoded with the
+// package encoder so hexstring fields apply.
+type SSE struct {
+	Event string
+	Data  any
+}
+
+var (
+	sseContentType = []string{sse.ContentType}
+	sseNoCache     = []string{"no-cache"}
+	sseKeepAlive   = []string{"keep-alive"}
+)
+
+// Render (SSE) writes the event and flushes it.
+func (r SSE) Render(w http.ResponseWriter) error {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return ErrSSENotFlusher
+	}
+	data, err := json.Marshal(r.Data)
+	if err != nil {
+		return err
+	}
+	r.WriteContentType(w)
+	if err = sse.Encode(w, sse.Event{Event: r.Event, Data: string(data)}); err != nil {
+		return err
+	}
+	flusher.Flush()
+	return nil
+}
+
+// WriteContentType (SSE) writes the event stream ContentType, and the Cache-Control
+// and Connection headers unless they are already set.
+func (r SSE) WriteContentType(w http.ResponseWriter) {
+	header := w.Header()
+	header["Content-Type"] = sseContentType
+	if _, exist := header["Cache-Control"]; !exist {
+		header["Cache-Control"] = sseNoCache
+	}
+	if _, exist := header["Connection"]; !exist {
+		header["Connection"] = sseKeepAlive
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// RequestIDHeader is the header RequestID reads and writes the request id in, unless
+// RequestIDHeaderName is given.
+const RequestIDHeader = "X-Request-Id"
+
+// maxRequestIDLength is the length of the longest incoming request id RequestID accepts.
+const maxRequestIDLength = 128
+
+// RequestIDOption configures the RequestID middleware.
+type RequestIDOption func(*requestIDConfig)
+
+type requestIDConfig struct {
+	header   string
+	key      string
+	generate func() string
+	valid    func(string) bool
+}
+
+// RequestIDHeaderName sets the header the request id is read from and echoed in.
+func RequestIDHeaderName(name string) RequestIDOption {
+	return func(conf *requestIDConfig) {
+		conf.header = name
+	}
+}
+
+// RequestIDContextKey sets the Context key the request id is stored under, instead of
+// Engine.RequestIDKey. Context.Fail and LoggerJSON still find it in the response header.
+func RequestIDContextKey(key string) RequestIDOption {
+	return func(conf *requestIDConfig) {
+		conf.key = key
+	}
+}
+
+// RequestIDGenerator sets the function generating the id of the requests without a valid one.
+func RequestIDGenerator(generate func() string) RequestIDOption {
+	return func(conf *requestIDConfig) {
+		conf.generate = generate
+	}
+}
+
+// RequestIDValidator sets the function reporting whether an incoming request id is kept.
+func RequestIDValidator(valid func(id string) bool) RequestIDOption {
+	return func(conf *requestIDConfig) {
+		conf.valid = valid
+	}
+}
+
+// RequestID returns a middleware that gives each request an id: the X-Request-Id request
+// header when it is sane, at most 128 letters, digits and "-_.:+/=@", or else a new one,
+// 16 random bytes hex encoded. The id is set in the request header, so it is passed on to
+// the upstream of a proxy, stored in the Context under Engine.RequestIDKey, for
+// c.GetString, and echoed in the response header.
+func RequestID(opts ...RequestIDOption) HandlerFunc {
+	conf := requestIDConfig{
+		header:   RequestIDHeader,
+		generate: newRequestID,
+		valid:    validRequestID,
+	}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	return func(c *Context) {
+		id := c.requestHeader(conf.header)
+		if !conf.valid(id) {
+			id = conf.generate()
+			c.Request.Header.Set(conf.header, id)
+		}
+		key := conf.key
+		if key == "" && c.engine != nil {
+			key = c.engine.RequestIDKey
+		}
+		if key == "" {
+			key = DefaultRequestIDKey
+		}
+		c.Set(key, id)
+		c.Header(conf.header, id)
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b[:])
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		switch c := id[i]; {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':', c == '+', c == '/', c == '=', c == '@':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDPreserved(t *testing.T) {
+	router := New()
+	router.Use(RequestID())
+	router.GET("/", func(c *Context) {
+		c.String(http.StatusOK, c.GetString(c.engine.RequestIDKey)+" "+c.GetHeader(RequestIDHeader))
+	})
+	router.GET("/fail", func(c *Context) {
+		c.Fail(http.StatusConflict, "conflict")
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/", header{Key: RequestIDHeader, Value: "0af7651916cd43dd8448eb211c80319c"})
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c 0af7651916cd43dd8448eb211c80319c", w.Body.String())
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", w.Header().Get(RequestIDHeader))
+
+	w = PerformRequest(router, http.MethodGet, "/fail", header{Key: RequestIDHeader, Value: "req-1"})
+	assert.Equal(t, `{"code":409,"message":"conflict","request_id":"req-1"}`, w.Body.String())
+}
+
+func TestRequestIDGenerated(t *testing.T) {
+	router := New()
+	router.Use(RequestID())
+	router.GET("/", func(c *Context) {
+		c.String(http.StatusOK, c.GetString(c.engine.RequestIDKey)+" "+c.GetHeader(RequestIDHeader))
+	})
+	hexID := regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+	w := PerformRequest(router, http.MethodGet, "/")
+	id := w.Header().Get(RequestIDHeader)
+	assert.Regexp(t, hexID, id)
+	assert.Equal(t, id+" "+id, w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/")
+	assert.Regexp(t, hexID, w.Header().Get(RequestIDHeader))
+	assert.NotEqual(t, id, w.Header().Get(RequestIDHeader))
+
+	// insane ids are replaced
+	for _, invalid := range []string{"a b", "<script>", "id\x00", strings.Repeat("a", 129)} {
+		w = PerformRequest(router, http.MethodGet, "/", header{Key: RequestIDHeader, Value: invalid})
+		assert.Regexp(t, hexID, w.Header().Get(RequestIDHeader), invalid)
+	}
+}
+
+func TestRequestIDOptions(t *testing.T) {
+	router := New()
+	router.Use(RequestID(
+		RequestIDHeaderName("X-Correlation-Id"),
+		RequestIDContextKey("correlation_id"),
+		RequestIDGenerator(func() string { return "generated" }),
+		RequestIDValidator(func(id string) bool { return strings.HasPrefix(id, "corr-") }),
+	))
+	router.GET("/", func(c *Context) {
+		c.String(http.StatusOK, c.GetString("correlation_id"))
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/", header{Key: "X-Correlation-Id", Value: "corr-7"})
+	assert.Equal(t, "corr-7", w.Body.String())
+	assert.Equal(t, "corr-7", w.Header().Get("X-Correlation-Id"))
+	assert.Empty(t, w.Header().Get(RequestIDHeader))
+
+	w = PerformRequest(router, http.MethodGet, "/", header{Key: "X-Correlation-Id", Value: "7"})
+	assert.Equal(t, "generated", w.Body.String())
+}
@@ -0,0 +1,59 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// LimitRequestLine returns a middleware that aborts the requests whose URL is longer than
+// maxURL bytes with 414 Request-URI Too Long, and those whose headers, Host included, take
+// more than maxHeaderBytes with 431 Request Header Fields Too Large, both with a
+// {"error": "..."} JSON body. Each header line counts as its name, its value and 4 bytes
+// for ": " and "\r\n". A limit that is zero or negative is not enforced.
+// It is meant to run first, before the rest of the chain does any work; the server still
+// bounds what it reads with http.Server.MaxHeaderBytes.
+func LimitRequestLine(maxURL, maxHeaderBytes int) HandlerFunc {
+	return func(c *Context) {
+		if maxURL > 0 {
+			if n := requestURILength(c.Request); n > maxURL {
+				c.AbortWithStatusJSON(http.StatusRequestURITooLong,
+					H{"error": fmt.Sprintf("request URI of %d bytes is over the %d bytes limit", n, maxURL)})
+				return
+			}
+		}
+		if maxHeaderBytes > 0 {
+			if n := requestHeaderLength(c.Request); n > maxHeaderBytes {
+				c.AbortWithStatusJSON(http.StatusRequestHeaderFieldsTooLarge,
+					H{"error": fmt.Sprintf("request headers of %d bytes are over the %d bytes limit", n, maxHeaderBytes)})
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// requestURILength returns the length of the request target as received, or of the URL
+// for requests built by hand.
+func requestURILength(req *http.Request) int {
+	if req.RequestURI != "" {
+		return len(req.RequestURI)
+	}
+	return len(req.URL.String())
+}
+
+func requestHeaderLength(req *http.Request) int {
+	n := 0
+	if req.Host != "" {
+		n += len("Host") + len(req.Host) + 4
+	}
+	for name, values := range req.Header {
+		for _, value := range values {
+			n += len(name) + len(value) + 4
+		}
+	}
+	return n
+}
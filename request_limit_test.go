@@ -0,0 +1,47 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitRequestLine(t *testing.T) {
+	router := New()
+	router.Use(LimitRequestLine(64, 128))
+	router.GET("/search", func(c *Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/search?q=gin", header{Key: "Accept", Value: "*/*"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = PerformRequest(router, http.MethodGet, "/search?q="+strings.Repeat("a", 60))
+	assert.Equal(t, http.StatusRequestURITooLong, w.Code)
+	assert.Equal(t, `{"error":"request URI of 70 bytes is over the 64 bytes limit"}`, w.Body.String())
+
+	// "Host: example.com\r\n" and "Cookie: ...\r\n"
+	w = PerformRequest(router, http.MethodGet, "/search", header{Key: "Cookie", Value: strings.Repeat("c", 100)})
+	assert.Equal(t, http.StatusRequestHeaderFieldsTooLarge, w.Code)
+	assert.Equal(t, `{"error":"request headers of 129 bytes are over the 128 bytes limit"}`, w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/search", header{Key: "Cookie", Value: strings.Repeat("c", 99)})
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestLimitRequestLineDisabled(t *testing.T) {
+	router := New()
+	router.Use(LimitRequestLine(0, -1))
+	router.GET("/", func(c *Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/?q="+strings.Repeat("a", 10000), header{Key: "Cookie", Value: strings.Repeat("c", 10000)})
+	assert.Equal(t, http.StatusOK, w.Code)
+}
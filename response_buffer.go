@@ -0,0 +1,160 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+)
+
+// BufferResponse returns a middleware that holds back the response of the rest of the
+// handler chain until the chain returns. Middleware registered after it can then read the
+// whole body with Context.ResponseBody once c.Next() returned, and still set headers, e.g.
+// a signature computed over the body:
+//
+//	router.Use(gin.BufferResponse(), func(c *gin.Context) {
+//		c.Next()
+//		c.Header("X-Signature", sign(c.ResponseBody()))
+//	})
+//
+// Flushing the writer, as Context.Stream and Context.SSEvent do, opts out: what was buffered
+// is sent at once and the rest of the response is written through.
+func BufferResponse() HandlerFunc {
+	return func(c *Context) {
+		w := c.Writer
+		if w.Written() {
+			c.Next()
+			return
+		}
+		bw := &bufferWriter{ResponseWriter: w, status: w.Status(), size: noWritten}
+		c.Writer = bw
+		defer func() {
+			c.Writer = w
+		}()
+		c.Next()
+		if !bw.streaming {
+			bw.writeTo(w)
+		}
+	}
+}
+
+// ResponseBody returns the response body written so far behind BufferResponse.
+// It returns nil without BufferResponse, or once the response was flushed.
+func (c *Context) ResponseBody() []byte {
+	if bw, ok := c.Writer.(*bufferWriter); ok && !bw.streaming {
+		return bw.buf.Bytes()
+	}
+	return nil
+}
+
+// bufferWriter buffers the response of the chain run by BufferResponse.
+type bufferWriter struct {
+	ResponseWriter
+
+	buf       bytes.Buffer
+	status    int
+	size      int
+	streaming bool
+}
+
+var _ ResponseWriter = (*bufferWriter)(nil)
+
+func (bw *bufferWriter) WriteHeader(code int) {
+	if bw.streaming {
+		bw.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if code > 0 && bw.status != code {
+		if bw.Written() {
+			debugPrint("[WARNING] Headers were already written. Wanted to override status code %d with %d", bw.status, code)
+			return
+		}
+		bw.status = code
+	}
+}
+
+func (bw *bufferWriter) WriteHeaderNow() {
+	if bw.streaming {
+		bw.ResponseWriter.WriteHeaderNow()
+		return
+	}
+	if !bw.Written() {
+		bw.size = 0
+	}
+}
+
+func (bw *bufferWriter) Write(data []byte) (int, error) {
+	if bw.streaming {
+		return bw.ResponseWriter.Write(data)
+	}
+	bw.WriteHeaderNow()
+	n, err := bw.buf.Write(data)
+	bw.size += n
+	return n, err
+}
+
+func (bw *bufferWriter) WriteString(s string) (int, error) {
+	if bw.streaming {
+		return bw.ResponseWriter.WriteString(s)
+	}
+	bw.WriteHeaderNow()
+	n, err := bw.buf.WriteString(s)
+	bw.size += n
+	return n, err
+}
+
+func (bw *bufferWriter) Status() int {
+	if bw.streaming {
+		return bw.ResponseWriter.Status()
+	}
+	return bw.status
+}
+
+func (bw *bufferWriter) Size() int {
+	if bw.streaming {
+		return bw.ResponseWriter.Size()
+	}
+	return bw.size
+}
+
+func (bw *bufferWriter) Written() bool {
+	if bw.streaming {
+		return bw.ResponseWriter.Written()
+	}
+	return bw.size != noWritten
+}
+
+// Flush sends the buffered response and stops buffering.
+func (bw *bufferWriter) Flush() {
+	bw.stream()
+	bw.ResponseWriter.Flush()
+}
+
+// Hijack stops buffering, the buffered response is dropped.
+func (bw *bufferWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	bw.streaming = true
+	return bw.ResponseWriter.Hijack()
+}
+
+func (bw *bufferWriter) stream() {
+	if !bw.streaming {
+		bw.streaming = true
+		bw.writeTo(bw.ResponseWriter)
+	}
+}
+
+// writeTo sends the buffered response to w.
+func (bw *bufferWriter) writeTo(w ResponseWriter) {
+	w.WriteHeader(bw.status)
+	if bw.size == noWritten {
+		return
+	}
+	w.WriteHeaderNow()
+	if bw.buf.Len() > 0 {
+		w.Write(bw.buf.Bytes()) //nolint: errcheck
+	}
+	bw.buf.Reset()
+}
@@ -0,0 +1,107 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func signBody(body []byte) string {
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestBufferResponseSignature(t *testing.T) {
+	var signed []byte
+	router := New()
+	router.Use(BufferResponse(), func(c *Context) {
+		c.Next()
+		signed = append(signed, c.ResponseBody()...)
+		c.Header("X-Signature", signBody(c.ResponseBody()))
+	})
+	router.GET("/", func(c *Context) {
+		c.JSON(http.StatusCreated, H{"id": 1})
+		assert.True(t, c.Writer.Written())
+		assert.Equal(t, http.StatusCreated, c.Writer.Status())
+		assert.Equal(t, 8, c.Writer.Size())
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/")
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `{"id":1}`, w.Body.String())
+	assert.Equal(t, w.Body.String(), string(signed))
+	assert.Equal(t, signBody(w.Body.Bytes()), w.Header().Get("X-Signature"))
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+}
+
+func TestBufferResponseStatusOnly(t *testing.T) {
+	router := New()
+	router.Use(BufferResponse(), func(c *Context) {
+		c.Next()
+		assert.Empty(t, c.ResponseBody())
+		c.Header("X-After", "1")
+	})
+	router.GET("/", func(c *Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/")
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "1", w.Header().Get("X-After"))
+}
+
+func TestBufferResponseStreamingOptsOut(t *testing.T) {
+	router := New()
+	router.Use(BufferResponse(), func(c *Context) {
+		c.Next()
+		assert.Nil(t, c.ResponseBody())
+		c.Header("X-After", "1")
+	})
+	router.GET("/", func(c *Context) {
+		c.Header("X-Before", "1")
+		c.String(http.StatusOK, "a")
+		assert.False(t, c.Writer.(*bufferWriter).ResponseWriter.Written())
+		n := 0
+		c.Stream(func(w io.Writer) bool {
+			n++
+			_, err := w.Write([]byte("b"))
+			assert.NoError(t, err)
+			return n < 2
+		})
+		assert.True(t, c.Writer.(*bufferWriter).ResponseWriter.Written())
+	})
+
+	w := CreateTestResponseRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "abb", w.Body.String())
+	assert.True(t, w.Flushed)
+	assert.Equal(t, "1", w.Header().Get("X-Before"))
+	// set after the headers were sent
+	assert.Empty(t, w.Result().Header.Get("X-After"))
+}
+
+func TestResponseBodyWithoutBuffer(t *testing.T) {
+	router := New()
+	router.GET("/", func(c *Context) {
+		c.String(http.StatusOK, "ok")
+		assert.Nil(t, c.ResponseBody())
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/")
+	assert.Equal(t, "ok", w.Body.String())
+}
@@ -123,6 +123,31 @@ func (w *responseWriter) Flush() {
 	w.ResponseWriter.(http.Flusher).Flush()
 }
 
+// FlushError flushes like Flush and returns the error of the underlying writer, or
+// http.ErrNotSupported if it cannot flush.
+func (w *responseWriter) FlushError() error {
+	w.WriteHeaderNow()
+	return flushError(w.ResponseWriter)
+}
+
+// flushError flushes w the way http.ResponseController does, looking for a FlushError
+// or Flush method through the writers wrapped with an Unwrap method.
+func flushError(w http.ResponseWriter) error {
+	for {
+		switch t := w.(type) {
+		case interface{ FlushError() error }:
+			return t.FlushError()
+		case http.Flusher:
+			t.Flush()
+			return nil
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = t.Unwrap()
+		default:
+			return http.ErrNotSupported
+		}
+	}
+}
+
 func (w *responseWriter) Pusher() (pusher http.Pusher) {
 	if pusher, ok := w.ResponseWriter.(http.Pusher); ok {
 		return pusher
@@ -186,3 +186,37 @@ func TestPusherWithoutPusher(t *testing.T) {
 	pusher := w.Pusher()
 	assert.Nil(t, pusher, "Expected pusher to be nil")
 }
+
+// flushErrorResponseWriter is an http.ResponseWriter whose FlushError fails.
+type flushErrorResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (flushErrorResponseWriter) FlushError() error {
+	return errTestRender
+}
+
+// unwrapResponseWriter wraps an http.ResponseWriter, exposing it with Unwrap only.
+type unwrapResponseWriter struct {
+	w http.ResponseWriter
+}
+
+func (u unwrapResponseWriter) Header() http.Header         { return u.w.Header() }
+func (u unwrapResponseWriter) Write(b []byte) (int, error) { return u.w.Write(b) }
+func (u unwrapResponseWriter) WriteHeader(code int)        { u.w.WriteHeader(code) }
+func (u unwrapResponseWriter) Unwrap() http.ResponseWriter { return u.w }
+
+func TestResponseWriterFlushError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writer := &responseWriter{}
+	writer.reset(unwrapResponseWriter{rec})
+	assert.NoError(t, writer.FlushError())
+	assert.True(t, rec.Flushed)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	writer.reset(unwrapResponseWriter{flushErrorResponseWriter{rec}})
+	assert.Equal(t, errTestRender, writer.FlushError())
+
+	writer.reset(&nonPusherResponseWriter{httptest.NewRecorder()})
+	assert.ErrorIs(t, writer.FlushError(), http.ErrNotSupported)
+}
@@ -50,6 +50,36 @@ type IRoutes interface {
 	StaticFS(string, http.FileSystem) IRoutes
 }
 
+// ResourceController is a controller registered with RouterGroup.Resource.
+// It implements any subset of ResourceIndexer, ResourceShower, ResourceCreator,
+// ResourceUpdater and ResourceDestroyer; only the implemented actions are routed.
+type ResourceController interface{}
+
+// ResourceIndexer handles GET /name.
+type ResourceIndexer interface {
+	Index(*Context)
+}
+
+// ResourceShower handles GET /name/:id.
+type ResourceShower interface {
+	Show(*Context)
+}
+
+// ResourceCreator handles POST /name.
+type ResourceCreator interface {
+	Create(*Context)
+}
+
+// ResourceUpdater handles PUT /name/:id.
+type ResourceUpdater interface {
+	Update(*Context)
+}
+
+// ResourceDestroyer handles DELETE /name/:id.
+type ResourceDestroyer interface {
+	Destroy(*Context)
+}
+
 // RouterGroup is used internally to configure router, a RouterGroup is associated with
 // a prefix and an array of handlers (middleware).
 type RouterGroup struct {
@@ -57,6 +87,12 @@ type RouterGroup struct {
 	basePath string
 	engine   *Engine
 	root     bool
+
+	// host is the host the routes of the group are registered for, nil for any host.
+	host *hostRoutes
+
+	// noMatchHandlers is Handlers without the Engine.UseOnMatch middleware.
+	noMatchHandlers HandlersChain
 }
 
 var _ IRouter = (*RouterGroup)(nil)
@@ -64,16 +100,53 @@ var _ IRouter = (*RouterGroup)(nil)
 // Use adds middleware to the group, see example code in GitHub.
 func (group *RouterGroup) Use(middleware ...HandlerFunc) IRoutes {
 	group.Handlers = append(group.Handlers, middleware...)
+	group.noMatchHandlers = append(group.noMatchHandlers, middleware...)
 	return group.returnObj()
 }
 
+// NoRoute sets the handlers called instead of the engine's NoRoute handlers for unmatched
+// paths under the group's prefix, that is the prefix itself and any path below it. The group
+// middleware runs before them, and they return a 404 code by default. When the prefixes of
+// several groups match, the longest one wins; calling NoRoute again for the same prefix
+// replaces its handlers. 405 responses are not affected.
+func (group *RouterGroup) NoRoute(handlers ...HandlerFunc) {
+	group.engine.addGroupNoRoute(strings.TrimSuffix(group.basePath, "/"), group.combineNoMatchHandlers(handlers))
+}
+
 // Group creates a new router group. You should add all the routes that have common middlewares or the same path prefix.
 // For example, all the routes that use a common middleware for authorization could be grouped.
 func (group *RouterGroup) Group(relativePath string, handlers ...HandlerFunc) *RouterGroup {
+	basePath := group.calculateAbsolutePath(relativePath)
+	assertUniqueParams(basePath)
 	return &RouterGroup{
-		Handlers: group.combineHandlers(handlers),
-		basePath: group.calculateAbsolutePath(relativePath),
-		engine:   group.engine,
+		Handlers:        group.combineHandlers(handlers),
+		basePath:        basePath,
+		engine:          group.engine,
+		host:            group.host,
+		noMatchHandlers: group.combineNoMatchHandlers(handlers),
+	}
+}
+
+// assertUniqueParams panics if two params of path have the same name, e.g. a group
+// "/users/:id" nested in "/orgs/:id": Context.Param would only see the first value.
+func assertUniqueParams(path string) {
+	names := make(map[string]struct{})
+	for rest := path; ; {
+		wildcard, i, valid := findWildcard(rest)
+		if i < 0 || !valid {
+			// invalid wildcards are reported when the route is added
+			return
+		}
+		rest = rest[i+len(wildcard):]
+		name, _, _ := strings.Cut(wildcard[1:], "{")
+		if name == "" {
+			continue
+		}
+		if _, ok := names[name]; ok {
+			panic("param name '" + name + "' is used twice in path '" + path +
+				"', nested groups and their routes must use distinct param names")
+		}
+		names[name] = struct{}{}
 	}
 }
 
@@ -85,8 +158,13 @@ func (group *RouterGroup) BasePath() string {
 
 func (group *RouterGroup) handle(httpMethod, relativePath string, handlers HandlersChain) IRoutes {
 	absolutePath := group.calculateAbsolutePath(relativePath)
+	assertUniqueParams(absolutePath)
 	handlers = group.combineHandlers(handlers)
-	group.engine.addRoute(httpMethod, absolutePath, handlers)
+	if group.host != nil {
+		group.engine.addRouteTo(&group.host.routeTable, httpMethod, absolutePath, handlers)
+	} else {
+		group.engine.addRoute(httpMethod, absolutePath, handlers)
+	}
 	return group.returnObj()
 }
 
@@ -161,6 +239,58 @@ func (group *RouterGroup) Match(methods []string, relativePath string, handlers
 	return group.returnObj()
 }
 
+// Resource registers the REST routes of ctrl under the given name:
+//
+//	GET    /name      Index
+//	GET    /name/:id  Show
+//	POST   /name      Create
+//	PUT    /name/:id  Update
+//	DELETE /name/:id  Destroy
+//
+// Routes are only registered for the actions ctrl implements.
+func (group *RouterGroup) Resource(name string, ctrl ResourceController) IRoutes {
+	itemPath := path.Join(name, ":id")
+	if c, ok := ctrl.(ResourceIndexer); ok {
+		group.handle(http.MethodGet, name, HandlersChain{c.Index})
+	}
+	if c, ok := ctrl.(ResourceShower); ok {
+		group.handle(http.MethodGet, itemPath, HandlersChain{c.Show})
+	}
+	if c, ok := ctrl.(ResourceCreator); ok {
+		group.handle(http.MethodPost, name, HandlersChain{c.Create})
+	}
+	if c, ok := ctrl.(ResourceUpdater); ok {
+		group.handle(http.MethodPut, itemPath, HandlersChain{c.Update})
+	}
+	if c, ok := ctrl.(ResourceDestroyer); ok {
+		group.handle(http.MethodDelete, itemPath, HandlersChain{c.Destroy})
+	}
+
+	return group.returnObj()
+}
+
+// Mount registers every route of sub under relativePath, e.g. a GET /users route of sub is
+// served at GET relativePath/users. The group's middleware runs before the handlers of sub,
+// which already include the middleware sub.Use registered before the route was added.
+// Only routes are copied: NoRoute, NoMethod and the other settings of sub are ignored.
+// A route conflicting with an existing one panics like any duplicate registration.
+func (group *RouterGroup) Mount(relativePath string, sub *Engine) IRoutes {
+	for _, tree := range sub.trees {
+		mountTree(group, relativePath, tree.method, "", tree.root)
+	}
+	return group.returnObj()
+}
+
+func mountTree(group *RouterGroup, relativePath, method, path string, root *node) {
+	path += root.path
+	if len(root.handlers) > 0 {
+		group.handle(method, joinPaths(relativePath, path), root.handlers)
+	}
+	for _, child := range root.children {
+		mountTree(group, relativePath, method, path, child)
+	}
+}
+
 // StaticFile registers a single route in order to serve a single file of the local filesystem.
 // router.StaticFile("favicon.ico", "./resources/favicon.ico")
 func (group *RouterGroup) StaticFile(relativePath, filepath string) IRoutes {
@@ -247,6 +377,20 @@ func (group *RouterGroup) combineHandlers(handlers HandlersChain) HandlersChain
 	return mergedHandlers
 }
 
+// combineNoMatchHandlers is like combineHandlers for the chains that run when no route
+// matched, which leave out the Engine.UseOnMatch middleware.
+func (group *RouterGroup) combineNoMatchHandlers(handlers HandlersChain) HandlersChain {
+	if !group.engine.useOnMatch {
+		return group.combineHandlers(handlers)
+	}
+	finalSize := len(group.noMatchHandlers) + len(handlers)
+	assert1(finalSize < int(abortIndex), "too many handlers")
+	mergedHandlers := make(HandlersChain, finalSize)
+	copy(mergedHandlers, group.noMatchHandlers)
+	copy(mergedHandlers[len(group.noMatchHandlers):], handlers)
+	return mergedHandlers
+}
+
 func (group *RouterGroup) calculateAbsolutePath(relativePath string) string {
 	return joinPaths(group.basePath, relativePath)
 }
@@ -42,6 +42,28 @@ func TestRouterGroupBasicHandle(t *testing.T) {
 	performRequestInGroup(t, http.MethodOptions)
 }
 
+func TestRouterGroupParamCollision(t *testing.T) {
+	router := New()
+	v1 := router.Group("/v1")
+	orgs := v1.Group("/orgs/:id")
+
+	assert.PanicsWithValue(t, "param name 'id' is used twice in path '/v1/orgs/:id/users/:id', "+
+		"nested groups and their routes must use distinct param names", func() {
+		orgs.Group("/users/:id")
+	})
+	assert.Panics(t, func() { orgs.GET("/users/:id", func(c *Context) {}) })
+	assert.Panics(t, func() { orgs.GET("/files/*id", func(c *Context) {}) })
+	assert.Panics(t, func() { orgs.GET(`/users/:id{[0-9]+}`, func(c *Context) {}) })
+	assert.Panics(t, func() { router.GET("/a/:x/b/:x", func(c *Context) {}) })
+
+	users := orgs.Group("/users/:uid")
+	users.GET("/posts/:pid", func(c *Context) {
+		c.String(http.StatusOK, c.Param("id")+" "+c.Param("uid")+" "+c.Param("pid"))
+	})
+	w := PerformRequest(router, http.MethodGet, "/v1/orgs/1/users/2/posts/3")
+	assert.Equal(t, "1 2 3", w.Body.String())
+}
+
 func performRequestInGroup(t *testing.T, method string) {
 	router := New()
 	v1 := router.Group("v1", func(c *Context) {})
@@ -193,3 +215,78 @@ func testRoutesInterface(t *testing.T, r IRoutes) {
 	assert.Equal(t, r, r.Static("/static", "."))
 	assert.Equal(t, r, r.StaticFS("/static2", Dir(".", false)))
 }
+
+type fullResource struct{}
+
+func (fullResource) Index(c *Context)   { c.String(http.StatusOK, "index") }
+func (fullResource) Show(c *Context)    { c.String(http.StatusOK, "show "+c.Param("id")) }
+func (fullResource) Create(c *Context)  { c.String(http.StatusCreated, "create") }
+func (fullResource) Update(c *Context)  { c.String(http.StatusOK, "update "+c.Param("id")) }
+func (fullResource) Destroy(c *Context) { c.String(http.StatusOK, "destroy "+c.Param("id")) }
+
+type readOnlyResource struct{}
+
+func (readOnlyResource) Index(c *Context) { c.String(http.StatusOK, "index") }
+func (readOnlyResource) Show(c *Context)  { c.String(http.StatusOK, "show "+c.Param("id")) }
+
+func TestRouterGroupResource(t *testing.T) {
+	router := New()
+	v1 := router.Group("/v1")
+	assert.Equal(t, v1, v1.Resource("users", fullResource{}))
+	assert.Equal(t, router, router.Resource("/posts", readOnlyResource{}))
+
+	tests := []struct {
+		method, path string
+		code         int
+		body         string
+	}{
+		{http.MethodGet, "/v1/users", http.StatusOK, "index"},
+		{http.MethodGet, "/v1/users/7", http.StatusOK, "show 7"},
+		{http.MethodPost, "/v1/users", http.StatusCreated, "create"},
+		{http.MethodPut, "/v1/users/7", http.StatusOK, "update 7"},
+		{http.MethodDelete, "/v1/users/7", http.StatusOK, "destroy 7"},
+		{http.MethodGet, "/posts", http.StatusOK, "index"},
+		{http.MethodGet, "/posts/3", http.StatusOK, "show 3"},
+		{http.MethodPost, "/posts", http.StatusNotFound, "404 page not found"},
+		{http.MethodDelete, "/posts/3", http.StatusNotFound, "404 page not found"},
+	}
+	for _, tt := range tests {
+		w := PerformRequest(router, tt.method, tt.path)
+		assert.Equal(t, tt.code, w.Code, tt.method+" "+tt.path)
+		assert.Equal(t, tt.body, w.Body.String(), tt.method+" "+tt.path)
+	}
+}
+
+func TestRouterGroupMount(t *testing.T) {
+	sub := New()
+	sub.Use(func(c *Context) { c.Header("X-Sub", "1") })
+	sub.GET("/users/:id", func(c *Context) { c.String(http.StatusOK, "user "+c.Param("id")) })
+	sub.POST("/users", func(c *Context) { c.String(http.StatusCreated, "created") })
+	sub.GET("/files/*path", func(c *Context) { c.String(http.StatusOK, "file "+c.Param("path")) })
+
+	router := New()
+	api := router.Group("/api", func(c *Context) { c.Header("X-Parent", "1") })
+	assert.Equal(t, api, api.Mount("/v1", sub))
+
+	tests := []struct {
+		method, path string
+		code         int
+		body         string
+	}{
+		{http.MethodGet, "/api/v1/users/7", http.StatusOK, "user 7"},
+		{http.MethodPost, "/api/v1/users", http.StatusCreated, "created"},
+		{http.MethodGet, "/api/v1/files/a/b.txt", http.StatusOK, "file /a/b.txt"},
+	}
+	for _, tt := range tests {
+		w := PerformRequest(router, tt.method, tt.path)
+		assert.Equal(t, tt.code, w.Code, tt.method+" "+tt.path)
+		assert.Equal(t, tt.body, w.Body.String(), tt.method+" "+tt.path)
+		assert.Equal(t, "1", w.Header().Get("X-Parent"))
+		assert.Equal(t, "1", w.Header().Get("X-Sub"))
+	}
+	assert.Equal(t, http.StatusNotFound, PerformRequest(router, http.MethodGet, "/users/7").Code)
+
+	assert.Panics(t, func() {
+		router.Group("/api").Mount("/v1", sub)
+	})
+}
@@ -6,10 +6,12 @@ package gin
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -245,6 +247,34 @@ func TestRouteRedirectTrailingSlash(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
 
+func TestRouteRedirectTrailingSlashStatus(t *testing.T) {
+	router := New()
+	router.TrailingSlashRedirectStatus = http.StatusPermanentRedirect
+	router.GET("/foo", func(c *Context) {})
+	router.POST("/foo", func(c *Context) {
+		body, _ := c.GetRawData()
+		c.String(http.StatusOK, c.Request.Method+" "+string(body))
+	})
+
+	w := PerformRequest(router, http.MethodPost, "/foo/")
+	assert.Equal(t, http.StatusPermanentRedirect, w.Code)
+	assert.Equal(t, "/foo", w.Header().Get("Location"))
+
+	w = PerformRequest(router, http.MethodGet, "/foo/")
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+
+	// the client repeats the method and the body
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+	resp, err := http.Post(srv.URL+"/foo/", MIMEPlain, strings.NewReader("payload"))
+	if assert.NoError(t, err) {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "POST payload", string(body))
+	}
+}
+
 func TestRouteRedirectFixedPath(t *testing.T) {
 	router := New()
 	router.RedirectFixedPath = true
@@ -514,6 +544,37 @@ func TestRouteNotAllowedEnabled2(t *testing.T) {
 	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
 }
 
+func TestRouteNotAllowedAllowHeader(t *testing.T) {
+	router := New()
+	router.HandleMethodNotAllowed = true
+	router.GET("/path", func(c *Context) {})
+	router.DELETE("/path", func(c *Context) {})
+	router.PUT("/other", func(c *Context) {})
+
+	w := PerformRequest(router, http.MethodPost, "/path")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET, DELETE", w.Header().Get("Allow"))
+
+	var allowed []string
+	router.NoMethod(func(c *Context) {
+		allowed = c.AllowedMethods()
+		c.Header("Allow", "GET")
+		c.String(http.StatusMethodNotAllowed, "responseText")
+	})
+	w = PerformRequest(router, http.MethodPost, "/path")
+	assert.Equal(t, []string{http.MethodGet, http.MethodDelete}, allowed)
+	assert.Equal(t, "GET", w.Header().Get("Allow"))
+	assert.Equal(t, "responseText", w.Body.String())
+
+	router.HandleOptionsAutomatically = true
+	PerformRequest(router, http.MethodPost, "/path")
+	assert.Equal(t, []string{http.MethodGet, http.MethodDelete, http.MethodOptions}, allowed)
+
+	w = PerformRequest(router, http.MethodPost, "/missing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Empty(t, w.Header().Get("Allow"))
+}
+
 func TestRouteNotAllowedDisabled(t *testing.T) {
 	router := New()
 	router.HandleMethodNotAllowed = false
@@ -529,6 +590,40 @@ func TestRouteNotAllowedDisabled(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
 
+func TestRouteOptionsAutomatically(t *testing.T) {
+	router := New()
+	router.HandleOptionsAutomatically = true
+	router.HandleMethodNotAllowed = true
+	router.Use(func(c *Context) {
+		c.Header("Access-Control-Allow-Origin", "*")
+	})
+	router.GET("/items/:id", func(c *Context) {})
+	router.POST("/items/:id", func(c *Context) {})
+	router.PUT("/other", func(c *Context) {})
+	router.GET("/explicit", func(c *Context) {})
+	router.OPTIONS("/explicit", func(c *Context) {
+		c.String(http.StatusOK, "explicit")
+	})
+
+	w := PerformRequest(router, http.MethodOptions, "/items/1")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "GET, POST, OPTIONS", w.Header().Get("Allow"))
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Body.String())
+
+	w = PerformRequest(router, http.MethodOptions, "/explicit")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "explicit", w.Body.String())
+	assert.Empty(t, w.Header().Get("Allow"))
+
+	w = PerformRequest(router, http.MethodOptions, "/missing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	router.HandleOptionsAutomatically = false
+	w = PerformRequest(router, http.MethodOptions, "/items/1")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
 func TestRouterNotFoundWithRemoveExtraSlash(t *testing.T) {
 	router := New()
 	router.RemoveExtraSlash = true
@@ -776,3 +871,115 @@ func TestEngineHandleMethodNotAllowedCornerCase(t *testing.T) {
 	w := PerformRequest(r, "GET", "/base/v1/user/groups")
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
+
+func TestRouteStaticFastPath(t *testing.T) {
+	router := New()
+	router.HandleMethodNotAllowed = true
+	handle := func(c *Context) {
+		c.String(http.StatusOK, c.FullPath()+"|"+c.Param("id")+c.Param("path"))
+	}
+	router.GET("/v1/ping", handle)
+	router.GET("/v1/:id", handle)
+	router.GET("/files/*path", handle)
+	router.GET("/dir/", handle)
+
+	assert.Equal(t, map[string]HandlersChain{
+		"/v1/ping": router.staticRoutes[http.MethodGet]["/v1/ping"],
+		"/dir/":    router.staticRoutes[http.MethodGet]["/dir/"],
+	}, router.staticRoutes[http.MethodGet])
+
+	w := PerformRequest(router, http.MethodGet, "/v1/ping")
+	assert.Equal(t, "/v1/ping|", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/v1/pong")
+	assert.Equal(t, "/v1/:id|pong", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/files/v1/ping")
+	assert.Equal(t, "/files/*path|/v1/ping", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/dir")
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+
+	w = PerformRequest(router, http.MethodPost, "/v1/ping")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestRouteGroupNoRoute(t *testing.T) {
+	router := New()
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(func(c *Context) {
+		c.String(http.StatusNotFound, "global")
+	})
+	v1 := router.Group("/v1")
+	v1.GET("/users", func(c *Context) {})
+	v1.NoRoute(func(c *Context) {
+		c.String(http.StatusNotFound, "v1 not found")
+	})
+	v2 := router.Group("/v2/", func(c *Context) {
+		c.Header("X-Group", "v2")
+	})
+	v2.NoRoute(func(c *Context) {
+		c.JSON(http.StatusNotFound, H{"error": "not found"})
+	})
+	// more specific than v2, registered later
+	admin := v2.Group("/admin")
+	admin.NoRoute(func(c *Context) {
+		c.String(http.StatusNotFound, "admin")
+	})
+	// only the status is set, the default body is written
+	router.Group("/v3").NoRoute()
+
+	testCases := []struct {
+		path string
+		body string
+	}{
+		{"/v1/nope", "v1 not found"},
+		{"/v1", "v1 not found"},
+		{"/v10/nope", "global"},
+		{"/v2/nope", `{"error":"not found"}`},
+		{"/v2/admin/nope", "admin"},
+		{"/v2/admins", `{"error":"not found"}`},
+		{"/v3/nope", "404 page not found"},
+		{"/nope", "global"},
+	}
+	for _, tc := range testCases {
+		w := PerformRequest(router, http.MethodGet, tc.path)
+		assert.Equal(t, http.StatusNotFound, w.Code, tc.path)
+		assert.Equal(t, tc.body, w.Body.String(), tc.path)
+	}
+
+	w := PerformRequest(router, http.MethodGet, "/v2/nope")
+	assert.Equal(t, "v2", w.Header().Get("X-Group"))
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+	// 405 is not affected
+	w = PerformRequest(router, http.MethodPost, "/v1/users")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	// calling NoRoute again replaces the handlers
+	v1.NoRoute(func(c *Context) {
+		c.String(http.StatusNotFound, "v1 replaced")
+	})
+	w = PerformRequest(router, http.MethodGet, "/v1/nope")
+	assert.Equal(t, "v1 replaced", w.Body.String())
+}
+
+func TestRouteParamConstraint(t *testing.T) {
+	router := New()
+	router.GET("/users/:id{[0-9]+}", func(c *Context) {
+		c.String(http.StatusOK, c.FullPath()+"|"+c.Param("id"))
+	})
+	router.NoRoute(func(c *Context) {
+		c.String(http.StatusNotFound, "no route")
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/users/42")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "/users/:id{[0-9]+}|42", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/users/abc")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "no route", w.Body.String())
+
+	assert.Equal(t, []string{"id"}, router.RouteTree(http.MethodGet)[1].Params)
+}
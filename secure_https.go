@@ -0,0 +1,128 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultHSTSMaxAge is the max-age of the Strict-Transport-Security header set by SecureHTTPS.
+const DefaultHSTSMaxAge = 365 * 24 * time.Hour
+
+// SecureHTTPSConfig configures the SecureHTTPS middleware.
+type SecureHTTPSConfig struct {
+	// RedirectStatus is the status of the redirects to HTTPS.
+	// Optional. Default value is 301 Moved Permanently for GET and HEAD requests
+	// and 308 Permanent Redirect, which keeps the method and body, for the others.
+	RedirectStatus int
+
+	// Host is the host HTTP requests are redirected to, e.g. "example.com:8443".
+	// Optional. Default value is the host of the request, without its port.
+	// As the host of the request is set by the client, Host should be set when the
+	// server is reachable by Host headers it does not serve, to not redirect to them.
+	Host string
+
+	// HSTSMaxAge is the max-age of the Strict-Transport-Security header, rounded down to seconds.
+	// Optional. Default value is DefaultHSTSMaxAge. A negative value disables the header.
+	HSTSMaxAge time.Duration
+
+	// HSTSIncludeSubDomains adds the includeSubDomains directive to the Strict-Transport-Security header.
+	HSTSIncludeSubDomains bool
+
+	// HSTSPreload adds the preload directive to the Strict-Transport-Security header.
+	HSTSPreload bool
+
+	// SkipPaths is an url path array which requests are passed through untouched,
+	// e.g. health checks made over plain HTTP.
+	// Optional.
+	SkipPaths []string
+}
+
+// SecureHTTPS returns a middleware redirecting the requests made over plain HTTP to HTTPS
+// and setting the Strict-Transport-Security header on the responses to HTTPS requests.
+// A request is made over HTTPS if it came over TLS, or if it came from a trusted proxy,
+// see Engine.SetTrustedProxies, with "https" as the first X-Forwarded-Proto value.
+func SecureHTTPS(conf SecureHTTPSConfig) HandlerFunc {
+	var skip map[string]struct{}
+	if length := len(conf.SkipPaths); length > 0 {
+		skip = make(map[string]struct{}, length)
+		for _, path := range conf.SkipPaths {
+			skip[path] = struct{}{}
+		}
+	}
+
+	if conf.HSTSMaxAge == 0 {
+		conf.HSTSMaxAge = DefaultHSTSMaxAge
+	}
+	var hsts string
+	if conf.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.FormatInt(int64(conf.HSTSMaxAge/time.Second), 10)
+		if conf.HSTSIncludeSubDomains {
+			hsts += "; includeSubDomains"
+		}
+		if conf.HSTSPreload {
+			hsts += "; preload"
+		}
+	}
+
+	return func(c *Context) {
+		if _, ok := skip[c.Request.URL.Path]; ok {
+			c.Next()
+			return
+		}
+
+		if isHTTPS(c) {
+			if hsts != "" {
+				c.Header("Strict-Transport-Security", hsts)
+			}
+			c.Next()
+			return
+		}
+
+		code := conf.RedirectStatus
+		if code == 0 {
+			code = http.StatusPermanentRedirect
+			if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+				code = http.StatusMovedPermanently
+			}
+		}
+		host := conf.Host
+		if host == "" {
+			host = hostWithoutPort(c.Request.Host)
+		}
+		c.Redirect(code, "https://"+host+c.Request.URL.RequestURI())
+		c.Abort()
+	}
+}
+
+// hostWithoutPort returns host without its port, if any.
+func hostWithoutPort(host string) string {
+	h, _, err := net.SplitHostPort(host)
+	if err != nil {
+		return host
+	}
+	if strings.Contains(h, ":") {
+		return "[" + h + "]"
+	}
+	return h
+}
+
+// isHTTPS reports whether the request was made over HTTPS, trusting the
+// X-Forwarded-Proto header only when it comes from a trusted proxy.
+func isHTTPS(c *Context) bool {
+	if c.Request.TLS != nil {
+		return true
+	}
+	proto := c.requestHeader("X-Forwarded-Proto")
+	if proto == "" || !c.engine.isTrustedProxy(net.ParseIP(c.RemoteIP())) {
+		return false
+	}
+	proto, _, _ = strings.Cut(proto, ",")
+	return strings.EqualFold(strings.TrimSpace(proto), "https")
+}
@@ -0,0 +1,116 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecureHTTPSRedirect(t *testing.T) {
+	router := New()
+	router.Use(SecureHTTPS(SecureHTTPSConfig{}))
+	router.Any("/users", func(c *Context) {})
+
+	w := PerformRequest(router, http.MethodGet, "http://example.com/users?page=2")
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "https://example.com/users?page=2", w.Header().Get("Location"))
+	assert.Empty(t, w.Header().Get("Strict-Transport-Security"))
+
+	w = PerformRequest(router, http.MethodPost, "http://example.com/users")
+	assert.Equal(t, http.StatusPermanentRedirect, w.Code)
+	assert.Equal(t, "https://example.com/users", w.Header().Get("Location"))
+
+	// the port of the request is not kept
+	w = PerformRequest(router, http.MethodGet, "http://example.com:80/users")
+	assert.Equal(t, "https://example.com/users", w.Header().Get("Location"))
+	w = PerformRequest(router, http.MethodGet, "http://[::1]:8080/users")
+	assert.Equal(t, "https://[::1]/users", w.Header().Get("Location"))
+
+	router = New()
+	router.Use(SecureHTTPS(SecureHTTPSConfig{RedirectStatus: http.StatusFound, Host: "example.com:8443"}))
+	router.POST("/users", func(c *Context) {})
+	w = PerformRequest(router, http.MethodPost, "http://example.com:8080/users")
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "https://example.com:8443/users", w.Header().Get("Location"))
+}
+
+func TestSecureHTTPSHSTS(t *testing.T) {
+	router := New()
+	router.Use(SecureHTTPS(SecureHTTPSConfig{}))
+	router.GET("/users", func(c *Context) {
+		c.String(http.StatusOK, "users")
+	})
+	w := PerformRequest(router, http.MethodGet, "https://example.com/users")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "users", w.Body.String())
+	assert.Equal(t, "max-age=31536000", w.Header().Get("Strict-Transport-Security"))
+
+	router = New()
+	router.Use(SecureHTTPS(SecureHTTPSConfig{
+		HSTSMaxAge:            time.Hour,
+		HSTSIncludeSubDomains: true,
+		HSTSPreload:           true,
+	}))
+	router.GET("/users", func(c *Context) {})
+	w = PerformRequest(router, http.MethodGet, "https://example.com/users")
+	assert.Equal(t, "max-age=3600; includeSubDomains; preload", w.Header().Get("Strict-Transport-Security"))
+
+	router = New()
+	router.Use(SecureHTTPS(SecureHTTPSConfig{HSTSMaxAge: -1}))
+	router.GET("/users", func(c *Context) {})
+	w = PerformRequest(router, http.MethodGet, "https://example.com/users")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Strict-Transport-Security"))
+}
+
+func TestSecureHTTPSForwardedProto(t *testing.T) {
+	router := New()
+	router.Use(SecureHTTPS(SecureHTTPSConfig{}))
+	router.GET("/users", func(c *Context) {})
+
+	// all proxies are trusted by default
+	w := PerformRequest(router, http.MethodGet, "http://example.com/users", header{"X-Forwarded-Proto", "https, http"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "max-age=31536000", w.Header().Get("Strict-Transport-Security"))
+
+	w = PerformRequest(router, http.MethodGet, "http://example.com/users", header{"X-Forwarded-Proto", "http"})
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+
+	assert.NoError(t, router.SetTrustedProxies([]string{"10.0.0.0/8"}))
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/users", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	req.RemoteAddr = "10.0.0.1:1234"
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// the header of an untrusted client is ignored
+	req.RemoteAddr = "192.0.2.1:1234"
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "https://example.com/users", w.Header().Get("Location"))
+}
+
+func TestSecureHTTPSSkipPaths(t *testing.T) {
+	router := New()
+	router.Use(SecureHTTPS(SecureHTTPSConfig{SkipPaths: []string{"/healthz"}}))
+	router.GET("/healthz", func(c *Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	router.GET("/users", func(c *Context) {})
+
+	w := PerformRequest(router, http.MethodGet, "http://example.com/healthz")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ok", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "http://example.com/users")
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+}
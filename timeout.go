@@ -0,0 +1,213 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Timeout returns a middleware that runs the rest of the handler chain with a deadline of d.
+// The request context is cancelled when d expires, and onTimeout writes the response instead;
+// a nil onTimeout aborts with 503 Service Unavailable.
+//
+// The chain runs in its own goroutine and writes into a buffer that is sent once it returns,
+// so streaming, Flush and Hijack are not available behind Timeout. After the deadline the
+// buffered response is dropped and further writes fail with http.ErrHandlerTimeout.
+// onTimeout gets a copy of the Context, see Context.Copy, and may run while the chain is
+// still running; it must not touch the original Context. Its response is flushed to the
+// client right away, but Timeout itself only returns once the chain has returned, holding
+// the connection until then, so handlers should still give up when c.Request.Context() is done.
+func Timeout(d time.Duration, onTimeout HandlerFunc) HandlerFunc {
+	if onTimeout == nil {
+		onTimeout = func(c *Context) {
+			c.AbortWithStatus(http.StatusServiceUnavailable)
+		}
+	}
+	return func(c *Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		w := c.Writer
+		tw := &timeoutWriter{ResponseWriter: w, ctx: ctx, header: w.Header().Clone(), status: http.StatusOK}
+		tc := c.Copy()
+		tc.Writer = w
+		c.Writer = tw
+
+		done := make(chan struct{})
+		panicChan := make(chan any, 1)
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					panicChan <- p
+				}
+				close(done)
+			}()
+			c.Next()
+		}()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+		}
+		tw.mu.Lock()
+		timedOut := tw.expired()
+		tw.mu.Unlock()
+		if timedOut {
+			onTimeout(tc)
+			flushError(w) //nolint: errcheck
+			<-done
+		}
+		c.Writer = w
+
+		select {
+		case p := <-panicChan:
+			panic(p)
+		default:
+		}
+		if !timedOut {
+			tw.writeTo(w)
+		}
+	}
+}
+
+// DeadlineFromHeader returns a middleware that gives the request context of the rest of the
+// handler chain a deadline read from the given header, e.g. "X-Request-Timeout: 2s", so that
+// calls made with c.Request.Context() honor it. The value is parsed with time.ParseDuration
+// and capped at max unless max is 0. A missing, invalid or non-positive value leaves the request
+// unchanged. The context is cancelled once the chain returns.
+func DeadlineFromHeader(header string, max time.Duration) HandlerFunc {
+	return func(c *Context) {
+		d, err := time.ParseDuration(c.requestHeader(header))
+		if err != nil || d <= 0 {
+			c.Next()
+			return
+		}
+		if max > 0 && d > max {
+			d = max
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// timeoutWriter buffers the response of the chain run by Timeout.
+type timeoutWriter struct {
+	ResponseWriter
+	ctx context.Context
+
+	mu          sync.Mutex
+	header      http.Header
+	buf         bytes.Buffer
+	status      int
+	wroteHeader bool
+	timedOut    bool
+}
+
+var _ ResponseWriter = (*timeoutWriter)(nil)
+
+// expired reports whether the deadline has passed, after which the response is dropped.
+// tw.mu must be held.
+func (tw *timeoutWriter) expired() bool {
+	if !tw.timedOut && tw.ctx.Err() != nil {
+		tw.timedOut = true
+	}
+	return tw.timedOut
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if code > 0 && !tw.wroteHeader && !tw.expired() {
+		tw.status = code
+	}
+}
+
+func (tw *timeoutWriter) WriteHeaderNow() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.wroteHeader = true
+}
+
+func (tw *timeoutWriter) Write(data []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.expired() {
+		return 0, http.ErrHandlerTimeout
+	}
+	tw.wroteHeader = true
+	return tw.buf.Write(data)
+}
+
+func (tw *timeoutWriter) WriteString(s string) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.expired() {
+		return 0, http.ErrHandlerTimeout
+	}
+	tw.wroteHeader = true
+	return tw.buf.WriteString(s)
+}
+
+func (tw *timeoutWriter) Status() int {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	return tw.status
+}
+
+func (tw *timeoutWriter) Size() int {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if !tw.wroteHeader {
+		return noWritten
+	}
+	return tw.buf.Len()
+}
+
+func (tw *timeoutWriter) Written() bool {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	return tw.wroteHeader
+}
+
+// Flush is a no-op, the response is sent when the chain returns.
+func (tw *timeoutWriter) Flush() {}
+
+func (tw *timeoutWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, errors.New("gin: Hijack is not supported behind Timeout")
+}
+
+func (tw *timeoutWriter) Pusher() http.Pusher {
+	return nil
+}
+
+// writeTo sends the buffered response to w.
+func (tw *timeoutWriter) writeTo(w ResponseWriter) {
+	dst := w.Header()
+	for k := range dst {
+		delete(dst, k)
+	}
+	for k, v := range tw.header {
+		dst[k] = v
+	}
+	w.WriteHeader(tw.status)
+	if tw.wroteHeader {
+		w.WriteHeaderNow()
+	}
+	w.Write(tw.buf.Bytes()) //nolint: errcheck
+}
@@ -0,0 +1,162 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeoutSlowHandler(t *testing.T) {
+	writeErr := make(chan error, 1)
+	router := New()
+	router.GET("/", Timeout(10*time.Millisecond, nil), func(c *Context) {
+		<-c.Request.Context().Done()
+		c.Header("X-Late", "1")
+		_, err := c.Writer.WriteString("late")
+		writeErr <- err
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/")
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Empty(t, w.Header().Get("X-Late"))
+	assert.ErrorIs(t, <-writeErr, http.ErrHandlerTimeout)
+}
+
+func TestTimeoutServer(t *testing.T) {
+	release := make(chan struct{})
+	router := New()
+	router.GET("/", Timeout(50*time.Millisecond, nil), func(c *Context) {
+		<-release
+	})
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+	defer close(release)
+
+	start := time.Now()
+	resp, err := http.Get(srv.URL)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestTimeoutCustomHandler(t *testing.T) {
+	router := New()
+	router.Use(func(c *Context) {
+		c.Set("user", "gin")
+		c.Next()
+	})
+	router.GET("/", Timeout(10*time.Millisecond, func(c *Context) {
+		c.String(http.StatusGatewayTimeout, "timeout for %s", c.MustGet("user"))
+	}), func(c *Context) {
+		<-c.Request.Context().Done()
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/")
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Equal(t, "timeout for gin", w.Body.String())
+}
+
+func TestTimeoutFastHandler(t *testing.T) {
+	router := New()
+	router.GET("/", func(c *Context) {
+		c.Header("X-Before", "1")
+		c.Next()
+	}, Timeout(time.Second, nil), func(c *Context) {
+		_, ok := c.Request.Context().Deadline()
+		assert.True(t, ok)
+		assert.False(t, c.Writer.Written())
+		c.Header("X-After", "1")
+		c.String(http.StatusCreated, "created")
+		assert.Equal(t, http.StatusCreated, c.Writer.Status())
+		assert.Equal(t, 7, c.Writer.Size())
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/")
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "created", w.Body.String())
+	assert.Equal(t, "1", w.Header().Get("X-Before"))
+	assert.Equal(t, "1", w.Header().Get("X-After"))
+}
+
+func TestTimeoutStatusOnly(t *testing.T) {
+	router := New()
+	router.GET("/", Timeout(time.Second, nil), func(c *Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/")
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestTimeoutPanic(t *testing.T) {
+	router := New()
+	router.Use(Recovery())
+	router.GET("/", Timeout(time.Second, nil), func(c *Context) {
+		panic("oops")
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/")
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestDeadlineFromHeader(t *testing.T) {
+	var ctx context.Context
+	router := New()
+	router.GET("/", DeadlineFromHeader("X-Request-Timeout", time.Second), func(c *Context) {
+		ctx = c.Request.Context()
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			c.String(http.StatusOK, "none")
+			return
+		}
+		c.String(http.StatusOK, time.Until(deadline).Round(100*time.Millisecond).String())
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/", header{Key: "X-Request-Timeout", Value: "500ms"})
+	assert.Equal(t, "500ms", w.Body.String())
+	// cancelled once the chain returned
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+
+	w = PerformRequest(router, http.MethodGet, "/", header{Key: "X-Request-Timeout", Value: "1h"})
+	assert.Equal(t, "1s", w.Body.String())
+
+	for _, value := range []string{"", "soon", "-1s", "0"} {
+		w = PerformRequest(router, http.MethodGet, "/", header{Key: "X-Request-Timeout", Value: value})
+		assert.Equal(t, "none", w.Body.String(), value)
+		assert.NoError(t, ctx.Err())
+	}
+}
+
+func TestDeadlineFromHeaderExpires(t *testing.T) {
+	router := New()
+	router.GET("/", DeadlineFromHeader("X-Request-Timeout", 0), func(c *Context) {
+		select {
+		case <-c.Request.Context().Done():
+			assert.ErrorIs(t, c.Request.Context().Err(), context.DeadlineExceeded)
+			c.Status(http.StatusGatewayTimeout)
+		case <-time.After(time.Second):
+			c.Status(http.StatusOK)
+		}
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/", header{Key: "X-Request-Timeout", Value: "10ms"})
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+}
@@ -7,6 +7,7 @@ package gin
 import (
 	"bytes"
 	"net/url"
+	"regexp"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -122,6 +123,26 @@ type node struct {
 	children  []*node // child nodes, at most 1 :param style node at the end of the array
 	handlers  HandlersChain
 	fullPath  string
+	// pattern is the constraint of a param node such as ":id{[0-9]+}".
+	pattern *regexp.Regexp
+}
+
+// clone returns a copy of the tree rooted at n, to add routes to while n is read.
+func (n *node) clone() *node {
+	c := *n
+	c.children = make([]*node, len(n.children))
+	for i, child := range n.children {
+		c.children[i] = child.clone()
+	}
+	return &c
+}
+
+// paramKey returns the name of a param node, without the ':' and the constraint.
+func (n *node) paramKey() string {
+	if i := strings.IndexByte(n.path, '{'); i > 0 {
+		return n.path[1:i]
+	}
+	return n.path[1:]
 }
 
 // Increments priority of the given child and reorders if necessary
@@ -180,6 +201,7 @@ walk:
 				handlers:  n.handlers,
 				priority:  n.priority - 1,
 				fullPath:  n.fullPath,
+				pattern:   n.pattern,
 			}
 
 			n.children = []*node{&child}
@@ -266,6 +288,7 @@ walk:
 }
 
 // Search for a wildcard segment and check the name for invalid characters.
+// The constraint of a param, e.g. "{[0-9]+}" in ":id{[0-9]+}", is part of the wildcard.
 // Returns -1 as index, if no wildcard was found.
 func findWildcard(path string) (wildcard string, i int, valid bool) {
 	// Find start
@@ -277,11 +300,17 @@ func findWildcard(path string) (wildcard string, i int, valid bool) {
 
 		// Find end and check for invalid characters
 		valid = true
+		depth := 0
 		for end, c := range []byte(path[start+1:]) {
-			switch c {
-			case '/':
+			switch {
+			case c == '{' && path[start] == ':':
+				depth++
+			case c == '}' && depth > 0:
+				depth--
+			case depth > 0:
+			case c == '/':
 				return path[start : start+1+end], start, valid
-			case ':', '*':
+			case c == ':' || c == '*':
 				valid = false
 			}
 		}
@@ -320,6 +349,7 @@ func (n *node) insertChild(path string, fullPath string, handlers HandlersChain)
 				nType:    param,
 				path:     wildcard,
 				fullPath: fullPath,
+				pattern:  paramPattern(wildcard, fullPath),
 			}
 			n.addChild(child)
 			n.wildChild = true
@@ -398,6 +428,26 @@ func (n *node) insertChild(path string, fullPath string, handlers HandlersChain)
 	n.fullPath = fullPath
 }
 
+// paramPattern compiles the constraint of a param wildcard such as ":id{[0-9]+}", which the
+// whole value of the param must match. It returns nil for a wildcard without one.
+func paramPattern(wildcard, fullPath string) *regexp.Regexp {
+	i := strings.IndexByte(wildcard, '{')
+	if i < 0 {
+		return nil
+	}
+	if i < 2 {
+		panic("wildcards must be named with a non-empty name in path '" + fullPath + "'")
+	}
+	if wildcard[len(wildcard)-1] != '}' {
+		panic("invalid constraint in wildcard '" + wildcard + "' in path '" + fullPath + "'")
+	}
+	pattern, err := regexp.Compile("^(?:" + wildcard[i+1:len(wildcard)-1] + ")$")
+	if err != nil {
+		panic("invalid constraint in wildcard '" + wildcard + "' in path '" + fullPath + "': " + err.Error())
+	}
+	return pattern
+}
+
 // nodeValue holds return values of (*Node).getValue method
 type nodeValue struct {
 	handlers HandlersChain
@@ -445,6 +495,7 @@ walk: // Outer loop for walking the tree
 									children:  n.children,
 									handlers:  n.handlers,
 									fullPath:  n.fullPath,
+									pattern:   n.pattern,
 								},
 								paramsCount: globalParamsCount,
 							}
@@ -496,6 +547,32 @@ walk: // Outer loop for walking the tree
 						end++
 					}
 
+					val := path[:end]
+					if unescape {
+						if v, err := url.QueryUnescape(val); err == nil {
+							val = v
+						}
+					}
+
+					// The value does not satisfy the constraint,
+					// roll back to last valid skippedNode
+					if n.pattern != nil && !n.pattern.MatchString(val) {
+						for length := len(*skippedNodes); length > 0; length-- {
+							skippedNode := (*skippedNodes)[length-1]
+							*skippedNodes = (*skippedNodes)[:length-1]
+							if strings.HasSuffix(skippedNode.path, path) {
+								path = skippedNode.path
+								n = skippedNode.node
+								if value.params != nil {
+									*value.params = (*value.params)[:skippedNode.paramsCount]
+								}
+								globalParamsCount = skippedNode.paramsCount
+								continue walk
+							}
+						}
+						return
+					}
+
 					// Save param value
 					if params != nil {
 						// Preallocate capacity if necessary
@@ -511,14 +588,8 @@ walk: // Outer loop for walking the tree
 						// Expand slice within preallocated capacity
 						i := len(*value.params)
 						*value.params = (*value.params)[:i+1]
-						val := path[:end]
-						if unescape {
-							if v, err := url.QueryUnescape(val); err == nil {
-								val = v
-							}
-						}
 						(*value.params)[i] = Param{
-							Key:   n.path[1:],
+							Key:   n.paramKey(),
 							Value: val,
 						}
 					}
@@ -504,6 +504,61 @@ func TestEmptyWildcardName(t *testing.T) {
 	}
 }
 
+func TestTreeParamConstraint(t *testing.T) {
+	tree := &node{}
+
+	routes := [...]string{
+		"/users/:id{[0-9]+}",
+		"/users/:id{[0-9]+}/posts/:slug{[a-z-]{3,}}",
+		"/users/new",
+		"/files/:name{.*\\.txt}",
+		"/src/*filepath",
+	}
+	for _, route := range routes {
+		tree.addRoute(route, fakeHandler(route))
+	}
+
+	checkRequests(t, tree, testRequests{
+		{"/users/42", false, "/users/:id{[0-9]+}", Params{Param{"id", "42"}}},
+		{"/users/abc", true, "", nil},
+		{"/users/new", false, "/users/new", nil},
+		{"/users/newer", true, "", nil},
+		{"/users/42/posts/hello-gin", false, "/users/:id{[0-9]+}/posts/:slug{[a-z-]{3,}}", Params{Param{"id", "42"}, Param{"slug", "hello-gin"}}},
+		{"/users/42/posts/hi", true, "", Params{Param{"id", "42"}}},
+		{"/files/a.txt", false, "/files/:name{.*\\.txt}", Params{Param{"name", "a.txt"}}},
+		{"/files/a.md", true, "", nil},
+	})
+
+	checkPriorities(t, tree)
+}
+
+func TestTreeParamConstraintConflict(t *testing.T) {
+	routes := []testRoute{
+		{"/users/:id{[0-9]+}", false},
+		{"/users/:id{[a-z]+}", true},
+		{"/users/:name", true},
+		{"/users/:id{[0-9]+}/posts", false},
+	}
+	testRoutes(t, routes)
+}
+
+func TestTreeInvalidParamConstraint(t *testing.T) {
+	routes := [...]string{
+		"/users/:id{[0-9+}",
+		"/users/:id{[0-9]+}x",
+		"/users/:{[0-9]+}",
+	}
+	for _, route := range routes {
+		tree := &node{}
+		recv := catchPanic(func() {
+			tree.addRoute(route, nil)
+		})
+		if recv == nil {
+			t.Fatalf("no panic while inserting route with invalid constraint '%s", route)
+		}
+	}
+}
+
 func TestTreeCatchAllConflict(t *testing.T) {
 	routes := []testRoute{
 		{"/src/*filepath/x", true},
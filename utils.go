@@ -6,13 +6,20 @@ package gin
 
 import (
 	"encoding/xml"
+	"errors"
+	"mime"
 	"net/http"
 	"os"
 	"path"
 	"reflect"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 	"unicode"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 )
 
 // BindKey indicates a default bind key.
@@ -36,6 +43,41 @@ func Bind(val any) HandlerFunc {
 	}
 }
 
+// StatusCoder is implemented by errors returned from a JSON handler to choose the response status.
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// JSON is a helper function that turns fn into a Gin handler. The request body is bound into Req
+// with c.MustBindJSON, so a bind error is answered by Engine.BindErrorHandler. The Res returned by
+// fn is rendered with c.JSON and 200. An error returned by fn is added to c.Errors and answered
+// with {"error": err.Error()} and its StatusCode() if it implements StatusCoder, otherwise with 500
+// and the status text only, so internal messages are not sent to the client.
+//
+//	router.POST("/users", gin.JSON(func(c *gin.Context, req CreateUser) (User, error) {
+//	    return store.Create(req)
+//	}))
+func JSON[Req, Res any](fn func(*Context, Req) (Res, error)) HandlerFunc {
+	return func(c *Context) {
+		var req Req
+		if !c.MustBindJSON(&req) {
+			return
+		}
+		res, err := fn(c, req)
+		if err != nil {
+			c.Error(err) //nolint: errcheck
+			var sc StatusCoder
+			if errors.As(err, &sc) {
+				c.AbortWithStatusJSON(sc.StatusCode(), H{"error": err.Error()})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, H{"error": http.StatusText(http.StatusInternalServerError)})
+			return
+		}
+		c.JSON(http.StatusOK, res)
+	}
+}
+
 // WrapF is a helper function for wrapping http.HandlerFunc and returns a Gin middleware.
 func WrapF(f http.HandlerFunc) HandlerFunc {
 	return func(c *Context) {
@@ -100,18 +142,192 @@ func chooseData(custom, wildcard any) any {
 	panic("negotiation config is invalid")
 }
 
-func parseAccept(acceptHeader string) []string {
+// acceptRange is a media range of the Accept header with its quality.
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// parseAccept returns the acceptable media ranges of the Accept header, most preferred
+// first: by decreasing q-value, then in header order. The ranges with q=0 are returned
+// apart, they are explicitly not acceptable. A range with an invalid q-value is ignored.
+func parseAccept(acceptHeader string) (accepted, rejected []string) {
 	parts := strings.Split(acceptHeader, ",")
-	out := make([]string, 0, len(parts))
+	ranges := make([]acceptRange, 0, len(parts))
 	for _, part := range parts {
-		if i := strings.IndexByte(part, ';'); i > 0 {
-			part = part[:i]
+		mediaType, params, _ := strings.Cut(part, ";")
+		if mediaType = strings.TrimSpace(mediaType); mediaType == "" {
+			continue
+		}
+		q, ok := acceptQuality(params)
+		if !ok {
+			continue
+		}
+		if q == 0 {
+			rejected = append(rejected, mediaType)
+			continue
+		}
+		ranges = append(ranges, acceptRange{mediaType: mediaType, q: q})
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].q > ranges[j].q
+	})
+	accepted = make([]string, len(ranges))
+	for i, r := range ranges {
+		accepted[i] = r.mediaType
+	}
+	return accepted, rejected
+}
+
+// acceptQuality returns the first q parameter of the parameters of a media range, 1 if
+// there is none. ok is false if it is not a number between 0 and 1.
+func acceptQuality(params string) (q float64, ok bool) {
+	for params != "" {
+		var param string
+		param, params, _ = strings.Cut(params, ";")
+		key, value, _ := strings.Cut(param, "=")
+		if !strings.EqualFold(strings.TrimSpace(key), "q") {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		return q, err == nil && q >= 0 && q <= 1
+	}
+	return 1, true
+}
+
+// mediaRangeMatch reports whether the media types a and b match, either of them
+// may be a range such as "text/*" or "*/*".
+func mediaRangeMatch(a, b string) bool {
+	aType, aSub, _ := strings.Cut(a, "/")
+	bType, bSub, _ := strings.Cut(b, "/")
+	if aType != "*" && bType != "*" && !strings.EqualFold(aType, bType) {
+		return false
+	}
+	return aSub == "*" || bSub == "*" || aType == "*" || bType == "*" || strings.EqualFold(aSub, bSub)
+}
+
+// mediaRangeSpecificity ranks "*/*" below "type/*" below "type/subtype".
+func mediaRangeSpecificity(mediaRange string) int {
+	switch {
+	case strings.HasPrefix(mediaRange, "*"):
+		return 0
+	case strings.HasSuffix(mediaRange, "/*"):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// acceptParam returns the lower-cased value of param on the first media type of
+// the Accept header equal to mediaType, or "" if there is none.
+func acceptParam(acceptHeader, mediaType, param string) string {
+	for _, part := range strings.Split(acceptHeader, ",") {
+		mt, params, err := mime.ParseMediaType(part)
+		if err != nil || mt != mediaType {
+			continue
 		}
-		if part = strings.TrimSpace(part); part != "" {
-			out = append(out, part)
+		return strings.ToLower(params[param])
+	}
+	return ""
+}
+
+// ValidationMessages turns the validator.ValidationErrors returned by a binding, such as
+// c.ShouldBindJSON, into one message per invalid field, keyed by its JSON path, e.g.
+// "items[0].id"; the elements of a slice body are keyed like "[1].name". It returns nil
+// when err holds no validation errors.
+func ValidationMessages(err error) map[string]string {
+	messages := make(map[string]string)
+	addValidationMessages(messages, "", err)
+	if len(messages) == 0 {
+		return nil
+	}
+	return messages
+}
+
+func addValidationMessages(messages map[string]string, prefix string, err error) {
+	var sliceErrs binding.SliceValidationError
+	if errors.As(err, &sliceErrs) {
+		for i, e := range sliceErrs {
+			if e == nil {
+				continue
+			}
+			index := i
+			var elemErr *binding.SliceElementError
+			if errors.As(e, &elemErr) {
+				index = elemErr.Index
+			}
+			addValidationMessages(messages, prefix+"["+strconv.Itoa(index)+"]", e)
 		}
+		return
 	}
-	return out
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return
+	}
+	for _, fe := range verrs {
+		key := validationKey(fe)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		messages[key] = validationMessage(fe)
+	}
+}
+
+// validationKey returns the path of the field of fe from the validated struct, with the
+// json tag names when the default validator reports the struct type.
+func validationKey(fe validator.FieldError) string {
+	if sf, ok := fe.(interface{ StructType() reflect.Type }); ok {
+		return jsonFieldPath(sf.StructType(), fe.StructNamespace())
+	}
+	// the namespace starts with the name of the validated struct
+	_, key, _ := strings.Cut(fe.Namespace(), ".")
+	return key
+}
+
+// validationMessage describes the rule a field failed on.
+func validationMessage(fe validator.FieldError) string {
+	switch {
+	case fe.Tag() == "required":
+		return "is required"
+	case fe.Param() != "":
+		return "failed on the '" + fe.Tag() + "=" + fe.Param() + "' rule"
+	default:
+		return "failed on the '" + fe.Tag() + "' rule"
+	}
+}
+
+// jsonFieldPath converts a validator struct namespace such as "User.Items[0].ID" of typ
+// to the JSON path "items[0].id", using the json tag names of the fields.
+func jsonFieldPath(typ reflect.Type, namespace string) string {
+	parts := strings.Split(namespace, ".")[1:]
+	for i, part := range parts {
+		name, index, _ := strings.Cut(part, "[")
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		if typ.Kind() != reflect.Struct {
+			continue
+		}
+		field, ok := typ.FieldByName(name)
+		if !ok {
+			continue
+		}
+		if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag != "" && tag != "-" {
+			name = tag
+		}
+		typ = field.Type
+		if index != "" {
+			name += "[" + index
+			for typ.Kind() == reflect.Ptr {
+				typ = typ.Elem()
+			}
+			if typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array || typ.Kind() == reflect.Map {
+				typ = typ.Elem()
+			}
+		}
+		parts[i] = name
+	}
+	return strings.Join(parts, ".")
 }
 
 func lastChar(str string) uint8 {
@@ -162,3 +378,23 @@ func isASCII(s string) bool {
 	}
 	return true
 }
+
+// encodeExtValue percent-encodes s as the UTF-8 value of an RFC 5987 extended parameter,
+// keeping only attr-char bytes as they are.
+func encodeExtValue(s string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	b.Grow(len(s) * 3)
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || '0' <= ch && ch <= '9' ||
+			strings.IndexByte("!#$&+-.^_`|~", ch) >= 0 {
+			b.WriteByte(ch)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hex[ch>>4])
+		b.WriteByte(hex[ch&0x0f])
+	}
+	return b.String()
+}
@@ -9,6 +9,7 @@ import (
 	"encoding/xml"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -61,12 +62,25 @@ func TestLastChar(t *testing.T) {
 }
 
 func TestParseAccept(t *testing.T) {
-	parts := parseAccept("text/html , application/xhtml+xml,application/xml;q=0.9,  */* ;q=0.8")
+	parts, rejected := parseAccept("text/html , application/xhtml+xml,application/xml;q=0.9,  */* ;q=0.8")
 	assert.Len(t, parts, 4)
 	assert.Equal(t, "text/html", parts[0])
 	assert.Equal(t, "application/xhtml+xml", parts[1])
 	assert.Equal(t, "application/xml", parts[2])
 	assert.Equal(t, "*/*", parts[3])
+	assert.Empty(t, rejected)
+
+	parts, rejected = parseAccept("*/*;q=0.1, application/json;q=0.8, text/html;level=1;Q=0.9, image/png;q=0, text/plain;q=2, text/csv;q=x, application/xml")
+	assert.Equal(t, []string{"application/xml", "text/html", "application/json", "*/*"}, parts)
+	assert.Equal(t, []string{"image/png"}, rejected)
+}
+
+func TestAcceptParam(t *testing.T) {
+	assert.Equal(t, "dec", acceptParam("text/html, application/json; ids=DEC", "application/json", "ids"))
+	assert.Equal(t, "", acceptParam("application/json", "application/json", "ids"))
+	assert.Equal(t, "", acceptParam("text/plain; ids=dec", "application/json", "ids"))
+	assert.Equal(t, "", acceptParam("", "application/json", "ids"))
+	assert.Equal(t, "hex", acceptParam("bad;;, application/json;ids=hex", "application/json", "ids"))
 }
 
 func TestChooseData(t *testing.T) {
@@ -148,3 +162,57 @@ func TestIsASCII(t *testing.T) {
 	assert.Equal(t, isASCII("test"), true)
 	assert.Equal(t, isASCII("🧡💛💚💙💜"), false)
 }
+
+func TestEncodeExtValue(t *testing.T) {
+	assert.Equal(t, "abc.txt", encodeExtValue("abc.txt"))
+	assert.Equal(t, "a%20b%3B%22c%22%25", encodeExtValue(`a b;"c"%`))
+	assert.Equal(t, "%E2%82%AC!#$&+-.^_`|~", encodeExtValue("€!#$&+-.^_`|~"))
+}
+
+type notFoundError struct{ id int64 }
+
+func (e notFoundError) Error() string   { return fmt.Sprintf("user %x not found", e.id) }
+func (e notFoundError) StatusCode() int { return http.StatusNotFound }
+
+func TestJSONHandler(t *testing.T) {
+	type userReq struct {
+		ID int64 `json:"id" binding:"required"`
+	}
+	type userRes struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+	router := New()
+	router.POST("/users", JSON(func(c *Context, req userReq) (userRes, error) {
+		switch req.ID {
+		case 255:
+			return userRes{ID: req.ID, Name: "gin"}, nil
+		case 1:
+			return userRes{}, fmt.Errorf("lookup: %w", notFoundError{req.ID})
+		}
+		return userRes{}, fmt.Errorf("db down")
+	}))
+
+	tests := []struct {
+		body string
+		code int
+		resp string
+	}{
+		{`{"id":255}`, http.StatusOK, `{"id":255,"name":"gin"}`},
+		{`{"id":1}`, http.StatusNotFound, `{"error":"lookup: user 1 not found"}`},
+		{`{"id":2}`, http.StatusInternalServerError, `{"error":"Internal Server Error"}`},
+		{`{}`, http.StatusBadRequest, ""},
+		{`{"id":`, http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(http.MethodPost, "/users", bytes.NewBufferString(tt.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, tt.code, w.Code, tt.body)
+		if tt.resp != "" {
+			assert.Equal(t, tt.resp, w.Body.String(), tt.body)
+		} else {
+			assert.Contains(t, w.Body.String(), `{"error":`, tt.body)
+		}
+	}
+}
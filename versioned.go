@@ -0,0 +1,84 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"strconv"
+	"strings"
+)
+
+// VersionHeader is the request header Versioned dispatches on, e.g. "Accept-Version: 2".
+const VersionHeader = "Accept-Version"
+
+// VersionRouter registers the routes of the API versions, see Engine.Versioned.
+type VersionRouter struct {
+	engine   *Engine
+	versions map[int]*RouterGroup
+}
+
+// Versioned calls fn to register versioned routes: v.V(n) returns the group of version n,
+// served under the "/v<n>" path prefix. A request whose path does not start with the prefix
+// of a registered version but that sends a VersionHeader naming one, e.g. "2" or "v2", is
+// routed as if its path had that prefix. When the path already has a version prefix it wins
+// and the header is ignored, so a URL always addresses the same version. Requests without
+// a known version are routed as usual. The responses to paths without a version prefix get
+// 'Vary: Accept-Version', whether the header was sent or not, so caches keep them apart.
+// The dispatch happens before the route is matched, so it applies to all the routes.
+func (engine *Engine) Versioned(fn func(v *VersionRouter)) {
+	if engine.versions == nil {
+		engine.versions = &VersionRouter{engine: engine, versions: make(map[int]*RouterGroup)}
+	}
+	fn(engine.versions)
+}
+
+// V returns the group of the given version, creating it on first use.
+func (v *VersionRouter) V(version int) *RouterGroup {
+	assert1(version >= 0, "version must not be negative")
+	group, ok := v.versions[version]
+	if !ok {
+		group = v.engine.Group("/v" + strconv.Itoa(version))
+		v.versions[version] = group
+	}
+	return group
+}
+
+// resolve returns the path to route the request by.
+func (v *VersionRouter) resolve(c *Context, path string) string {
+	if len(v.versions) == 0 || v.pathVersioned(path) {
+		return path
+	}
+	c.Writer.Header().Add("Vary", VersionHeader)
+	header := strings.TrimSpace(c.requestHeader(VersionHeader))
+	if header == "" {
+		return path
+	}
+	version, err := strconv.Atoi(strings.TrimPrefix(strings.TrimPrefix(header, "v"), "V"))
+	if err != nil {
+		return path
+	}
+	group, ok := v.versions[version]
+	if !ok {
+		return path
+	}
+	return joinPaths(group.BasePath(), path)
+}
+
+// pathVersioned reports whether the path starts with the prefix of a registered version.
+func (v *VersionRouter) pathVersioned(path string) bool {
+	if !strings.HasPrefix(path, "/v") {
+		return false
+	}
+	rest := path[len("/v"):]
+	end := strings.IndexByte(rest, '/')
+	if end < 0 {
+		end = len(rest)
+	}
+	version, err := strconv.Atoi(rest[:end])
+	if err != nil || rest[:end] != strconv.Itoa(version) {
+		return false
+	}
+	_, ok := v.versions[version]
+	return ok
+}
@@ -0,0 +1,110 @@
+// Copyright 2023 Gin Core Team. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package gin
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionedPath(t *testing.T) {
+	router := New()
+	router.Versioned(func(v *VersionRouter) {
+		v.V(1).GET("/users", func(c *Context) {
+			c.String(http.StatusOK, "v1 users")
+		})
+		v.V(2).GET("/users", func(c *Context) {
+			c.String(http.StatusOK, "v2 users")
+		})
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/v1/users")
+	assert.Equal(t, "v1 users", w.Body.String())
+	assert.Empty(t, w.Header().Get("Vary"))
+
+	w = PerformRequest(router, http.MethodGet, "/v2/users")
+	assert.Equal(t, "v2 users", w.Body.String())
+
+	w = PerformRequest(router, http.MethodGet, "/v3/users")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestVersionedHeader(t *testing.T) {
+	router := New()
+	router.Versioned(func(v *VersionRouter) {
+		v.V(1).GET("/users", func(c *Context) {
+			c.String(http.StatusOK, "v1 users")
+		})
+		v.V(2).GET("/users/:id", func(c *Context) {
+			c.String(http.StatusOK, "v2 user "+c.Param("id")+" "+c.FullPath())
+		})
+	})
+	router.GET("/health", func(c *Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/users", header{Key: VersionHeader, Value: "1"})
+	assert.Equal(t, "v1 users", w.Body.String())
+	assert.Equal(t, VersionHeader, w.Header().Get("Vary"))
+
+	w = PerformRequest(router, http.MethodGet, "/users/42", header{Key: VersionHeader, Value: " v2 "})
+	assert.Equal(t, "v2 user 42 /v2/users/:id", w.Body.String())
+
+	// unknown or invalid versions are routed as usual
+	w = PerformRequest(router, http.MethodGet, "/users", header{Key: VersionHeader, Value: "3"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = PerformRequest(router, http.MethodGet, "/health", header{Key: VersionHeader, Value: "latest"})
+	assert.Equal(t, "ok", w.Body.String())
+
+	// the response without the header varies on it too
+	w = PerformRequest(router, http.MethodGet, "/users")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, VersionHeader, w.Header().Get("Vary"))
+	w = PerformRequest(router, http.MethodGet, "/health")
+	assert.Equal(t, "ok", w.Body.String())
+	assert.Equal(t, VersionHeader, w.Header().Get("Vary"))
+
+	router = New()
+	router.Versioned(func(v *VersionRouter) {})
+	router.GET("/health", func(c *Context) {})
+	w = PerformRequest(router, http.MethodGet, "/health")
+	assert.Empty(t, w.Header().Get("Vary"))
+}
+
+func TestVersionedPathWinsOverHeader(t *testing.T) {
+	router := New()
+	router.Versioned(func(v *VersionRouter) {
+		v.V(1).GET("/users", func(c *Context) {
+			c.String(http.StatusOK, "v1 users")
+		})
+		v.V(2).GET("/users", func(c *Context) {
+			c.String(http.StatusOK, "v2 users")
+		})
+	})
+
+	w := PerformRequest(router, http.MethodGet, "/v1/users", header{Key: VersionHeader, Value: "2"})
+	assert.Equal(t, "v1 users", w.Body.String())
+	assert.Empty(t, w.Header().Get("Vary"))
+
+	// "/v01" is not the prefix of version 1, the header applies
+	w = PerformRequest(router, http.MethodGet, "/v01/users", header{Key: VersionHeader, Value: "2"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestVersionedSameGroup(t *testing.T) {
+	router := New()
+	var first, second *RouterGroup
+	router.Versioned(func(v *VersionRouter) {
+		first = v.V(1)
+	})
+	router.Versioned(func(v *VersionRouter) {
+		second = v.V(1)
+		assert.Panics(t, func() { v.V(-1) })
+	})
+	assert.Same(t, first, second)
+	assert.Equal(t, "/v1", first.BasePath())
+}